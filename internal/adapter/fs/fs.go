@@ -134,3 +134,15 @@ func (fs *FileStorage) Write(path string, content []byte) error {
 	_, err = f.Write(content)
 	return err
 }
+
+func (fs *FileStorage) Rename(oldPath string, newPath string) error {
+	err := os.MkdirAll(filepath.Dir(newPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(oldPath, newPath)
+}
+
+func (fs *FileStorage) Remove(path string) error {
+	return os.Remove(path)
+}
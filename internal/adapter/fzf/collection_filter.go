@@ -0,0 +1,86 @@
+package fzf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/zk-org/zk/internal/adapter/term"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/opt"
+)
+
+// CollectionFilter uses fzf to filter interactively a set of collections,
+// e.g. tags.
+type CollectionFilter struct {
+	opts     CollectionFilterOpts
+	terminal *term.Terminal
+}
+
+// CollectionFilterOpts holds the configuration for the fzf collections
+// filtering.
+type CollectionFilterOpts struct {
+	// Indicates whether the filtering is interactive. If not, fzf is bypassed.
+	Interactive bool
+	// Optionally provide additional arguments, taken from the config `fzf-options` property.
+	FzfOptions opt.String
+	// Absolute path to the notebook, used to preview the notes of a
+	// collection.
+	NotebookDir string
+}
+
+func NewCollectionFilter(opts CollectionFilterOpts, terminal *term.Terminal) *CollectionFilter {
+	return &CollectionFilter{
+		opts:     opts,
+		terminal: terminal,
+	}
+}
+
+// Apply filters the given collections with fzf.
+func (f *CollectionFilter) Apply(collections []core.Collection) ([]core.Collection, error) {
+	selection := make([]core.Collection, 0)
+
+	if !f.opts.Interactive || !f.terminal.IsInteractive() || len(collections) == 0 {
+		return collections, nil
+	}
+
+	previewCmd := opt.NullString
+	if f.opts.NotebookDir != "" {
+		zkBin, err := os.Executable()
+		if err != nil {
+			return selection, err
+		}
+		previewCmd = opt.NewString(fmt.Sprintf(`"%s" --notebook-dir "%s" list --quiet --no-pager --format oneline --tag {1}`, zkBin, f.opts.NotebookDir))
+	}
+
+	fzf, err := New(Opts{
+		Options:    f.opts.FzfOptions.OrString(defaultOptions),
+		PreviewCmd: previewCmd,
+		Padding:    linePadding,
+	})
+	if err != nil {
+		return selection, err
+	}
+
+	for _, collection := range collections {
+		fzf.Add([]string{
+			f.terminal.MustStyle(collection.Name, core.StyleTitle),
+			f.terminal.MustStyle("("+strconv.Itoa(collection.NoteCount)+")", core.StyleUnderstate),
+		})
+	}
+
+	selected, err := fzf.Selection()
+	if err != nil {
+		return selection, err
+	}
+
+	for _, s := range selected {
+		for _, collection := range collections {
+			if collection.Name == s[0] {
+				selection = append(selection, collection)
+				break
+			}
+		}
+	}
+	return selection, nil
+}
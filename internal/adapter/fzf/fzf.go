@@ -23,6 +23,11 @@ var (
 	exitNoMatch     = 1
 )
 
+// DefaultDelimiter is the delimiter used between fields when none is given.
+// \x01 is a convenient delimiter because not visible in the output and most
+// likely not part of the fields themselves.
+const DefaultDelimiter = "\x01"
+
 // Opts holds the options used to run fzf.
 type Opts struct {
 	// Preview command executed by fzf when hovering a line.
@@ -35,6 +40,12 @@ type Opts struct {
 	Delimiter string
 	// List of key bindings enabled in fzf.
 	Bindings []Binding
+	// Command run by fzf to reload the lines each time the query changes.
+	// The filtering is then performed by the command instead of fzf.
+	ReloadCmd opt.String
+	// Additional environment variables given to fzf and its commands, as
+	// `key=value`.
+	Env []string
 }
 
 // Binding represents a keyboard shortcut bound to an action in fzf.
@@ -45,6 +56,23 @@ type Binding struct {
 	Action string
 	// Description which will be displayed as a fzf header if not empty.
 	Description string
+	// When true, pressing the keys exits fzf and reports them with Fzf.Key,
+	// instead of running the Action.
+	Expect bool
+}
+
+// KeysMatch returns whether the key reported by fzf is one of the
+// comma-separated keys of a binding, e.g. `ctrl-d,alt-d`.
+func KeysMatch(keys string, key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, k := range strings.Split(keys, ",") {
+		if strings.EqualFold(strings.TrimSpace(k), key) {
+			return true
+		}
+	}
+	return false
 }
 
 // Fzf filters a set of fields using fzf.
@@ -57,6 +85,9 @@ type Fzf struct {
 	// Fields selection or error result.
 	err       error
 	selection [][]string
+	// Final query and pressed expected keys.
+	query string
+	key   string
 
 	done      chan bool
 	cmd       *exec.Cmd
@@ -69,10 +100,8 @@ type Fzf struct {
 // To show a preview of each line, provide a previewCmd which will be executed
 // by fzf.
 func New(opts Opts) (*Fzf, error) {
-	// \x01 is a convenient delimiter because not visible in the output and
-	// most likely not part of the fields themselves.
 	if opts.Delimiter == "" {
-		opts.Delimiter = "\x01"
+		opts.Delimiter = DefaultDelimiter
 	}
 
 	// Hard-coded fzf options that are required by zk.
@@ -90,11 +119,16 @@ func New(opts Opts) (*Fzf, error) {
 
 	header := ""
 	binds := []string{}
+	expect := []string{}
 	for _, binding := range opts.Bindings {
 		if binding.Description != "" {
 			header += binding.Keys + ": " + binding.Description + "\n"
 		}
-		binds = append(binds, binding.Keys+":"+binding.Action)
+		if binding.Expect {
+			expect = append(expect, binding.Keys)
+		} else {
+			binds = append(binds, binding.Keys+":"+binding.Action)
+		}
 	}
 
 	if header != "" {
@@ -103,6 +137,14 @@ func New(opts Opts) (*Fzf, error) {
 	if len(binds) > 0 {
 		args = append(args, "--bind", strings.Join(binds, ","))
 	}
+	if len(expect) > 0 {
+		args = append(args, "--expect", strings.Join(expect, ","))
+	}
+	if !opts.ReloadCmd.IsNull() {
+		// The reload action consumes the rest of the binding, so it needs
+		// its own --bind option.
+		args = append(args, "--disabled", "--print-query", "--bind", "change:reload:"+opts.ReloadCmd.String())
+	}
 
 	if !opts.PreviewCmd.IsNull() {
 		args = append(args, "--preview", opts.PreviewCmd.String())
@@ -115,6 +157,9 @@ func New(opts Opts) (*Fzf, error) {
 
 	cmd := exec.Command(fzfPath, args...)
 	cmd.Stderr = os.Stderr
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
 
 	pipe, err := cmd.StdinPipe()
 	if err != nil {
@@ -139,6 +184,9 @@ func New(opts Opts) (*Fzf, error) {
 		}()
 
 		output, err := cmd.Output()
+		// The query and the pressed key are printed even when nothing
+		// matches.
+		output = f.parseHeader(output)
 
 		if err != nil {
 			exitErr, ok := err.(*exec.ExitError)
@@ -158,6 +206,27 @@ func New(opts Opts) (*Fzf, error) {
 	return &f, nil
 }
 
+// parseHeader extracts the query and the pressed key printed by fzf before
+// the selection, when requested. It returns the remaining output.
+func (f *Fzf) parseHeader(output []byte) []byte {
+	readLine := func() string {
+		line, rest, _ := strings.Cut(string(output), "\n")
+		output = []byte(rest)
+		return line
+	}
+
+	if !f.opts.ReloadCmd.IsNull() {
+		f.query = readLine()
+	}
+	for _, binding := range f.opts.Bindings {
+		if binding.Expect {
+			f.key = readLine()
+			break
+		}
+	}
+	return output
+}
+
 // parseSelection extracts the fields from fzf's output.
 func (f *Fzf) parseSelection(output []byte) {
 	f.selection = make([][]string, 0)
@@ -174,13 +243,19 @@ func (f *Fzf) parseSelection(output []byte) {
 
 // Add appends a new line of fields to fzf input.
 func (f *Fzf) Add(fields []string) error {
+	return WriteLine(f.pipe, fields, f.opts.Delimiter, f.opts.Padding)
+}
+
+// WriteLine writes a line of fields formatted for fzf, e.g. when printing the
+// lines of a reload command.
+func WriteLine(w io.Writer, fields []string, delimiter string, padding int) error {
 	line := ""
 	for i, field := range fields {
 		if i > 0 {
-			line += f.opts.Delimiter
+			line += delimiter
 
-			if field != "" && f.opts.Padding > 0 {
-				line += strings.Repeat(" ", f.opts.Padding)
+			if field != "" && padding > 0 {
+				line += strings.Repeat(" ", padding)
 			}
 		}
 		line += field
@@ -189,7 +264,7 @@ func (f *Fzf) Add(fields []string) error {
 		return nil
 	}
 
-	_, err := fmt.Fprintln(f.pipe, line)
+	_, err := fmt.Fprintln(w, line)
 	return err
 }
 
@@ -200,6 +275,18 @@ func (f *Fzf) Selection() ([][]string, error) {
 	return f.selection, f.err
 }
 
+// Query returns the final query typed by the user, when a ReloadCmd is set.
+// It is only available after calling Selection.
+func (f *Fzf) Query() string {
+	return f.query
+}
+
+// Key returns the expected keys pressed by the user to exit fzf, or an empty
+// string. It is only available after calling Selection.
+func (f *Fzf) Key() string {
+	return f.key
+}
+
 func (f *Fzf) close() error {
 	var err error
 	f.closeOnce.Do(func() {
@@ -2,8 +2,10 @@ package fzf
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -44,6 +46,53 @@ type NoteFilterOpts struct {
 	NewNoteDir *core.Dir
 	// Absolute path to the notebook.
 	NotebookDir string
+	// Absolute path to the notebook of each filtered note, when they belong
+	// to several notebooks. Takes precedence over NotebookDir.
+	NotebookDirs []string
+	// Key bindings of the actions which can be triggered on the selected
+	// notes, indexed by the name of the action.
+	Actions map[string]string
+	// Command printing the lines of the notes matching the query appended to
+	// it, with PrintLines. When set, the notes are searched live as the query
+	// changes instead of being filtered by fzf.
+	ReloadCmd opt.String
+	// Environment variables given to the ReloadCmd, as `key=value`.
+	ReloadEnv []string
+	// Finds the notes matching the final query of a live search. Required
+	// with ReloadCmd.
+	Requery func(query string) ([]core.ContextualNote, error)
+}
+
+// NoteSelection holds the result of an interactive filtering.
+type NoteSelection struct {
+	// Notes available to the user, which differ from the filtered notes
+	// after a live search.
+	Notes []core.ContextualNote
+	// Indexes of the selected notes in Notes.
+	Indexes []int
+	// Name of the action triggered on the selection, or an empty string for
+	// the default one.
+	Action string
+}
+
+// Selected returns the selected notes.
+func (s NoteSelection) Selected() []core.ContextualNote {
+	notes := make([]core.ContextualNote, 0, len(s.Indexes))
+	for _, i := range s.Indexes {
+		notes = append(notes, s.Notes[i])
+	}
+	return notes
+}
+
+// NoteActions lists the names of the actions which can be bound to keys in
+// fzf, with their description.
+var NoteActions = map[string]string{
+	"open":      "open in the editor",
+	"link":      "print the links",
+	"copy-path": "copy the paths",
+	"delete":    "delete",
+	"tag":       "add tags",
+	"backlinks": "view the backlinks",
 }
 
 func NewNoteFilter(opts NoteFilterOpts, fs core.FileStorage, terminal *term.Terminal, templateLoader core.TemplateLoader) *NoteFilter {
@@ -57,32 +106,38 @@ func NewNoteFilter(opts NoteFilterOpts, fs core.FileStorage, terminal *term.Term
 
 // Apply filters the given notes with fzf.
 func (f *NoteFilter) Apply(notes []core.ContextualNote) ([]core.ContextualNote, error) {
-	selectedNotes := make([]core.ContextualNote, 0)
-	relPaths := []string{}
-	absPaths := []string{}
-
-	if !f.opts.Interactive || !f.terminal.IsInteractive() || (!f.opts.AlwaysFilter && len(notes) == 0) {
-		return notes, nil
+	selection, err := f.Select(notes)
+	if err != nil {
+		return []core.ContextualNote{}, err
 	}
+	return selection.Selected(), nil
+}
 
-	lineTemplate, err := f.templateLoader.LoadTemplate(f.opts.LineTemplate.OrString(defaultLineTemplate).String())
-	if err != nil {
-		return selectedNotes, err
+// ApplyIndexes filters the given notes with fzf and returns the indexes of the
+// selected ones.
+func (f *NoteFilter) ApplyIndexes(notes []core.ContextualNote) ([]int, error) {
+	selection, err := f.Select(notes)
+	return selection.Indexes, err
+}
+
+// Select filters the given notes with fzf and returns the selection, with the
+// action triggered by the user.
+func (f *NoteFilter) Select(notes []core.ContextualNote) (NoteSelection, error) {
+	selection := NoteSelection{
+		Notes:   notes,
+		Indexes: make([]int, 0),
 	}
 
-	for _, note := range notes {
-		absPath := filepath.Join(f.opts.NotebookDir, note.Path)
-		absPaths = append(absPaths, absPath)
-		if relPath, err := f.fs.Rel(absPath); err == nil {
-			relPaths = append(relPaths, relPath)
-		} else {
-			relPaths = append(relPaths, note.Path)
+	if !f.opts.Interactive || !f.terminal.IsInteractive() || (!f.opts.AlwaysFilter && len(notes) == 0) {
+		for i := range notes {
+			selection.Indexes = append(selection.Indexes, i)
 		}
+		return selection, nil
 	}
 
 	zkBin, err := os.Executable()
 	if err != nil {
-		return selectedNotes, err
+		return selection, err
 	}
 
 	bindings := []Binding{}
@@ -103,25 +158,115 @@ func (f *NoteFilter) Apply(notes []core.ContextualNote) ([]core.ContextualNote,
 		}
 	}
 
+	actions := make([]string, 0, len(f.opts.Actions))
+	for action := range f.opts.Actions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		bindings = append(bindings, Binding{
+			Keys:        f.opts.Actions[action],
+			Description: NoteActions[action],
+			Expect:      true,
+		})
+	}
+
 	previewCmd := f.opts.PreviewCmd.OrString("cat {-1}").Unwrap()
 
 	fzf, err := New(Opts{
 		Options:    f.opts.FzfOptions.OrString(defaultOptions),
 		PreviewCmd: opt.NewNotEmptyString(previewCmd),
-		Padding:    2,
+		Padding:    linePadding,
 		Bindings:   bindings,
+		ReloadCmd:  f.opts.ReloadCmd,
+		Env:        f.opts.ReloadEnv,
 	})
 	if err != nil {
-		return selectedNotes, err
+		return selection, err
+	}
+
+	lines, absPaths, err := f.lines(notes)
+	if err != nil {
+		return selection, err
+	}
+	for _, line := range lines {
+		fzf.Add(line)
+	}
+
+	selected, err := fzf.Selection()
+	if err != nil {
+		return selection, err
+	}
+
+	for action, keys := range f.opts.Actions {
+		if KeysMatch(keys, fzf.Key()) {
+			selection.Action = action
+		}
+	}
+
+	// After a live search, the selected notes might not be part of the
+	// initial ones.
+	if !f.opts.ReloadCmd.IsNull() && fzf.Query() != "" {
+		selection.Notes, err = f.opts.Requery(fzf.Query())
+		if err != nil {
+			return selection, err
+		}
+		absPaths = f.absPaths(selection.Notes)
+	}
+
+	for _, s := range selected {
+		path := s[len(s)-1]
+		for i := range selection.Notes {
+			if absPaths[i] == path {
+				selection.Indexes = append(selection.Indexes, i)
+			}
+		}
+	}
+
+	return selection, nil
+}
+
+// PrintLines writes the fzf lines of the given notes, for the ReloadCmd of a
+// live search.
+func (f *NoteFilter) PrintLines(w io.Writer, notes []core.ContextualNote) error {
+	lines, _, err := f.lines(notes)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if err := WriteLine(w, line, DefaultDelimiter, linePadding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linePadding is the amount of space between two fields of the notes lines.
+const linePadding = 2
+
+// lines returns the fields displayed by fzf for each note, with the absolute
+// paths of the notes.
+func (f *NoteFilter) lines(notes []core.ContextualNote) ([][]string, []string, error) {
+	lines := [][]string{}
+	absPaths := f.absPaths(notes)
+
+	lineTemplate, err := f.templateLoader.LoadTemplate(f.opts.LineTemplate.OrString(defaultLineTemplate).String())
+	if err != nil {
+		return lines, absPaths, err
 	}
 
 	for i, note := range notes {
+		relPath, err := f.fs.Rel(absPaths[i])
+		if err != nil {
+			relPath = note.Path
+		}
+
 		context := lineRenderContext{
 			Filename:     note.Filename(),
 			FilenameStem: note.FilenameStem(),
 			Path:         note.Path,
 			AbsPath:      absPaths[i],
-			RelPath:      relPaths[i],
+			RelPath:      relPath,
 			Title:        note.Title,
 			TitleOrPath:  note.Title,
 			Body:         stringsutil.JoinLines(note.Body),
@@ -139,30 +284,29 @@ func (f *NoteFilter) Apply(notes []core.ContextualNote) ([]core.ContextualNote,
 
 		line, err := lineTemplate.Render(context)
 		if err != nil {
-			return selectedNotes, err
+			return lines, absPaths, err
 		}
 
 		// The absolute path is appended at the end of the line to be used in
 		// the preview command.
 		absPathField := f.terminal.MustStyle(context.AbsPath, core.StyleUnderstate)
-		fzf.Add([]string{line, absPathField})
+		lines = append(lines, []string{line, absPathField})
 	}
 
-	selection, err := fzf.Selection()
-	if err != nil {
-		return selectedNotes, err
-	}
+	return lines, absPaths, nil
+}
 
-	for _, s := range selection {
-		path := s[len(s)-1]
-		for i, m := range notes {
-			if absPaths[i] == path {
-				selectedNotes = append(selectedNotes, m)
-			}
+// absPaths returns the absolute paths of the given notes.
+func (f *NoteFilter) absPaths(notes []core.ContextualNote) []string {
+	absPaths := make([]string, 0, len(notes))
+	for i, note := range notes {
+		notebookDir := f.opts.NotebookDir
+		if i < len(f.opts.NotebookDirs) {
+			notebookDir = f.opts.NotebookDirs[i]
 		}
+		absPaths = append(absPaths, filepath.Join(notebookDir, note.Path))
 	}
-
-	return selectedNotes, nil
+	return absPaths
 }
 
 var defaultLineTemplate = `{{style "title" title-or-path}} {{style "understate" body}} {{style "understate" (json metadata)}}`
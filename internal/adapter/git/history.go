@@ -0,0 +1,206 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/errors"
+	executil "github.com/zk-org/zk/internal/util/exec"
+)
+
+// History implements core.NoteHistory by calling the local git binary.
+type History struct {
+	dir    string
+	logger util.Logger
+}
+
+// NewHistory creates a History reading the revisions of the notebook at dir.
+func NewHistory(dir string, logger util.Logger) *History {
+	return &History{
+		dir:    dir,
+		logger: logger,
+	}
+}
+
+const (
+	// recordSeparator delimits the commits in the git log output.
+	recordSeparator = "\x1e"
+	// fieldSeparator delimits the fields of a commit in the git log output.
+	fieldSeparator = "\x1f"
+)
+
+// Dates implements core.NoteHistory.
+func (h *History) Dates(paths []string) (map[string]core.NoteDates, error) {
+	wrap := errors.Wrapper("git log")
+
+	dates := map[string]core.NoteDates{}
+	out, err := h.git(append([]string{"log", "--format=" + recordSeparator + "%aI", "--name-only", "--no-renames", "--relative", "--"}, pathspecs(paths)...)...)
+	if err != nil {
+		return dates, wrap(err)
+	}
+
+	// Commits are listed from the most recent one.
+	for _, commit := range strings.Split(out, recordSeparator) {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, lines[0])
+		if err != nil {
+			h.logger.Err(err)
+			continue
+		}
+		date = date.UTC()
+
+		for _, path := range lines[1:] {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			d, ok := dates[path]
+			if !ok {
+				d.Modified = date
+			}
+			d.Created = date
+			dates[path] = d
+		}
+	}
+
+	dirty, err := h.dirtyPaths(paths)
+	if err != nil {
+		return dates, errors.Wrap(err, "git status")
+	}
+	for _, path := range dirty {
+		if d, ok := dates[path]; ok {
+			d.Modified = time.Time{}
+			dates[path] = d
+		}
+	}
+
+	return dates, nil
+}
+
+// dirtyPaths returns the tracked files with uncommitted changes, relative to
+// the notebook root.
+func (h *History) dirtyPaths(paths []string) ([]string, error) {
+	prefix, err := h.git("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	out, err := h.git(append([]string{"status", "--porcelain=v1", "-z", "--untracked-files=no", "--"}, pathspecs(paths)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	dirty := []string{}
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if strings.ContainsAny(status, "RC") {
+			// The origin of a renamed or copied file follows.
+			i++
+		}
+		// Paths are relative to the root of the repository.
+		dirty = append(dirty, strings.TrimPrefix(path, prefix))
+	}
+	return dirty, nil
+}
+
+// Revisions implements core.NoteHistory.
+func (h *History) Revisions(path string) ([]core.NoteRevision, error) {
+	format := strings.Join([]string{"%H", "%an", "%ae", "%aI", "%s"}, fieldSeparator) + recordSeparator
+	out, err := h.git("log", "--follow", "--format="+format, "--", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "git log")
+	}
+
+	revisions := []core.NoteRevision{}
+	for _, commit := range strings.Split(out, recordSeparator) {
+		fields := strings.Split(strings.TrimSpace(commit), fieldSeparator)
+		if len(fields) != 5 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			h.logger.Err(err)
+			continue
+		}
+		revisions = append(revisions, core.NoteRevision{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    date.UTC(),
+			Message: fields[4],
+		})
+	}
+	return revisions, nil
+}
+
+// PathsChangedSince implements core.NoteHistory.
+func (h *History) PathsChangedSince(rev string) ([]string, error) {
+	// The revision is given by the user, so it must not be parsed as an
+	// option by git.
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("%s: invalid git revision", rev)
+	}
+	hash, err := h.git("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%s: unknown git revision", rev)
+	}
+
+	out, err := h.git("diff", "--name-only", "--no-renames", "--relative", strings.TrimSpace(hash), "--")
+	if err != nil {
+		return nil, errors.Wrap(err, "git diff")
+	}
+	return splitPaths(out), nil
+}
+
+// PathsModifiedBy implements core.NoteHistory.
+func (h *History) PathsModifiedBy(author string) ([]string, error) {
+	out, err := h.git("log", "--author="+author, "--format=", "--name-only", "--no-renames", "--relative")
+	if err != nil {
+		return nil, errors.Wrap(err, "git log")
+	}
+	return splitPaths(out), nil
+}
+
+// git runs a git command from the notebook directory and returns its
+// output.
+func (h *History) git(args ...string) (string, error) {
+	// Prevent git from escaping non-ASCII paths.
+	args = append([]string{"-c", "core.quotePath=false"}, args...)
+	out, err := executil.Output(h.dir, "git", args...)
+	return string(out), err
+}
+
+// pathspecs returns the git pathspecs matching the given paths, or the whole
+// notebook when nil.
+func pathspecs(paths []string) []string {
+	if paths == nil {
+		return []string{"."}
+	}
+	return paths
+}
+
+// splitPaths returns the unique non-empty paths listed in the given output.
+func splitPaths(out string) []string {
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, path := range strings.Split(out, "\n") {
+		path = strings.TrimSpace(path)
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
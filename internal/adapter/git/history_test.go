@@ -0,0 +1,135 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+// testRepo creates a git repository containing a notebook in the notes/
+// sub-directory, and returns the notebook path.
+func testRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "notes")
+	assert.Nil(t, os.MkdirAll(dir, 0755))
+
+	run := func(date string, author string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com",
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com",
+			"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date,
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	write := func(path string, content string) {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+	}
+
+	run("", "", "init", "-q")
+	write("notes/one.md", "# One\n")
+	write("notes/two.md", "# Two\n")
+	write("outside.md", "# Outside\n")
+	run("2021-03-04T10:00:00Z", "ada", "add", "-A")
+	run("2021-03-04T10:00:00Z", "ada", "commit", "-q", "-m", "Add notes")
+	write("notes/one.md", "# One\n\nUpdated\n")
+	run("2022-05-06T10:00:00Z", "bob", "commit", "-q", "-a", "-m", "Update one")
+	write("notes/two.md", "# Two\n\nDirty\n")
+	write("notes/three.md", "# Three\n")
+
+	return dir
+}
+
+func date(day string) time.Time {
+	d, _ := time.Parse("2006-01-02T15:04:05Z", day)
+	return d
+}
+
+func TestHistoryDates(t *testing.T) {
+	history := NewHistory(testRepo(t), &util.NullLogger)
+
+	dates, err := history.Dates(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, dates, map[string]core.NoteDates{
+		"one.md": {Created: date("2021-03-04T10:00:00Z"), Modified: date("2022-05-06T10:00:00Z")},
+		// Uncommitted changes don't have a modification date.
+		"two.md": {Created: date("2021-03-04T10:00:00Z")},
+	})
+
+	dates, err = history.Dates([]string{"one.md"})
+	assert.Nil(t, err)
+	assert.Equal(t, dates, map[string]core.NoteDates{
+		"one.md": {Created: date("2021-03-04T10:00:00Z"), Modified: date("2022-05-06T10:00:00Z")},
+	})
+}
+
+func TestHistoryRevisions(t *testing.T) {
+	history := NewHistory(testRepo(t), &util.NullLogger)
+
+	revisions, err := history.Revisions("one.md")
+	assert.Nil(t, err)
+	assert.Equal(t, len(revisions), 2)
+	assert.Equal(t, len(revisions[0].Hash), 40)
+	revisions[0].Hash = ""
+	revisions[1].Hash = ""
+	assert.Equal(t, revisions, []core.NoteRevision{
+		{Author: "bob", Email: "bob@example.com", Date: date("2022-05-06T10:00:00Z"), Message: "Update one"},
+		{Author: "ada", Email: "ada@example.com", Date: date("2021-03-04T10:00:00Z"), Message: "Add notes"},
+	})
+
+	revisions, err = history.Revisions("three.md")
+	assert.Nil(t, err)
+	assert.Equal(t, revisions, []core.NoteRevision{})
+}
+
+func TestHistoryPathsChangedSince(t *testing.T) {
+	history := NewHistory(testRepo(t), &util.NullLogger)
+
+	paths, err := history.PathsChangedSince("HEAD")
+	assert.Nil(t, err)
+	assert.Equal(t, paths, []string{"two.md"})
+
+	paths, err = history.PathsChangedSince("HEAD~1")
+	assert.Nil(t, err)
+	assert.Equal(t, paths, []string{"one.md", "two.md"})
+
+	_, err = history.PathsChangedSince("unknown")
+	assert.Err(t, err, "unknown: unknown git revision")
+
+	// Revisions can't be used to pass options to git.
+	output := filepath.Join(t.TempDir(), "output")
+	_, err = history.PathsChangedSince("--output=" + output)
+	assert.Err(t, err, "--output="+output+": invalid git revision")
+	_, err = os.Stat(output)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestHistoryPathsModifiedBy(t *testing.T) {
+	history := NewHistory(testRepo(t), &util.NullLogger)
+
+	paths, err := history.PathsModifiedBy("ada")
+	assert.Nil(t, err)
+	assert.Equal(t, paths, []string{"one.md", "two.md"})
+
+	paths, err = history.PathsModifiedBy("bob")
+	assert.Nil(t, err)
+	assert.Equal(t, paths, []string{"one.md"})
+
+	paths, err = history.PathsModifiedBy("eve")
+	assert.Nil(t, err)
+	assert.Equal(t, paths, []string{})
+}
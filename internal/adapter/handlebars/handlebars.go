@@ -3,7 +3,10 @@ package handlebars
 import (
 	"fmt"
 	"html"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aymerick/raymond"
 	"github.com/zk-org/zk/internal/adapter/handlebars/helpers"
@@ -14,6 +17,7 @@ import (
 )
 
 func Init(supportsUTF8 bool, logger util.Logger) {
+	helpers.RegisterCalendar(logger)
 	helpers.RegisterConcat()
 	helpers.RegisterDate(logger)
 	helpers.RegisterFormatDate(logger)
@@ -52,6 +56,8 @@ type Loader struct {
 	lookupPaths []string
 	styler      core.Styler
 	helpers     map[string]interface{}
+	// Partials found in the templates directories, loaded lazily.
+	partials map[string]string
 }
 
 type LoaderOpts struct {
@@ -91,7 +97,10 @@ func (l *Loader) LoadTemplate(content string) (core.Template, error) {
 	if err != nil {
 		return nil, wrap(err)
 	}
-	template = l.newTemplate(vendorTempl)
+	template, err = l.newTemplate(vendorTempl)
+	if err != nil {
+		return nil, wrap(err)
+	}
 	l.strings[content] = template
 	return template, nil
 }
@@ -116,7 +125,10 @@ func (l *Loader) LoadTemplateAt(path string) (core.Template, error) {
 	if err != nil {
 		return nil, wrap(err)
 	}
-	template = l.newTemplate(vendorTempl)
+	template, err = l.newTemplate(vendorTempl)
+	if err != nil {
+		return nil, wrap(err)
+	}
 	l.files[path] = template
 	return template, nil
 }
@@ -146,7 +158,63 @@ func (l *Loader) locateTemplate(path string) (string, bool) {
 	return path, false
 }
 
-func (l *Loader) newTemplate(vendorTempl *raymond.Template) *Template {
+func (l *Loader) newTemplate(vendorTempl *raymond.Template) (*Template, error) {
+	partials, err := l.loadPartials()
+	if err != nil {
+		return nil, err
+	}
+
 	vendorTempl.RegisterHelpers(l.helpers)
-	return &Template{vendorTempl, l.styler}
+	vendorTempl.RegisterHelpers(l.layoutHelpers())
+	vendorTempl.RegisterPartials(partials)
+	return &Template{vendorTempl, l.styler}, nil
+}
+
+// PartialsDir is the name of the subdirectory of the templates directories
+// holding the partials.
+const PartialsDir = "partials"
+
+// loadPartials reads the partials found in the `partials/` subdirectory of
+// the lookup paths. They are named after their path relative to this
+// directory, without extension, e.g. `header` or `journal/nav`.
+//
+// Partials found in the first lookup paths take precedence, like templates.
+func (l *Loader) loadPartials() (map[string]string, error) {
+	if l.partials != nil {
+		return l.partials, nil
+	}
+
+	partials := map[string]string{}
+	for i := len(l.lookupPaths) - 1; i >= 0; i-- {
+		dir := filepath.Join(l.lookupPaths[i], PartialsDir)
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir && os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			name = filepath.ToSlash(strings.TrimSuffix(name, filepath.Ext(name)))
+			partials[name] = string(content)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load partials")
+		}
+	}
+
+	l.partials = partials
+	return partials, nil
 }
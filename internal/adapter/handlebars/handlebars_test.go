@@ -95,6 +95,62 @@ func TestLookupPaths(t *testing.T) {
 	test("subdir/test3.tpl", "Test 3") // relative
 }
 
+func TestPartials(t *testing.T) {
+	global := t.TempDir()
+	notebook := t.TempDir()
+	paths.WriteString(filepath.Join(global, "partials/header.md"), "Global header\n")
+	paths.WriteString(filepath.Join(global, "partials/footer.md"), "Global footer\n")
+	paths.WriteString(filepath.Join(notebook, "partials/header.md"), "# {{title}}\n")
+	paths.WriteString(filepath.Join(notebook, "partials/journal/nav.md"), "<- {{prev}}\n")
+
+	sut := testLoader(LoaderOpts{LookupPaths: []string{notebook, global}})
+
+	tpl, err := sut.LoadTemplate("{{> header}}\n{{> journal/nav prev=\"yesterday\"}}\n{{> footer}}")
+	assert.Nil(t, err)
+	res, err := tpl.Render(map[string]interface{}{"title": "Hello"})
+	assert.Nil(t, err)
+	assert.Equal(t, res, "# Hello\n<- yesterday\nGlobal footer\n")
+}
+
+func TestLayoutInheritance(t *testing.T) {
+	dir := t.TempDir()
+	paths.WriteString(filepath.Join(dir, "partials/base.md"), `# {{#block "title"}}Untitled{{/block}}
+
+{{#block "body"}}Empty{{/block}}
+{{#block "footer"}}-- {{author}}{{/block}}`)
+	paths.WriteString(filepath.Join(dir, "partials/daily.md"), `{{#extend "base"}}
+{{#override "title"}}Daily{{/override}}
+{{#override "footer"}}-- daily{{/override}}
+{{/extend}}`)
+	paths.WriteString(filepath.Join(dir, "meeting.md"), `{{#extend "base"}}{{#override "body"}}Notes & actions{{/override}}{{/extend}}`)
+
+	sut := testLoader(LoaderOpts{LookupPaths: []string{dir}})
+	context := map[string]interface{}{"author": "Mickaël"}
+
+	test := func(template string, expected string) {
+		tpl, err := sut.LoadTemplate(template)
+		assert.Nil(t, err)
+		res, err := tpl.Render(context)
+		assert.Nil(t, err)
+		assert.Equal(t, res, expected)
+	}
+
+	// Blocks not overridden keep their default content.
+	test(`{{#extend "base"}}{{#override "title"}}{{author}}'s note{{/override}}{{/extend}}`,
+		"# Mickaël's note\n\nEmpty\n-- Mickaël")
+	// Layouts can extend other layouts, the most derived blocks win.
+	test(`{{#extend "daily"}}{{#override "title"}}Monday{{/override}}{{#override "body"}}Body{{/override}}{{/extend}}`,
+		"# Monday\n\nBody\n-- daily")
+	// Layouts can be template files.
+	test(`{{#extend "meeting.md"}}{{#override "footer"}}Bye{{/override}}{{/extend}}`,
+		"# Untitled\n\nNotes & actions\nBye")
+
+	tpl, err := sut.LoadTemplate(`{{#extend "unknown"}}{{/extend}}`)
+	assert.Nil(t, err)
+	_, err = tpl.Render(context)
+	assert.Err(t, err, "cannot find template at unknown")
+}
+
 func TestRenderString(t *testing.T) {
 	testString(t,
 		"Goodbye, {{name}}",
@@ -211,6 +267,88 @@ func TestLinkHelper(t *testing.T) {
 	assert.Equal(t, actual, "path/to note.md - An interesting subject")
 }
 
+func TestPluginHelper(t *testing.T) {
+	sut := testLoader(LoaderOpts{})
+
+	render := func(name string, params []interface{}, hash map[string]interface{}) (string, error) {
+		return fmt.Sprintf("%s %v %v", name, params, hash["unit"]), nil
+	}
+	sut.RegisterHelper("weather", helpers.NewPluginHelper("weather", 2, render, &util.NullLogger))
+
+	templ, err := sut.LoadTemplate(`{{weather "Paris" 3 unit="celsius"}}`)
+	assert.Nil(t, err)
+
+	actual, err := templ.Render(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, actual, "weather [Paris 3] celsius")
+}
+
+// notebookQuerier is a test double for helpers.NotebookQuerier.
+type notebookQuerier struct {
+	queries []string
+}
+
+func (q *notebookQuerier) FindNotes(query string, limit int) ([]interface{}, error) {
+	q.queries = append(q.queries, fmt.Sprintf("%s (%d)", query, limit))
+	if query == "empty" {
+		return []interface{}{}, nil
+	}
+	return []interface{}{
+		map[string]interface{}{"title": "Note 1", "link": "[[note1]]"},
+		map[string]interface{}{"title": "Note 2", "link": "[[note2]]"},
+	}, nil
+}
+
+func (q *notebookQuerier) FindNote(path string) (interface{}, error) {
+	q.queries = append(q.queries, "note "+path)
+	if path != "note1.md" {
+		return nil, nil
+	}
+	return map[string]interface{}{"title": "Note 1", "link": "[[note1]]"}, nil
+}
+
+func (q *notebookQuerier) FindTags() ([]core.Collection, error) {
+	return []core.Collection{
+		{Kind: core.CollectionKindTag, Name: "book", NoteCount: 2},
+		{Kind: core.CollectionKindTag, Name: "science", NoteCount: 1},
+	}, nil
+}
+
+func TestNotebookHelpers(t *testing.T) {
+	querier := &notebookQuerier{}
+	sut := testLoader(LoaderOpts{})
+	sut.RegisterHelper("notes", helpers.NewNotesHelper(querier, &util.NullLogger))
+	sut.RegisterHelper("backlinks", helpers.NewBacklinksHelper(querier, &util.NullLogger))
+	sut.RegisterHelper("note-link", helpers.NewNoteLinkHelper(querier, &util.NullLogger))
+	sut.RegisterHelper("tags", helpers.NewTagsHelper(querier, &util.NullLogger))
+
+	test := func(template string, context interface{}, expected string) {
+		templ, err := sut.LoadTemplate(template)
+		assert.Nil(t, err)
+		actual, err := templ.Render(context)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test(`{{#notes "tag:project" limit=5}}{{@index}}. {{title}} ({{../id}})
+{{/notes}}`, map[string]interface{}{"id": "a1"}, "0. Note 1 (a1)\n1. Note 2 (a1)\n")
+	test(`{{#notes "empty"}}{{title}}{{else}}No notes{{/notes}}`, nil, "No notes")
+	test(`{{backlinks "dir/a note.md"}}`, nil, "[[note1]]\n[[note2]]")
+	test(`{{note-link "note1.md"}}`, nil, "[[note1]]")
+	test(`{{note-link "note"}}`, nil, "")
+	test(`{{tags}}`, nil, "book, science")
+	// The tags of the current context take precedence.
+	test(`{{tags}}`, map[string]interface{}{"tags": []string{"a", "b"}}, "ab")
+
+	assert.Equal(t, querier.queries, []string{
+		"tag:project (5)",
+		"empty (0)",
+		"--link-to 'dir/a note.md' (0)",
+		"note note1.md",
+		"note note",
+	})
+}
+
 func TestSlugHelper(t *testing.T) {
 	// inline
 	testString(t,
@@ -355,6 +493,36 @@ func TestDateHelper(t *testing.T) {
 	testString(t, "{{format-date (date \"2009-11-17T20:34:58\") 'timestamp'}}", context, "200911172034")
 }
 
+func TestDateArithmeticHelpers(t *testing.T) {
+	// Wednesday
+	context := map[string]interface{}{"now": time.Date(2009, 11, 18, 14, 30, 0, 0, time.UTC)}
+	test := func(template string, expected string) {
+		testString(t, template, context, expected)
+	}
+
+	test(`{{format-date (date-add now "3 days") "%Y-%m-%d"}}`, "2009-11-21")
+	test(`{{format-date (date-add now "1 month 2 weeks") "%Y-%m-%d"}}`, "2010-01-01")
+	test(`{{format-date (date-add now "1h30m") "%H:%M"}}`, "16:00")
+	test(`{{format-date (date-sub now "1 year") "%Y-%m-%d"}}`, "2008-11-18")
+	test(`{{format-date (date-sub now "-2 days") "%Y-%m-%d"}}`, "2009-11-20")
+	test(`{{week-number now}}`, "47")
+	test(`{{week-number (date-add now "6 weeks")}}`, "53")
+
+	test(`{{format-date (start-of now "week") "%Y-%m-%d %H:%M"}}`, "2009-11-16 00:00")
+	test(`{{format-date (start-of now "week" week-start="sunday") "%Y-%m-%d"}}`, "2009-11-15")
+	test(`{{format-date (start-of now "month") "%Y-%m-%d"}}`, "2009-11-01")
+	test(`{{format-date (start-of now "year") "%Y-%m-%d"}}`, "2009-01-01")
+	test(`{{format-date (end-of now "week") "%Y-%m-%d %H:%M"}}`, "2009-11-22 23:59")
+	test(`{{format-date (end-of now "month") "%Y-%m-%d"}}`, "2009-11-30")
+	test(`{{format-date (end-of now "day") "%Y-%m-%d %H:%M"}}`, "2009-11-18 23:59")
+
+	test(`{{weekday now}} {{weekday (date-add now "1 day") format="short"}}`, "mercredi jeu.")
+
+	test(`{{#each-day (start-of now "week") (end-of now "week")}}{{#if @first}}{{else}}, {{/if}}{{weekday this format="short"}}{{/each-day}}`,
+		"lun., mar., mer., jeu., ven., sam., dim.")
+	test(`{{#each-day now (date-sub now "1 day")}}{{this}}{{else}}empty{{/each-day}}`, "empty")
+}
+
 func TestShellHelper(t *testing.T) {
 	// block is passed as piped input
 	testString(t,
@@ -394,6 +562,9 @@ func testLoader(opts LoaderOpts) *Loader {
 
 	loader.RegisterHelper("style", helpers.NewStyleHelper(opts.Styler, &util.NullLogger))
 	loader.RegisterHelper("slug", helpers.NewSlugHelper("en", &util.NullLogger))
+	loader.RegisterHelper("weekday", helpers.NewWeekdayHelper("fr-CA", &util.NullLogger))
+	loader.RegisterHelper("start-of", helpers.NewStartOfHelper(time.Monday, &util.NullLogger))
+	loader.RegisterHelper("end-of", helpers.NewEndOfHelper(time.Monday, &util.NullLogger))
 
 	formatter := func(context core.LinkFormatterContext) (string, error) {
 		return context.Path + " - " + context.Title, nil
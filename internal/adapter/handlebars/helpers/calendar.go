@@ -0,0 +1,286 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util"
+	dateutil "github.com/zk-org/zk/internal/util/date"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// RegisterCalendar registers the template helpers performing date arithmetic.
+//
+// {{date-add now "3 days"}}
+// {{date-sub now "1 month 2 weeks"}}
+// {{week-number now}} -> 46
+// {{#each-day (date "monday") (date "friday")}}{{format-date this "%A"}}{{/each-day}}
+func RegisterCalendar(logger util.Logger) {
+	raymond.RegisterHelper("date-add", func(date interface{}, duration string) time.Time {
+		res, err := addDuration(date, duration, 1)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "the {{date-add}} template helper failed"))
+		}
+		return res
+	})
+
+	raymond.RegisterHelper("date-sub", func(date interface{}, duration string) time.Time {
+		res, err := addDuration(date, duration, -1)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "the {{date-sub}} template helper failed"))
+		}
+		return res
+	})
+
+	raymond.RegisterHelper("week-number", func(date interface{}) int {
+		t, err := parseDate(date)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "the {{week-number}} template helper failed"))
+			return 0
+		}
+		_, week := t.ISOWeek()
+		return week
+	})
+
+	raymond.RegisterHelper("each-day", func(from interface{}, to interface{}, options *raymond.Options) raymond.SafeString {
+		start, err := parseDate(from)
+		if err == nil {
+			var end time.Time
+			end, err = parseDate(to)
+			if err == nil {
+				return eachDay(startOf(start, "day", time.Monday), startOf(end, "day", time.Monday), options)
+			}
+		}
+		logger.Err(errors.Wrap(err, "the {{each-day}} template helper failed"))
+		return ""
+	})
+}
+
+// NewStartOfHelper creates a new template helper returning the beginning of
+// the day, week, month or year containing a date. The first day of the week
+// can be overridden with the week-start argument.
+//
+// {{format-date (start-of now "week") "%Y-%m-%d"}} -> 2009-11-16
+// {{start-of now "week" week-start="sunday"}}
+func NewStartOfHelper(weekStart time.Weekday, logger util.Logger) interface{} {
+	return func(date interface{}, unit string, options *raymond.Options) time.Time {
+		t, ws, err := parseCalendarArgs(date, unit, weekStart, options)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "the {{start-of}} template helper failed"))
+			return time.Time{}
+		}
+		return startOf(t, unit, ws)
+	}
+}
+
+// NewEndOfHelper creates a new template helper returning the last instant of
+// the day, week, month or year containing a date. The first day of the week
+// can be overridden with the week-start argument.
+//
+// {{format-date (end-of now "month") "%Y-%m-%d"}} -> 2009-11-30
+func NewEndOfHelper(weekStart time.Weekday, logger util.Logger) interface{} {
+	return func(date interface{}, unit string, options *raymond.Options) time.Time {
+		t, ws, err := parseCalendarArgs(date, unit, weekStart, options)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "the {{end-of}} template helper failed"))
+			return time.Time{}
+		}
+		return addCalendarUnit(startOf(t, unit, ws), unit, 1).Add(-time.Nanosecond)
+	}
+}
+
+// NewWeekdayHelper creates a new template helper printing the name of the day
+// of the week of a date, in the given language. The abbreviated name is
+// printed with format="short".
+//
+// {{weekday now}} -> Tuesday
+// {{weekday now format="short"}} -> Tue
+func NewWeekdayHelper(lang string, logger util.Logger) interface{} {
+	names, ok := weekdayNames[languageCode(lang)]
+	if !ok {
+		names = weekdayNames["en"]
+	}
+
+	return func(date interface{}, options *raymond.Options) string {
+		t, err := parseDate(date)
+		if err != nil {
+			logger.Err(errors.Wrap(err, "the {{weekday}} template helper failed"))
+			return ""
+		}
+		if options.HashStr("format") == "short" {
+			return names.short[t.Weekday()]
+		}
+		return names.long[t.Weekday()]
+	}
+}
+
+// parseDate converts a helper argument to a date. Strings are parsed as
+// natural dates, e.g. "next monday".
+func parseDate(arg interface{}) (time.Time, error) {
+	switch date := arg.(type) {
+	case time.Time:
+		return date, nil
+	case string:
+		return dateutil.TimeFromNatural(date)
+	default:
+		return time.Time{}, fmt.Errorf("expected a date, received: %v", arg)
+	}
+}
+
+func parseCalendarArgs(date interface{}, unit string, weekStart time.Weekday, options *raymond.Options) (time.Time, time.Weekday, error) {
+	t, err := parseDate(date)
+	if err != nil {
+		return t, weekStart, err
+	}
+	if _, ok := calendarUnits[unit]; !ok {
+		return t, weekStart, fmt.Errorf("%s: unknown unit, expected day, week, month or year", unit)
+	}
+	if name := options.HashStr("week-start"); name != "" {
+		weekStart, err = dateutil.WeekdayFromString(name)
+	}
+	return t, weekStart, err
+}
+
+var calendarUnits = map[string]bool{"day": true, "week": true, "month": true, "year": true}
+
+// startOf returns the beginning of the calendar unit containing the date.
+func startOf(t time.Time, unit string, weekStart time.Weekday) time.Time {
+	year, month, day := t.Date()
+	switch unit {
+	case "week":
+		offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case "month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case "year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func addCalendarUnit(t time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "year":
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+var durationRegex = regexp.MustCompile(`^\s*([+-]?\d+)\s*([a-z]+?)s?\b\s*`)
+
+// addDuration adds sign times the given duration to the date. The duration is
+// either a Go duration (e.g. 1h30m) or a list of amounts of calendar units
+// (e.g. 1 year 2 months).
+func addDuration(date interface{}, duration string, sign int) (time.Time, error) {
+	t, err := parseDate(date)
+	if err != nil {
+		return t, err
+	}
+
+	if d, err := time.ParseDuration(duration); err == nil {
+		return t.Add(time.Duration(sign) * d), nil
+	}
+
+	rest := strings.ToLower(duration)
+	if strings.TrimSpace(rest) == "" {
+		return t, fmt.Errorf("empty duration")
+	}
+	for strings.TrimSpace(rest) != "" {
+		match := durationRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return t, fmt.Errorf("%s: invalid duration", duration)
+		}
+		rest = rest[len(match[0]):]
+
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return t, err
+		}
+		n *= sign
+
+		switch match[2] {
+		case "minute":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "hour":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "day", "week", "month", "year":
+			t = addCalendarUnit(t, match[2], n)
+		default:
+			return t, fmt.Errorf("%s: unknown duration unit %s", duration, match[2])
+		}
+	}
+	return t, nil
+}
+
+// eachDay renders the block for each day between start and end, inclusive.
+// The block is rendered with the date as context.
+func eachDay(start time.Time, end time.Time, options *raymond.Options) raymond.SafeString {
+	days := []time.Time{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		return raymond.SafeString(options.Inverse())
+	}
+
+	var res strings.Builder
+	for i, day := range days {
+		res.WriteString(options.FnCtxData(day, iterDataFrame(options, len(days), i)))
+	}
+	return raymond.SafeString(res.String())
+}
+
+// languageCode returns the ISO 639-1 code of a language tag, e.g. fr for fr-CA.
+func languageCode(lang string) string {
+	code, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	code, _, _ = strings.Cut(code, "_")
+	return code
+}
+
+type weekdays struct {
+	long  [7]string
+	short [7]string
+}
+
+// weekdayNames holds the localized names of the days of the week, starting
+// on Sunday.
+var weekdayNames = map[string]weekdays{
+	"en": {
+		long:  [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		short: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	},
+	"fr": {
+		long:  [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		short: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	"de": {
+		long:  [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		short: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	},
+	"es": {
+		long:  [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		short: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	"it": {
+		long:  [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		short: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	"pt": {
+		long:  [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		short: [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+	},
+	"nl": {
+		long:  [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		short: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+}
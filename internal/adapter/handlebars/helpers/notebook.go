@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/kballard/go-shellquote"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+)
+
+// NotebookQuerier gives the template helpers access to the content of the
+// current notebook.
+type NotebookQuerier interface {
+	// FindNotes returns the template variables of the notes matching the
+	// given `zk list` filtering arguments. A positive limit overrides the one
+	// from the query.
+	FindNotes(query string, limit int) ([]interface{}, error)
+	// FindNote returns the template variables of the note at the given path,
+	// or nil if there is none.
+	FindNote(path string) (interface{}, error)
+	// FindTags returns the tags used in the notebook.
+	FindTags() ([]core.Collection, error)
+}
+
+// linkTemplate renders the link of a note, in the inline form of the helpers.
+var linkTemplate = raymond.MustParse("{{{link}}}")
+
+// NewNotesHelper creates a new block helper iterating over the notes matching
+// a `zk list` query. The block is rendered with the same variables as the note
+// formatting templates. The {{else}} block is rendered when no notes are found.
+//
+// {{#notes "tag:project --sort modified-" limit=5}}- {{link}}{{/notes}}
+func NewNotesHelper(notebook NotebookQuerier, logger util.Logger) interface{} {
+	return func(query string, options *raymond.Options) raymond.SafeString {
+		limit, _ := options.HashProp("limit").(int)
+		notes, err := notebook.FindNotes(query, limit)
+		if err != nil {
+			logger.Err(err)
+			return ""
+		}
+		if len(notes) == 0 {
+			return raymond.SafeString(options.Inverse())
+		}
+
+		var res strings.Builder
+		for i, note := range notes {
+			res.WriteString(options.FnCtxData(note, iterDataFrame(options, len(notes), i)))
+		}
+		return raymond.SafeString(res.String())
+	}
+}
+
+// NewBacklinksHelper creates a new template helper printing the links to the
+// notes linking to the note at the given path, one per line.
+//
+// {{backlinks "path/to/note.md"}} -> [[other]]
+//
+//	[[another]]
+func NewBacklinksHelper(notebook NotebookQuerier, logger util.Logger) interface{} {
+	return func(path string, options *raymond.Options) raymond.SafeString {
+		limit, _ := options.HashProp("limit").(int)
+		notes, err := notebook.FindNotes(shellquote.Join("--link-to", path), limit)
+		if err != nil {
+			logger.Err(err)
+			return ""
+		}
+
+		links := make([]string, 0, len(notes))
+		for _, note := range notes {
+			links = append(links, renderLink(note, logger))
+		}
+		return raymond.SafeString(strings.Join(links, "\n"))
+	}
+}
+
+// NewNoteLinkHelper creates a new template helper generating a link to the
+// note at the given path, titled after the note.
+//
+// {{note-link "path/to/note.md"}} -> [[path/to/note]]
+func NewNoteLinkHelper(notebook NotebookQuerier, logger util.Logger) interface{} {
+	return func(path string) raymond.SafeString {
+		note, err := notebook.FindNote(path)
+		if err != nil {
+			logger.Err(err)
+			return ""
+		}
+		if note == nil {
+			logger.Printf("%s: note not found", path)
+			return ""
+		}
+		return raymond.SafeString(renderLink(note, logger))
+	}
+}
+
+// NewTagsHelper creates a new template helper listing the tags of the
+// notebook, separated by commas.
+//
+// {{tags}} -> book, history, science
+//
+// When the current context already has a `tags` variable, such as in the note
+// formatting templates, it takes precedence over the tags of the notebook.
+func NewTagsHelper(notebook NotebookQuerier, logger util.Logger) interface{} {
+	return func(options *raymond.Options) raymond.SafeString {
+		if tags := options.Value("tags"); tags != nil {
+			return raymond.SafeString(raymond.Str(tags))
+		}
+
+		tags, err := notebook.FindTags()
+		if err != nil {
+			logger.Err(err)
+			return ""
+		}
+
+		names := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		return raymond.SafeString(strings.Join(names, ", "))
+	}
+}
+
+// iterDataFrame returns the private variables of the i-th iteration of a
+// block, e.g. @index or @last.
+func iterDataFrame(options *raymond.Options, length int, i int) *raymond.DataFrame {
+	frame := options.NewDataFrame()
+	frame.Set("index", i)
+	frame.Set("first", i == 0)
+	frame.Set("last", i == length-1)
+	return frame
+}
+
+func renderLink(note interface{}, logger util.Logger) string {
+	link, err := linkTemplate.Exec(note)
+	if err != nil {
+		logger.Err(err)
+		return ""
+	}
+	return link
+}
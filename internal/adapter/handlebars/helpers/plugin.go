@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"reflect"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/util"
+)
+
+// PluginHelperFunc renders a template helper implemented by a plugin.
+type PluginHelperFunc func(name string, params []interface{}, hash map[string]interface{}) (string, error)
+
+// NewPluginHelper creates a new template helper delegating its rendering to a
+// plugin. The helper expects exactly the given number of positional
+// parameters, and accepts any hash arguments.
+//
+// {{weather "Paris" unit="celsius"}} -> 21°C
+func NewPluginHelper(name string, params int, render PluginHelperFunc, logger util.Logger) interface{} {
+	// Raymond checks the arity of the helpers, so the function is built with
+	// the expected number of parameters.
+	in := make([]reflect.Type, params+1)
+	for i := 0; i < params; i++ {
+		in[i] = reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+	in[params] = reflect.TypeOf(&raymond.Options{})
+	out := []reflect.Type{reflect.TypeOf("")}
+
+	fn := reflect.MakeFunc(reflect.FuncOf(in, out, false), func(args []reflect.Value) []reflect.Value {
+		options := args[params].Interface().(*raymond.Options)
+
+		res, err := render(name, options.Params(), options.Hash())
+		if err != nil {
+			logger.Err(err)
+		}
+		return []reflect.Value{reflect.ValueOf(res)}
+	})
+	return fn.Interface()
+}
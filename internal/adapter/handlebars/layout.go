@@ -0,0 +1,93 @@
+package handlebars
+
+import (
+	"fmt"
+
+	"github.com/aymerick/raymond"
+	"github.com/zk-org/zk/internal/core"
+)
+
+// blocksKey is the private variable holding the blocks overridden by the
+// templates extending a layout.
+const blocksKey = "zk-blocks"
+
+// layoutHelpers returns the template helpers used for the layout inheritance.
+//
+// A layout declares blocks with a default content:
+// {{#block "body"}}Default content{{/block}}
+//
+// A template extends a layout, which is either a partial or a template file,
+// and overrides some of its blocks. Anything outside the overridden blocks is
+// discarded.
+// {{#extend "layout"}}{{#override "body"}}Custom content{{/override}}{{/extend}}
+//
+// Layouts can extend other layouts. The blocks overridden by the most derived
+// template take precedence.
+func (l *Loader) layoutHelpers() map[string]interface{} {
+	return map[string]interface{}{
+		"extend": func(name string, options *raymond.Options) raymond.SafeString {
+			blocks := map[string]string{}
+			for name, content := range inheritedBlocks(options) {
+				blocks[name] = content
+			}
+			frame := options.NewDataFrame()
+			frame.Set(blocksKey, blocks)
+			options.FnData(frame)
+
+			layout, err := l.loadLayout(name)
+			if err != nil {
+				panic(err)
+			}
+			res, err := layout.template.ExecWith(options.Ctx(), frame)
+			if err != nil {
+				panic(fmt.Errorf("%s: %w", name, err))
+			}
+			return raymond.SafeString(res)
+		},
+
+		"override": func(name string, options *raymond.Options) string {
+			blocks := inheritedBlocks(options)
+			if blocks == nil {
+				panic(fmt.Errorf("{{#override \"%s\"}} must be used inside {{#extend}}", name))
+			}
+			if _, ok := blocks[name]; !ok {
+				blocks[name] = options.Fn()
+			}
+			return ""
+		},
+
+		"block": func(name string, options *raymond.Options) raymond.SafeString {
+			if content, ok := inheritedBlocks(options)[name]; ok {
+				return raymond.SafeString(content)
+			}
+			return raymond.SafeString(options.Fn())
+		},
+	}
+}
+
+// inheritedBlocks returns the blocks overridden by the templates extending the
+// current one, if any.
+func inheritedBlocks(options *raymond.Options) map[string]string {
+	blocks, _ := options.DataFrame().Get(blocksKey).(map[string]string)
+	return blocks
+}
+
+// loadLayout returns the layout with the given name, which is either a
+// partial or the path to a template file.
+func (l *Loader) loadLayout(name string) (*Template, error) {
+	partials, err := l.loadPartials()
+	if err != nil {
+		return nil, err
+	}
+
+	var template core.Template
+	if partial, ok := partials[name]; ok {
+		template, err = l.LoadTemplate(partial)
+	} else {
+		template, err = l.LoadTemplateAt(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return template.(*Template), nil
+}
@@ -0,0 +1,136 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+const cmdBacklinks = "zk.backlinks"
+
+func executeCommandBacklinks(notebook *core.Notebook, args []interface{}) (interface{}, error) {
+	path, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s expects a note path as first argument, got: %v", cmdBacklinks, args[0])
+	}
+
+	relPath, err := notebook.RelPath(path)
+	if err != nil {
+		return nil, err
+	}
+	note, err := notebook.FindByHref(relPath, false)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, fmt.Errorf("%s: note not found", path)
+	}
+
+	return findBacklinkLocations(notebook, *note)
+}
+
+// findBacklinkLocations returns the location of every link targeting the
+// given note.
+func findBacklinkLocations(notebook *core.Notebook, target core.MinimalNote) ([]protocol.Location, error) {
+	wrap := errors.Wrapperf("failed to find backlinks of %s", target.Path)
+
+	sources, err := notebook.FindNotes(core.NoteFindOpts{
+		LinkTo: &core.LinkFilter{Hrefs: []string{target.Path}},
+	})
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	locations := []protocol.Location{}
+	if len(sources) == 0 {
+		return locations, nil
+	}
+
+	ids := []core.NoteID{target.ID}
+	documents := map[core.NoteID]*document{}
+	for _, source := range sources {
+		ids = append(ids, source.ID)
+		documents[source.ID] = &document{
+			Path:    filepath.Join(notebook.Path, source.Path),
+			Content: source.RawContent,
+		}
+	}
+
+	links, err := notebook.FindLinksBetweenNotes(ids)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	found := map[protocol.Location]bool{}
+	for _, link := range links {
+		if link.TargetID != target.ID {
+			continue
+		}
+		doc, ok := documents[link.SourceID]
+		if !ok {
+			continue
+		}
+
+		location := protocol.Location{URI: pathToURI(doc.Path)}
+		for _, docLink := range backlinkCandidates(doc, link, notebook.Path) {
+			location.Range = docLink.Range
+			if !found[location] {
+				break
+			}
+		}
+		found[location] = true
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+// backlinkCandidates returns the links of the source document which may be
+// the given indexed link, the ones located in its snippet first. The
+// beginning of the snippet is used if the link can't be found.
+func backlinkCandidates(doc *document, link core.ResolvedLink, rootDir string) []documentLink {
+	snippetStart := doc.PositionAt(link.SnippetStart)
+	snippetEnd := doc.PositionAt(link.SnippetEnd)
+
+	inSnippet := []documentLink{}
+	others := []documentLink{}
+	docLinks, _ := doc.DocumentLinks()
+	for _, docLink := range docLinks {
+		if !isSameLink(docLink, link, rootDir) {
+			continue
+		}
+		if docLink.Range.Start.Line >= snippetStart.Line && docLink.Range.End.Line <= snippetEnd.Line {
+			inSnippet = append(inSnippet, docLink)
+		} else {
+			others = append(others, docLink)
+		}
+	}
+
+	fallback := documentLink{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: snippetStart.Line},
+			End:   protocol.Position{Line: snippetStart.Line},
+		},
+	}
+	return append(append(inSnippet, others...), fallback)
+}
+
+// isSameLink returns whether the link found in a document is the given
+// indexed link. The hrefs of Markdown links are indexed relative to the
+// notebook root.
+func isSameLink(docLink documentLink, link core.ResolvedLink, rootDir string) bool {
+	if docLink.IsWikiLink {
+		return link.Type == core.LinkTypeWikiLink && docLink.Href == link.Href
+	}
+	if link.Type != core.LinkTypeMarkdown {
+		return false
+	}
+	href, err := filepath.Rel(rootDir, filepath.Join(docLink.RelativeToDir, docLink.Href))
+	if err != nil {
+		return false
+	}
+	return filepath.ToSlash(href) == link.Href
+}
@@ -0,0 +1,52 @@
+package lsp
+
+import (
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestBacklinkCandidates(t *testing.T) {
+	content := `# Source
+
+Intro with a [[target]] link.
+
+A paragraph
+continued with [the target](../target.md) and [another](other.md).
+`
+	doc := &document{Path: "/notebook/dir/source.md", Content: content}
+
+	test := func(link core.ResolvedLink, expected []protocol.Range) {
+		candidates := backlinkCandidates(doc, link, "/notebook")
+		actual := []protocol.Range{}
+		for _, candidate := range candidates {
+			actual = append(actual, candidate.Range)
+		}
+		assert.Equal(t, actual, expected)
+	}
+
+	rangeOf := func(line, start, end protocol.UInteger) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: line, Character: start},
+			End:   protocol.Position{Line: line, Character: end},
+		}
+	}
+
+	// A wiki link, in its snippet.
+	test(core.ResolvedLink{
+		Link: core.Link{Href: "target", Type: core.LinkTypeWikiLink, SnippetStart: 10, SnippetEnd: 39},
+	}, []protocol.Range{rangeOf(2, 13, 23), rangeOf(2, 0, 0)})
+
+	// A Markdown link relative to the source note, on the second line of its
+	// snippet.
+	test(core.ResolvedLink{
+		Link: core.Link{Href: "target.md", Type: core.LinkTypeMarkdown, SnippetStart: 41, SnippetEnd: 121},
+	}, []protocol.Range{rangeOf(5, 15, 41), rangeOf(4, 0, 0)})
+
+	// A link which can't be found falls back on the start of its snippet.
+	test(core.ResolvedLink{
+		Link: core.Link{Href: "missing", Type: core.LinkTypeWikiLink, SnippetStart: 41, SnippetEnd: 121},
+	}, []protocol.Range{rangeOf(4, 0, 0)})
+}
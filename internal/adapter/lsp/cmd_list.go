@@ -71,6 +71,7 @@ type listSelection struct {
 	Created      bool
 	Modified     bool
 	Checksum     bool
+	Score        bool
 }
 
 func newListSelection(fields []string) listSelection {
@@ -90,6 +91,7 @@ func newListSelection(fields []string) listSelection {
 		Created:      strutil.Contains(fields, "created"),
 		Modified:     strutil.Contains(fields, "modified"),
 		Checksum:     strutil.Contains(fields, "checksum"),
+		Score:        strutil.Contains(fields, "score"),
 	}
 }
 
@@ -140,6 +142,9 @@ func newListNote(note core.ContextualNote, selection listSelection, basePath str
 	if selection.Checksum {
 		res.Checksum = note.Checksum
 	}
+	if selection.Score {
+		res.Score = note.Score
+	}
 	return res
 }
 
@@ -159,4 +164,5 @@ type listNote struct {
 	Created      *time.Time             `json:"created,omitempty"`
 	Modified     *time.Time             `json:"modified,omitempty"`
 	Checksum     string                 `json:"checksum,omitempty"`
+	Score        float64                `json:"score,omitempty"`
 }
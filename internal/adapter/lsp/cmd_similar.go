@@ -0,0 +1,107 @@
+package lsp
+
+import (
+	"fmt"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+const cmdSimilar = "zk.similar"
+
+type cmdSimilarOpts struct {
+	Path  string `json:"path"`
+	Limit int    `json:"limit"`
+	Pick  bool   `json:"pick"`
+}
+
+func executeCommandSimilar(logger util.Logger, notebook *core.Notebook, context *glsp.Context, args []interface{}) (interface{}, error) {
+	opts := cmdSimilarOpts{Limit: 10}
+	if len(args) > 1 {
+		arg, ok := args[1].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s expects a dictionary of options as second argument, got: %v", cmdSimilar, args[1])
+		}
+		err := unmarshalJSON(arg, &opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s args, got: %v", cmdSimilar, arg)
+		}
+	}
+
+	if opts.Path == "" {
+		return nil, errors.New("'path' not provided")
+	}
+	path, err := notebook.RelPath(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := notebook.FindNotes(core.NoteFindOpts{
+		SimilarTo: []string{path},
+		Limit:     opts.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	selection := newListSelection([]string{"path", "absPath", "title", "score"})
+	listNotes := []listNote{}
+	for _, note := range notes {
+		listNotes = append(listNotes, newListNote(note, selection, notebook.Path))
+	}
+
+	if opts.Pick {
+		go pickNote(logger, context, "Similar notes", listNotes)
+	}
+
+	return listNotes, nil
+}
+
+// pickNote asks the user to choose one of the given notes, and opens it in
+// the editor.
+func pickNote(logger util.Logger, context *glsp.Context, message string, notes []listNote) {
+	if len(notes) == 0 {
+		context.Notify(protocol.ServerWindowShowMessage, protocol.ShowMessageParams{
+			Type:    protocol.MessageTypeInfo,
+			Message: "No notes found",
+		})
+		return
+	}
+
+	actions := []protocol.MessageActionItem{}
+	paths := map[string]string{}
+	for _, note := range notes {
+		title := note.Title
+		if title == "" {
+			title = note.Path
+		}
+		if _, ok := paths[title]; ok {
+			title = fmt.Sprintf("%s (%s)", title, note.Path)
+		}
+		paths[title] = note.AbsPath
+		actions = append(actions, protocol.MessageActionItem{Title: title})
+	}
+
+	var picked *protocol.MessageActionItem
+	context.Call(protocol.ServerWindowShowMessageRequest, protocol.ShowMessageRequestParams{
+		Type:    protocol.MessageTypeInfo,
+		Message: message,
+		Actions: actions,
+	}, &picked)
+	if picked == nil {
+		return
+	}
+
+	absPath, ok := paths[picked.Title]
+	if !ok {
+		logger.Printf("%s: unknown note picked", picked.Title)
+		return
+	}
+	context.Call(protocol.ServerWindowShowDocument, protocol.ShowDocumentParams{
+		URI:       pathToURI(absPath),
+		TakeFocus: boolPtr(true),
+	}, nil)
+}
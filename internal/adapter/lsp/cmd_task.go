@@ -0,0 +1,77 @@
+package lsp
+
+import (
+	"fmt"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+const cmdTaskToggle = "zk.task.toggle"
+
+type cmdTaskToggleOpts struct {
+	Location *protocol.Location `json:"location"`
+}
+
+func executeCommandTaskToggle(documents *documentStore, parseStructure func(doc *document) (*markdown.Structure, error), context *glsp.Context, args []interface{}) (interface{}, error) {
+	var opts cmdTaskToggleOpts
+	if len(args) > 1 {
+		arg, ok := args[1].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s expects a dictionary of options as second argument, got: %v", cmdTaskToggle, args[1])
+		}
+		err := unmarshalJSON(arg, &opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s args, got: %v", cmdTaskToggle, arg)
+		}
+	}
+
+	if opts.Location == nil {
+		return nil, errors.New("'location' not provided")
+	}
+
+	doc, ok := documents.Get(opts.Location.URI)
+	if !ok {
+		return nil, fmt.Errorf("cannot toggle a task in '%s'", opts.Location.URI)
+	}
+
+	structure, err := parseStructure(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	line := opts.Location.Range.Start.Line
+	checkbox, done, ok := doc.TaskCheckboxAt(structure, int(line))
+	if !ok {
+		return nil, fmt.Errorf("no task found at line %d", line+1)
+	}
+
+	newText := "x"
+	if done {
+		newText = " "
+	}
+
+	go context.Call(protocol.ServerWorkspaceApplyEdit, protocol.ApplyWorkspaceEditParams{
+		Edit: protocol.WorkspaceEdit{
+			Changes: map[string][]protocol.TextEdit{
+				opts.Location.URI: {{Range: checkbox, NewText: newText}},
+			},
+		},
+	}, nil)
+
+	return !done, nil
+}
+
+// TaskCheckboxAt returns the range of the mark in the checkbox of the task
+// found at the given line, and whether the task is checked.
+func (d *document) TaskCheckboxAt(structure *markdown.Structure, index int) (protocol.Range, bool, bool) {
+	for _, task := range structure.Tasks {
+		start := d.PositionAt(task.Start)
+		if int(start.Line) == index {
+			return protocol.Range{Start: start, End: d.PositionAt(task.End)}, task.Done, true
+		}
+	}
+	return protocol.Range{}, false, false
+}
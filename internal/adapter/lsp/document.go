@@ -9,6 +9,7 @@ import (
 
 	"github.com/tliron/glsp"
 	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/adapter/markdown"
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
 	"github.com/zk-org/zk/internal/util/errors"
@@ -64,6 +65,15 @@ func (s *documentStore) Get(pathOrURI string) (*document, bool) {
 	return d, ok
 }
 
+// All returns the opened documents.
+func (s *documentStore) All() []*document {
+	docs := make([]*document, 0, len(s.documents))
+	for _, doc := range s.documents {
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
 func (s *documentStore) normalizePath(pathOrUri string) (string, error) {
 	path, err := uriToPath(pathOrUri)
 	if err != nil {
@@ -129,6 +139,18 @@ func (d *document) GetLines() []string {
 	return d.lines
 }
 
+// PositionAt converts a byte offset in the document content to an LSP
+// position.
+func (d *document) PositionAt(offset int) protocol.Position {
+	offset = max(0, min(offset, len(d.Content)))
+	before := d.Content[:offset]
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return protocol.Position{
+		Line:      protocol.UInteger(strings.Count(before, "\n")),
+		Character: protocol.UInteger(len(utf16.Encode([]rune(before[lineStart:])))),
+	}
+}
+
 // LookBehind returns the n characters before the given position, on the same line.
 func (d *document) LookBehind(pos protocol.Position, length int) string {
 	line, ok := d.GetLine(int(pos.Line))
@@ -173,6 +195,16 @@ func (d *document) LinkFromRoot(nb *core.Notebook) (*documentLink, error) {
 	}, nil
 }
 
+// TitleLine returns the index of the line holding the title of the note,
+// either in the YAML frontmatter or as a heading, according to the given
+// document structure. Defaults to the first line.
+func (d *document) TitleLine(structure *markdown.Structure) protocol.UInteger {
+	if structure == nil || structure.Title == nil {
+		return 0
+	}
+	return d.PositionAt(structure.Title.Start).Line
+}
+
 // DocumentLinkAt returns the internal or external link found in the document
 // at the given position.
 func (d *document) DocumentLinkAt(pos protocol.Position) (*documentLink, error) {
@@ -271,8 +303,20 @@ func isLineWithinCodeBlock(lines []string, lineIndex int, line string) bool {
 }
 
 // DocumentLinks returns all the internal and external links found in the
-// document.
+// document, except the embedded images.
 func (d *document) DocumentLinks() ([]documentLink, error) {
+	return d.findLinks(false)
+}
+
+// ImageLinks returns the embedded images found in the document, e.g.
+// ![title](file.png).
+func (d *document) ImageLinks() ([]documentLink, error) {
+	return d.findLinks(true)
+}
+
+// findLinks returns either the links or the embedded images found in the
+// document.
+func (d *document) findLinks(images bool) ([]documentLink, error) {
 	links := []documentLink{}
 
 	lines := d.GetLines()
@@ -318,8 +362,9 @@ func (d *document) DocumentLinks() ([]documentLink, error) {
 				continue
 			}
 
-			// Ignore embedded images ![title](file.png)
-			if match[0] > 0 && line[match[0]-1] == '!' {
+			// Embedded images ![title](file.png) are not links to notes.
+			isImage := match[0] > 0 && line[match[0]-1] == '!'
+			if isImage != images {
 				continue
 			}
 
@@ -344,7 +389,7 @@ func (d *document) DocumentLinks() ([]documentLink, error) {
 
 		for _, match := range wikiLinkRegex.FindAllStringSubmatchIndex(line, -1) {
 			// Ignore when inside backticks: `[[filename]]`
-			if linkWithinInlineCode(line, match[0], match[1], insideInline) {
+			if images || linkWithinInlineCode(line, match[0], match[1], insideInline) {
 				continue
 			}
 			href := line[match[2]:match[3]]
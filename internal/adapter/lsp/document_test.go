@@ -3,6 +3,8 @@ package lsp
 import (
 	"testing"
 
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/util"
 	"github.com/zk-org/zk/internal/util/test/assert"
 )
 
@@ -81,6 +83,17 @@ func TestDocumentLinks_EscapedBackticks(t *testing.T) {
 	}
 }
 
+func TestDocumentImageLinks(t *testing.T) {
+	doc := &document{Content: "An ![image](img/cat%20photo.png), a [document](doc.pdf#page=2) and [[a-note]]"}
+	assert.Equal(t, extractHrefs(doc), []string{"doc.pdf#page=2", "a-note"})
+
+	images, err := doc.ImageLinks()
+	assert.Nil(t, err)
+	assert.Equal(t, len(images), 1)
+	assert.Equal(t, images[0].Href, "img/cat photo.png")
+	assert.Equal(t, images[0].Range.Start.Character, uint32(4))
+}
+
 func TestLinkWithinInlineCode_EscapedBackticks(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -123,3 +136,80 @@ func TestLinkWithinInlineCode_EscapedBackticks(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentTitleLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected uint32
+	}{
+		{
+			name:     "no title",
+			content:  "Some content\nwithout heading",
+			expected: 0,
+		},
+		{
+			name:     "highest heading",
+			content:  "Intro\n\n## Sub\n# Title",
+			expected: 3,
+		},
+		{
+			name:     "frontmatter title",
+			content:  "---\nid: a1b2\nTitle: A note\n---\n\n# Heading",
+			expected: 2,
+		},
+		{
+			name:     "frontmatter without title",
+			content:  "---\nid: a1b2\n---\n\n# Heading",
+			expected: 4,
+		},
+		{
+			name:     "heading in a code block",
+			content:  "```\n# Comment\n```\n\n## Title",
+			expected: 4,
+		},
+		{
+			name:     "first heading of the highest level",
+			content:  "### Deep\n\n## Title\n\n## Other",
+			expected: 2,
+		},
+	}
+
+	parser := markdown.NewParser(markdown.ParserOpts{}, &util.NullLogger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &document{Content: tt.content}
+			structure, err := parser.ParseStructure(tt.content)
+			assert.Nil(t, err)
+			assert.Equal(t, doc.TitleLine(structure), tt.expected)
+		})
+	}
+}
+
+func TestDocumentTaskCheckboxAt(t *testing.T) {
+	doc := &document{Content: "# Tasks\n\n- [ ] Open\n* [x] Done\n  1. [X] Nested\n- Not a task\n\n```\n- [ ] In code\n```\n\n- Parent\n\n    - [ ] Nested after a blank line"}
+	parser := markdown.NewParser(markdown.ParserOpts{}, &util.NullLogger)
+	structure, err := parser.ParseStructure(doc.Content)
+	assert.Nil(t, err)
+
+	test := func(line int, expectedChar uint32, expectedDone bool, expectedOk bool) {
+		rng, done, ok := doc.TaskCheckboxAt(structure, line)
+		assert.Equal(t, ok, expectedOk)
+		assert.Equal(t, done, expectedDone)
+		if ok {
+			assert.Equal(t, rng.Start.Line, uint32(line))
+			assert.Equal(t, rng.Start.Character, expectedChar)
+			assert.Equal(t, rng.End.Character, expectedChar+1)
+		}
+	}
+
+	test(0, 0, false, false)
+	test(2, 3, false, true)
+	test(3, 3, true, true)
+	test(4, 6, true, true)
+	test(5, 0, false, false)
+	test(8, 0, false, false)
+	test(13, 7, false, true)
+	test(42, 0, false, false)
+}
@@ -0,0 +1,105 @@
+package lsp
+
+import (
+	"encoding/json"
+
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// GLSP only implements the version 3.16 of the LSP specification. This file
+// adds the few parts of the 3.17 specification used by zk.
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/
+
+const methodTextDocumentInlayHint = "textDocument/inlayHint"
+
+// InlayHintKind is the kind of an inlay hint.
+type InlayHintKind protocol.UInteger
+
+const (
+	// InlayHintKindType is an inlay hint for a type annotation.
+	InlayHintKindType InlayHintKind = 1
+	// InlayHintKindParameter is an inlay hint for a parameter.
+	InlayHintKindParameter InlayHintKind = 2
+)
+
+// InlayHintParams is the parameter of a textDocument/inlayHint request.
+type InlayHintParams struct {
+	protocol.WorkDoneProgressParams
+
+	// The text document.
+	TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
+
+	// The visible document range for which inlay hints should be computed.
+	Range protocol.Range `json:"range"`
+}
+
+// InlayHint is an inline annotation displayed by the editor in the source
+// code.
+type InlayHint struct {
+	// The position of this hint.
+	Position protocol.Position `json:"position"`
+
+	// The label of this hint.
+	Label string `json:"label"`
+
+	// The kind of this hint.
+	Kind *InlayHintKind `json:"kind,omitempty"`
+
+	// The tooltip text when you hover over this item.
+	Tooltip *string `json:"tooltip,omitempty"`
+
+	// Render padding before the hint.
+	PaddingLeft *bool `json:"paddingLeft,omitempty"`
+
+	// Render padding after the hint.
+	PaddingRight *bool `json:"paddingRight,omitempty"`
+}
+
+type textDocumentInlayHintFunc func(context *glsp.Context, params *InlayHintParams) ([]InlayHint, error)
+
+// handler extends the GLSP handler with requests from the 3.17
+// specification.
+type handler struct {
+	protocol.Handler
+
+	TextDocumentInlayHint textDocumentInlayHintFunc
+}
+
+// Handle implements glsp.Handler.
+func (h *handler) Handle(context *glsp.Context) (r interface{}, validMethod bool, validParams bool, err error) {
+	switch context.Method {
+	case methodTextDocumentInlayHint:
+		if !h.IsInitialized() {
+			return nil, true, true, errors.New("server not initialized")
+		}
+		if h.TextDocumentInlayHint != nil {
+			validMethod = true
+			var params InlayHintParams
+			if err = json.Unmarshal(context.Params, &params); err == nil {
+				validParams = true
+				r, err = h.TextDocumentInlayHint(context, &params)
+			}
+		}
+		return
+
+	default:
+		return h.Handler.Handle(context)
+	}
+}
+
+// serverCapabilities adds the 3.17 capabilities to the ones supported by
+// GLSP.
+type serverCapabilities struct {
+	protocol.ServerCapabilities
+
+	InlayHintProvider interface{} `json:"inlayHintProvider,omitempty"` // nil | bool | InlayHintOptions
+}
+
+// initializeResult is the same as protocol.InitializeResult, with the 3.17
+// server capabilities.
+type initializeResult struct {
+	Capabilities serverCapabilities                   `json:"capabilities"`
+	ServerInfo   *protocol.InitializeResultServerInfo `json:"serverInfo,omitempty"`
+}
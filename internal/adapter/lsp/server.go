@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf16"
 
 	"github.com/tliron/glsp"
 	protocol "github.com/tliron/glsp/protocol_3_16"
@@ -31,6 +33,11 @@ type Server struct {
 	fs                     core.FileStorage
 	logger                 util.Logger
 	useAdditionalTextEdits opt.Bool
+
+	// Note file extensions for which file watchers were registered in the
+	// client.
+	watchedExtensions     map[string]bool
+	watchedExtensionsLock sync.Mutex
 }
 
 // ServerOpts holds the options to create a new Server.
@@ -52,7 +59,7 @@ func NewServer(opts ServerOpts) *Server {
 		logging.Configure(10, opts.LogFile.Value)
 	}
 
-	handler := protocol.Handler{}
+	handler := handler{}
 	glspServer := glspserv.NewServer(&handler, opts.Name, debug)
 
 	// Redirect zk's logger to GLSP's to avoid breaking the JSON-RPC protocol
@@ -69,6 +76,7 @@ func NewServer(opts ServerOpts) *Server {
 		fs:                     fs,
 		logger:                 opts.Logger,
 		useAdditionalTextEdits: opt.NullBool,
+		watchedExtensions:      map[string]bool{},
 	}
 
 	var clientCapabilities protocol.ClientCapabilities
@@ -114,6 +122,9 @@ func NewServer(opts ServerOpts) *Server {
 				cmdNew,
 				cmdList,
 				cmdTagList,
+				cmdBacklinks,
+				cmdTaskToggle,
+				cmdSimilar,
 			},
 		}
 		capabilities.CompletionProvider = &protocol.CompletionOptions{
@@ -123,8 +134,19 @@ func NewServer(opts ServerOpts) *Server {
 
 		capabilities.ReferencesProvider = &protocol.ReferenceOptions{}
 
-		return protocol.InitializeResult{
-			Capabilities: capabilities,
+		capabilities.SemanticTokensProvider = &protocol.SemanticTokensOptions{
+			Legend: protocol.SemanticTokensLegend{
+				TokenTypes:     semanticTokenTypes,
+				TokenModifiers: semanticTokenModifiers,
+			},
+			Full: true,
+		}
+
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				ServerCapabilities: capabilities,
+				InlayHintProvider:  true,
+			},
 			ServerInfo: &protocol.InitializeResultServerInfo{
 				Name:    opts.Name,
 				Version: &opts.Version,
@@ -153,6 +175,15 @@ func NewServer(opts ServerOpts) *Server {
 		}
 		if doc != nil {
 			server.refreshDiagnosticsOfDocument(doc, context.Notify, false)
+
+			if watchedFiles := clientCapabilities.Workspace; watchedFiles != nil &&
+				watchedFiles.DidChangeWatchedFiles != nil &&
+				isTrue(watchedFiles.DidChangeWatchedFiles.DynamicRegistration) {
+
+				if notebook, err := server.notebookOf(doc); err == nil {
+					server.registerFileWatchers(context, notebook)
+				}
+			}
 		}
 		return nil
 	}
@@ -190,6 +221,42 @@ func NewServer(opts ServerOpts) *Server {
 		return nil
 	}
 
+	handler.WorkspaceDidChangeWatchedFiles = func(context *glsp.Context, params *protocol.DidChangeWatchedFilesParams) error {
+		// Group the changed files by notebook.
+		notebooks := []*core.Notebook{}
+		changes := map[*core.Notebook][]string{}
+		for _, change := range params.Changes {
+			path, err := uriToPath(change.URI)
+			if err != nil {
+				server.logger.Err(err)
+				continue
+			}
+			path = fs.Canonical(path)
+
+			notebook, err := server.notebooks.Open(path)
+			if err != nil {
+				// Not in a notebook.
+				continue
+			}
+			if _, ok := changes[notebook]; !ok {
+				notebooks = append(notebooks, notebook)
+			}
+			changes[notebook] = append(changes[notebook], path)
+		}
+
+		for _, notebook := range notebooks {
+			paths := changes[notebook]
+			_, err := notebook.IndexPaths(paths)
+			if err != nil {
+				server.logger.Err(err)
+				continue
+			}
+			server.refreshDiagnosticsOfLinkingDocuments(notebook, paths, context.Notify)
+		}
+
+		return nil
+	}
+
 	handler.TextDocumentCompletion = func(context *glsp.Context, params *protocol.CompletionParams) (interface{}, error) {
 		doc, ok := server.documents.Get(params.TextDocument.URI)
 		if !ok {
@@ -291,6 +358,10 @@ func NewServer(opts ServerOpts) *Server {
 				targetNote, err := server.noteForLink(link, notebook)
 				if targetNote != nil && err == nil {
 					target = targetNote.URI
+				} else if path, ok := server.assetForLink(link, notebook); ok {
+					if exists, _ := server.fs.FileExists(path); exists {
+						target = pathToURI(path)
+					}
 				}
 			}
 
@@ -302,6 +373,22 @@ func NewServer(opts ServerOpts) *Server {
 			}
 		}
 
+		images, err := doc.ImageLinks()
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range images {
+			if path, ok := server.assetForLink(link, notebook); ok {
+				if exists, _ := server.fs.FileExists(path); exists {
+					target := pathToURI(path)
+					documentLinks = append(documentLinks, protocol.DocumentLink{
+						Range:  link.Range,
+						Target: &target,
+					})
+				}
+			}
+		}
+
 		return documentLinks, err
 	}
 
@@ -388,6 +475,23 @@ func NewServer(opts ServerOpts) *Server {
 			}
 			return executeCommandTagList(server.logger, nb, params.Arguments)
 
+		case cmdBacklinks:
+			nb, err := openNotebook()
+			if err != nil {
+				return nil, err
+			}
+			return executeCommandBacklinks(nb, params.Arguments)
+
+		case cmdTaskToggle:
+			return executeCommandTaskToggle(server.documents, server.documentStructure, context, params.Arguments)
+
+		case cmdSimilar:
+			nb, err := openNotebook()
+			if err != nil {
+				return nil, err
+			}
+			return executeCommandSimilar(server.logger, nb, context, params.Arguments)
+
 		default:
 			return nil, fmt.Errorf("unknown zk LSP command: %s", params.Command)
 		}
@@ -405,6 +509,45 @@ func NewServer(opts ServerOpts) *Server {
 		missingBacklinkActions := server.getMissingBacklinkCodeActions(doc, params.TextDocument.URI, params.Range)
 		actions = append(actions, missingBacklinkActions...)
 
+		structure, err := server.documentStructure(doc)
+		if err != nil {
+			server.logger.Err(err)
+		} else if _, done, ok := doc.TaskCheckboxAt(structure, int(params.Range.Start.Line)); ok {
+			title := "Check task"
+			if done {
+				title = "Uncheck task"
+			}
+			actions = append(actions, protocol.CodeAction{
+				Title: title,
+				Kind:  stringPtr(protocol.CodeActionKindRefactorRewrite),
+				Command: &protocol.Command{
+					Title:   title,
+					Command: cmdTaskToggle,
+					Arguments: []interface{}{wd, map[string]interface{}{
+						"location": protocol.Location{
+							URI:   params.TextDocument.URI,
+							Range: params.Range,
+						},
+					}},
+				},
+			})
+		}
+
+		if isCodeActionKindRequested(params.Context.Only, protocol.CodeActionKindSource) && server.isIndexedNote(doc) {
+			actions = append(actions, protocol.CodeAction{
+				Title: "Show similar notes",
+				Kind:  stringPtr(protocol.CodeActionKindSource),
+				Command: &protocol.Command{
+					Title:   "Show similar notes",
+					Command: cmdSimilar,
+					Arguments: []interface{}{wd, map[string]interface{}{
+						"path": doc.Path,
+						"pick": true,
+					}},
+				},
+			})
+		}
+
 		// Only add "New note" actions if range is not empty.
 		if !isRangeEmpty(params.Range) {
 			addAction := func(dir string, actionTitle string) error {
@@ -509,6 +652,188 @@ func NewServer(opts ServerOpts) *Server {
 		return locations, nil
 	}
 
+	handler.TextDocumentCodeLens = func(context *glsp.Context, params *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+
+		notebook, err := server.notebookOf(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		relPath, err := notebook.RelPath(doc.Path)
+		if err != nil {
+			return nil, err
+		}
+		note, err := notebook.FindByHref(relPath, false)
+		if note == nil || err != nil {
+			return nil, err
+		}
+
+		backlinks, err := findBacklinkLocations(notebook, *note)
+		if err != nil {
+			return nil, err
+		}
+
+		structure, err := server.parseStructure(notebook, doc)
+		if err != nil {
+			return nil, err
+		}
+		line := doc.TitleLine(structure)
+		count := len(backlinks)
+		return []protocol.CodeLens{
+			{
+				Range: protocol.Range{
+					Start: protocol.Position{Line: line, Character: 0},
+					End:   protocol.Position{Line: line, Character: 0},
+				},
+				Command: &protocol.Command{
+					Title:     fmt.Sprintf("%d %s", count, strutil.Pluralize("backlink", count)),
+					Command:   cmdBacklinks,
+					Arguments: []interface{}{doc.Path},
+				},
+			},
+		}, nil
+	}
+
+	handler.TextDocumentInlayHint = func(context *glsp.Context, params *InlayHintParams) ([]InlayHint, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+
+		links, err := doc.DocumentLinks()
+		if err != nil {
+			return nil, err
+		}
+
+		notebook, err := server.notebookOf(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		hints := []InlayHint{}
+		for _, link := range links {
+			// Only links without a label, such as [[a1b2]], need a hint.
+			if !link.IsWikiLink || link.HasTitle || strutil.IsURL(link.Href) {
+				continue
+			}
+			if link.Range.End.Line < params.Range.Start.Line || link.Range.Start.Line > params.Range.End.Line {
+				continue
+			}
+
+			target, err := server.noteForLink(link, notebook)
+			if err != nil {
+				server.logger.Err(err)
+				continue
+			}
+			if target == nil || target.Title == "" || strings.EqualFold(target.Title, link.Href) {
+				continue
+			}
+
+			hints = append(hints, InlayHint{
+				Position:    link.Range.End,
+				Label:       target.Title,
+				Tooltip:     stringPtr(target.Path),
+				PaddingLeft: boolPtr(true),
+			})
+		}
+
+		return hints, nil
+	}
+
+	handler.TextDocumentSemanticTokensFull = func(context *glsp.Context, params *protocol.SemanticTokensParams) (*protocol.SemanticTokens, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+
+		notebook, err := server.notebookOf(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		structure, err := server.parseStructure(notebook, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return &protocol.SemanticTokens{
+			Data: server.buildSemanticTokens(notebook, doc, structure),
+		}, nil
+	}
+
+	handler.TextDocumentFoldingRange = func(context *glsp.Context, params *protocol.FoldingRangeParams) ([]protocol.FoldingRange, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+
+		notebook, err := server.notebookOf(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		structure, err := server.parseStructure(notebook, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildFoldingRanges(doc, structure), nil
+	}
+
+	handler.TextDocumentSelectionRange = func(context *glsp.Context, params *protocol.SelectionRangeParams) ([]protocol.SelectionRange, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+
+		notebook, err := server.notebookOf(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		structure, err := server.parseStructure(notebook, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildSelectionRanges(doc, structure, params.Positions), nil
+	}
+
+	handler.TextDocumentFormatting = func(context *glsp.Context, params *protocol.DocumentFormattingParams) ([]protocol.TextEdit, error) {
+		doc, ok := server.documents.Get(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+
+		notebook, err := server.notebookOf(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := notebook.RelPath(doc.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		formatted, err := notebook.FormatNote(path, doc.Content)
+		if err != nil || formatted == doc.Content {
+			return []protocol.TextEdit{}, err
+		}
+
+		// Replace the whole document.
+		return []protocol.TextEdit{{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: 0, Character: 0},
+				End:   doc.PositionAt(len(doc.Content)),
+			},
+			NewText: formatted,
+		}}, nil
+	}
+
 	return server
 }
 
@@ -528,6 +853,20 @@ func (s *Server) notebookOf(doc *document) (*core.Notebook, error) {
 //  2. Find any occurrence of the href in a note path (substring)
 //  3. Match the href as a term in the note titles
 func (s *Server) noteForLink(link documentLink, notebook *core.Notebook) (*Note, error) {
+	if link.IsWikiLink {
+		target, href, aliased, err := s.notebookForHref(link.Href, notebook)
+		if err != nil {
+			s.logger.Err(err)
+			return nil, nil
+		}
+		// The alias may also name the current notebook.
+		if aliased {
+			notebook = target
+			link.Href = href
+			link.RelativeToDir = target.Path
+		}
+	}
+
 	note, err := s.noteForHref(link.Href, link.RelativeToDir, notebook)
 	if note == nil && err == nil && link.IsWikiLink {
 		// Try to find a partial href match.
@@ -541,6 +880,38 @@ func (s *Server) noteForLink(link documentLink, notebook *core.Notebook) (*Note,
 	return &Note{*note, pathToURI(joined_path)}, nil
 }
 
+// notebookForHref resolves an href prefixed with the alias of a notebook
+// declared in the [notebooks] config section, e.g. work:Some note. It returns
+// the targeted notebook and the href relative to its root, or false when the
+// href has no known alias prefix.
+func (s *Server) notebookForHref(href string, notebook *core.Notebook) (*core.Notebook, string, bool, error) {
+	alias, targetHref, found := strings.Cut(href, ":")
+	if !found || targetHref == "" {
+		return notebook, href, false, nil
+	}
+	dir, ok := notebook.Config.NotebookDir(alias)
+	if !ok {
+		return notebook, href, false, nil
+	}
+
+	target, err := s.notebooks.Open(dir)
+	if err != nil {
+		return nil, "", false, errors.Wrapf(err, "failed to open the notebook %s", alias)
+	}
+	return target, targetHref, true, nil
+}
+
+// assetForLink returns the absolute path of the local file targeted by the
+// given link, when it is not a note, e.g. an image.
+func (s *Server) assetForLink(link documentLink, notebook *core.Notebook) (string, bool) {
+	if link.IsWikiLink || !notebook.IsAssetHref(link.Href, link.RelativeToDir) {
+		return "", false
+	}
+	path := strings.SplitN(link.Href, "#", 2)[0]
+	path = strings.SplitN(path, "?", 2)[0]
+	return filepath.Join(link.RelativeToDir, path), true
+}
+
 // noteForHref returns the Note object for the note targeted by the given HREF
 // relative to relativeToDir.
 func (s *Server) noteForHref(href string, relativeToDir string, notebook *core.Notebook) (*core.MinimalNote, error) {
@@ -616,6 +987,13 @@ func (s *Server) refreshDiagnosticsOfDocument(doc *document, notify glsp.NotifyF
 				}
 				severity = protocol.DiagnosticSeverity(diagConfig.DeadLink)
 				message = "not found"
+
+				if path, ok := s.assetForLink(link, notebook); ok {
+					if exists, _ := s.fs.FileExists(path); exists {
+						continue
+					}
+					message = "file not found"
+				}
 			} else if target.URI == doc.URI {
 				if diagConfig.SelfLink == core.LSPDiagnosticNone {
 					continue
@@ -638,6 +1016,30 @@ func (s *Server) refreshDiagnosticsOfDocument(doc *document, notify glsp.NotifyF
 			})
 		}
 
+		if diagConfig.DeadLink != core.LSPDiagnosticNone {
+			images, err := doc.ImageLinks()
+			if err != nil {
+				s.logger.Err(err)
+				return
+			}
+			for _, link := range images {
+				path, ok := s.assetForLink(link, notebook)
+				if !ok {
+					continue
+				}
+				if exists, _ := s.fs.FileExists(path); exists {
+					continue
+				}
+				severity := protocol.DiagnosticSeverity(diagConfig.DeadLink)
+				diagnostics = append(diagnostics, protocol.Diagnostic{
+					Range:    link.Range,
+					Severity: &severity,
+					Source:   stringPtr("zk"),
+					Message:  "file not found",
+				})
+			}
+		}
+
 		if diagConfig.MissingBacklink.Level != core.LSPDiagnosticNone {
 			backlinks := s.getMissingBacklinkDiagnostics(doc, notebook, diagConfig.MissingBacklink)
 			diagnostics = append(diagnostics, backlinks...)
@@ -650,9 +1052,91 @@ func (s *Server) refreshDiagnosticsOfDocument(doc *document, notify glsp.NotifyF
 	}()
 }
 
+// registerFileWatchers asks the client to notify the server when notes of the
+// given notebook are changed outside of the editor, e.g. with git.
+func (s *Server) registerFileWatchers(context *glsp.Context, notebook *core.Notebook) {
+	extensions := []string{notebook.Config.Note.Extension}
+	for _, group := range notebook.Config.Groups {
+		extensions = append(extensions, group.Note.Extension)
+	}
+
+	s.watchedExtensionsLock.Lock()
+	defer s.watchedExtensionsLock.Unlock()
+
+	registrations := []protocol.Registration{}
+	for _, ext := range extensions {
+		if ext == "" || s.watchedExtensions[ext] {
+			continue
+		}
+		s.watchedExtensions[ext] = true
+
+		registrations = append(registrations, protocol.Registration{
+			ID:     "zk-watch-" + ext,
+			Method: string(protocol.MethodWorkspaceDidChangeWatchedFiles),
+			RegisterOptions: protocol.DidChangeWatchedFilesRegistrationOptions{
+				Watchers: []protocol.FileSystemWatcher{
+					{GlobPattern: "**/*." + ext},
+				},
+			},
+		})
+	}
+
+	if len(registrations) > 0 {
+		go context.Call(protocol.ServerClientRegisterCapability, protocol.RegistrationParams{
+			Registrations: registrations,
+		}, nil)
+	}
+}
+
+// refreshDiagnosticsOfLinkingDocuments refreshes the diagnostics of the opened
+// documents linking to one of the given changed notes. Documents containing
+// dead links are refreshed as well, as the missing note might have been
+// created.
+func (s *Server) refreshDiagnosticsOfLinkingDocuments(notebook *core.Notebook, changedPaths []string, notify glsp.NotifyFunc) {
+	changed := map[string]bool{}
+	for _, path := range changedPaths {
+		changed[path] = true
+	}
+
+	for _, doc := range s.documents.All() {
+		docNotebook, err := s.notebookOf(doc)
+		if err != nil || docNotebook != notebook {
+			continue
+		}
+
+		links, err := doc.DocumentLinks()
+		if err != nil {
+			s.logger.Err(err)
+			continue
+		}
+
+		for _, link := range links {
+			if strutil.IsURL(link.Href) {
+				continue
+			}
+			target, err := s.noteForLink(link, notebook)
+			if err != nil {
+				continue
+			}
+			if target == nil {
+				s.refreshDiagnosticsOfDocument(doc, notify, false)
+				break
+			}
+			if path, err := uriToPath(target.URI); err == nil && changed[s.fs.Canonical(path)] {
+				s.refreshDiagnosticsOfDocument(doc, notify, false)
+				break
+			}
+		}
+	}
+}
+
 // buildInvokedCompletionList builds the completion item response for a
 // completion started automatically when typing an identifier, or manually.
 func (s *Server) buildInvokedCompletionList(notebook *core.Notebook, doc *document, position protocol.Position) ([]protocol.CompletionItem, error) {
+	if items, ok := s.buildNotebookLinkCompletionList(notebook, doc, position); ok {
+		return items, nil
+	}
+
 	currentWord := doc.WordAt(position)
 	// currentWord will include parentheses (( but not brackets [[.
 	// We check both if it's at len(currentWord) word and len(currentWord)-2 to account for auto-pair
@@ -672,6 +1156,10 @@ func (s *Server) buildInvokedCompletionList(notebook *core.Notebook, doc *docume
 // buildTriggerCompletionList builds the completion item response for a
 // completion started with a trigger character.
 func (s *Server) buildTriggerCompletionList(notebook *core.Notebook, doc *document, position protocol.Position) ([]protocol.CompletionItem, error) {
+	if items, ok := s.buildNotebookLinkCompletionList(notebook, doc, position); ok {
+		return items, nil
+	}
+
 	// We don't use the context because clients might not send it. Instead,
 	// we'll look for trigger patterns in the document.
 	switch doc.LookBehind(position, 3) {
@@ -768,6 +1256,71 @@ func (s *Server) buildLinkCompletionList(notebook *core.Notebook, doc *document,
 	return items, nil
 }
 
+var notebookLinkPrefixRegex = regexp.MustCompile(`\[\[([^\[\]|:]+):[^\[\]|]*$`)
+
+// buildNotebookLinkCompletionList completes links to notes of another
+// notebook, when the cursor follows a prefix such as [[work:
+//
+// The boolean is false when the position is not in a cross-notebook link.
+func (s *Server) buildNotebookLinkCompletionList(notebook *core.Notebook, doc *document, position protocol.Position) ([]protocol.CompletionItem, bool) {
+	before := doc.LookBehind(position, int(position.Character))
+	match := notebookLinkPrefixRegex.FindStringSubmatchIndex(before)
+	if match == nil {
+		return nil, false
+	}
+	alias := before[match[2]:match[3]]
+	dir, ok := notebook.Config.NotebookDir(alias)
+	if !ok {
+		return nil, false
+	}
+
+	target, err := s.notebooks.Open(dir)
+	if err != nil {
+		s.logger.Err(err)
+		return nil, true
+	}
+	notes, err := target.FindMinimalNotes(core.NoteFindOpts{})
+	if err != nil {
+		s.logger.Err(err)
+		return nil, true
+	}
+
+	// Replace everything after the opening [[, including the auto-paired
+	// closing brackets.
+	start := protocol.Position{
+		Line:      position.Line,
+		Character: protocol.UInteger(len(utf16.Encode([]rune(before[:match[0]+2])))),
+	}
+	end := position
+	if doc.LookForward(position, 2) == "]]" {
+		end.Character += 2
+	}
+
+	kind := protocol.CompletionItemKindReference
+	items := []protocol.CompletionItem{}
+	for _, note := range notes {
+		href := strings.TrimSuffix(note.Path, filepath.Ext(note.Path))
+		label := note.Title
+		if label == "" {
+			label = note.Path
+		}
+
+		items = append(items, protocol.CompletionItem{
+			Label:      label,
+			Kind:       &kind,
+			Detail:     stringPtr(alias + ":" + note.Path),
+			FilterText: stringPtr(alias + ":" + label + " " + note.Path),
+			Data:       filepath.Join(target.Path, note.Path),
+			TextEdit: protocol.TextEdit{
+				NewText: alias + ":" + href + "]]",
+				Range:   protocol.Range{Start: start, End: end},
+			},
+		})
+	}
+
+	return items, true
+}
+
 func newLinkFormatter(notebook *core.Notebook, doc *document, position protocol.Position) (core.LinkFormatter, error) {
 	if doc.LookBehind(position, 3) == "]((" {
 		return core.NewMarkdownLinkFormatter(notebook.Config.Format.Markdown, true)
@@ -920,6 +1473,40 @@ func rangeFromPosition(pos protocol.Position, startOffset, endOffset int) protoc
 	}
 }
 
+// isCodeActionKindRequested returns whether the code actions of the given kind
+// are requested by the client, which may ask only for some kinds, e.g. the
+// `source.fixAll` actions when saving a document.
+func isCodeActionKindRequested(only []protocol.CodeActionKind, kind protocol.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, requested := range only {
+		if kind == requested || strings.HasPrefix(string(kind), string(requested)+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isIndexedNote returns whether the given document is a note found in the
+// index of its notebook.
+func (s *Server) isIndexedNote(doc *document) bool {
+	notebook, err := s.notebookOf(doc)
+	if err != nil {
+		return false
+	}
+	relPath, err := notebook.RelPath(doc.Path)
+	if err != nil {
+		return false
+	}
+	note, err := notebook.FindByHref(relPath, false)
+	if err != nil {
+		s.logger.Err(err)
+		return false
+	}
+	return note != nil
+}
+
 func isRangeEmpty(pos protocol.Range) bool {
 	return pos.Start == pos.End
 }
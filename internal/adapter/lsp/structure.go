@@ -0,0 +1,223 @@
+package lsp
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/core"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Semantic token types and modifiers supported by the server. The index of
+// each item is used to encode the tokens.
+//
+// Only standard LSP types and modifiers are used, as clients don't theme
+// custom ones: tags are keywords, wiki-links are variables and dead links are
+// deprecated.
+var (
+	semanticTokenTypes = []string{
+		string(protocol.SemanticTokenTypeKeyword),
+		string(protocol.SemanticTokenTypeVariable),
+		string(protocol.SemanticTokenTypeProperty),
+	}
+	semanticTokenModifiers = []string{
+		string(protocol.SemanticTokenModifierDeprecated),
+	}
+)
+
+const (
+	semanticTokenTypeTag      = 0
+	semanticTokenTypeLink     = 1
+	semanticTokenTypeProperty = 2
+
+	semanticTokenModifierDead = 1 << 0
+)
+
+// parseStructure returns the position of the syntactic elements of the
+// given document.
+func (s *Server) parseStructure(notebook *core.Notebook, doc *document) (*markdown.Structure, error) {
+	parser, ok := notebook.Parser.(*markdown.Parser)
+	if !ok {
+		config := notebook.Config.Format.Markdown
+		parser = markdown.NewParser(markdown.ParserOpts{
+			HashtagEnabled:      config.Hashtags,
+			MultiWordTagEnabled: config.MultiwordTags,
+			ColontagEnabled:     config.ColonTags,
+		}, s.logger)
+	}
+	return parser.ParseStructure(doc.Content)
+}
+
+// documentStructure returns the position of the syntactic elements of the
+// given document, parsed with the settings of its notebook.
+func (s *Server) documentStructure(doc *document) (*markdown.Structure, error) {
+	notebook, err := s.notebookOf(doc)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseStructure(notebook, doc)
+}
+
+type semanticToken struct {
+	start     int
+	end       int
+	tokenType int
+	modifiers int
+}
+
+// buildSemanticTokens encodes the tags, wiki links and frontmatter keys of the
+// document as LSP semantic tokens.
+func (s *Server) buildSemanticTokens(notebook *core.Notebook, doc *document, structure *markdown.Structure) []protocol.UInteger {
+	tokens := []semanticToken{}
+
+	for _, key := range structure.FrontmatterKeys {
+		tokens = append(tokens, semanticToken{start: key.Start, end: key.End, tokenType: semanticTokenTypeProperty})
+	}
+	for _, tag := range structure.Tags {
+		tokens = append(tokens, semanticToken{start: tag.Start, end: tag.End, tokenType: semanticTokenTypeTag})
+	}
+	for _, link := range structure.WikiLinks {
+		if strutil.IsURL(link.Href) {
+			continue
+		}
+
+		modifiers := 0
+		target, err := s.noteForLink(documentLink{
+			Href:          link.Href,
+			RelativeToDir: filepath.Dir(doc.Path),
+			IsWikiLink:    true,
+		}, notebook)
+		if err != nil {
+			s.logger.Err(err)
+			continue
+		}
+		if target == nil {
+			modifiers |= semanticTokenModifierDead
+		}
+
+		tokens = append(tokens, semanticToken{start: link.Start, end: link.End, tokenType: semanticTokenTypeLink, modifiers: modifiers})
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].start < tokens[j].start
+	})
+
+	// Each token is encoded as 5 integers, relative to the previous token.
+	// See https://microsoft.github.io/language-server-protocol/specifications/specification-3-16/#textDocument_semanticTokens
+	data := []protocol.UInteger{}
+	previous := protocol.Position{}
+	for _, token := range tokens {
+		start := doc.PositionAt(token.start)
+		end := doc.PositionAt(token.end)
+		// Multiline tokens are not supported by all clients.
+		if start.Line != end.Line || end.Character <= start.Character {
+			continue
+		}
+
+		deltaChar := start.Character
+		if start.Line == previous.Line {
+			deltaChar -= previous.Character
+		}
+		data = append(data,
+			start.Line-previous.Line,
+			deltaChar,
+			end.Character-start.Character,
+			protocol.UInteger(token.tokenType),
+			protocol.UInteger(token.modifiers),
+		)
+		previous = start
+	}
+
+	return data
+}
+
+// buildFoldingRanges returns the foldable sections and frontmatter of the
+// document.
+func buildFoldingRanges(doc *document, structure *markdown.Structure) []protocol.FoldingRange {
+	ranges := []protocol.FoldingRange{}
+	kind := string(protocol.FoldingRangeKindRegion)
+
+	addRange := func(span markdown.Span) {
+		// Trailing blank lines are not folded.
+		end := len(strings.TrimRight(doc.Content[:span.End], " \t\r\n"))
+		startLine := doc.PositionAt(span.Start).Line
+		endLine := doc.PositionAt(end).Line
+		if endLine > startLine {
+			ranges = append(ranges, protocol.FoldingRange{
+				StartLine: startLine,
+				EndLine:   endLine,
+				Kind:      &kind,
+			})
+		}
+	}
+
+	if structure.Frontmatter != nil {
+		addRange(*structure.Frontmatter)
+	}
+	for _, section := range structure.Sections {
+		addRange(section.Span)
+	}
+
+	return ranges
+}
+
+// buildSelectionRanges returns, for each position, the chain of syntactic
+// elements containing it, from the innermost to the whole document.
+func buildSelectionRanges(doc *document, structure *markdown.Structure, positions []protocol.Position) []protocol.SelectionRange {
+	spans := []markdown.Span{}
+	spans = append(spans, structure.FrontmatterKeys...)
+	spans = append(spans, structure.Tags...)
+	for _, link := range structure.WikiLinks {
+		spans = append(spans, link.Span)
+	}
+	spans = append(spans, structure.Blocks...)
+	if structure.Frontmatter != nil {
+		spans = append(spans, *structure.Frontmatter)
+	}
+	for _, section := range structure.Sections {
+		spans = append(spans, section.Span)
+	}
+	spans = append(spans, markdown.Span{Start: 0, End: len(doc.Content)})
+
+	toRange := func(span markdown.Span) protocol.Range {
+		return protocol.Range{
+			Start: doc.PositionAt(span.Start),
+			End:   doc.PositionAt(span.End),
+		}
+	}
+
+	ranges := []protocol.SelectionRange{}
+	for _, pos := range positions {
+		offset := pos.IndexIn(doc.Content)
+
+		containing := []markdown.Span{}
+		for _, span := range spans {
+			if span.Contains(offset) {
+				containing = append(containing, span)
+			}
+		}
+		sort.SliceStable(containing, func(i, j int) bool {
+			return containing[i].End-containing[i].Start < containing[j].End-containing[j].Start
+		})
+
+		// Build the chain from the outermost element.
+		var selection *protocol.SelectionRange
+		for i := len(containing) - 1; i >= 0; i-- {
+			rng := toRange(containing[i])
+			if selection != nil && selection.Range == rng {
+				continue
+			}
+			selection = &protocol.SelectionRange{Range: rng, Parent: selection}
+		}
+
+		if selection == nil {
+			selection = &protocol.SelectionRange{Range: protocol.Range{Start: pos, End: pos}}
+		}
+		ranges = append(ranges, *selection)
+	}
+
+	return ranges
+}
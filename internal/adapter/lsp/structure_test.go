@@ -0,0 +1,76 @@
+package lsp
+
+import (
+	"testing"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func parseTestStructure(t *testing.T, content string) (*document, *markdown.Structure) {
+	parser := markdown.NewParser(markdown.ParserOpts{HashtagEnabled: true}, &util.NullLogger)
+	structure, err := parser.ParseStructure(content)
+	assert.Nil(t, err)
+	return &document{Content: content}, structure
+}
+
+func TestDocumentPositionAt(t *testing.T) {
+	doc := &document{Content: "Héllo\nw😀rld"}
+
+	assert.Equal(t, doc.PositionAt(0), protocol.Position{Line: 0, Character: 0})
+	assert.Equal(t, doc.PositionAt(3), protocol.Position{Line: 0, Character: 2})
+	assert.Equal(t, doc.PositionAt(7), protocol.Position{Line: 1, Character: 0})
+	// The emoji is made of two UTF-16 code units.
+	assert.Equal(t, doc.PositionAt(12), protocol.Position{Line: 1, Character: 3})
+	assert.Equal(t, doc.PositionAt(100), protocol.Position{Line: 1, Character: 6})
+}
+
+func TestBuildFoldingRanges(t *testing.T) {
+	doc, structure := parseTestStructure(t, `---
+title: Note
+---
+
+# Heading 1
+Content
+
+## Heading 1.1
+Content
+
+# Heading 2
+`)
+
+	kind := string(protocol.FoldingRangeKindRegion)
+	assert.Equal(t, buildFoldingRanges(doc, structure), []protocol.FoldingRange{
+		{StartLine: 0, EndLine: 2, Kind: &kind},
+		{StartLine: 4, EndLine: 8, Kind: &kind},
+		{StartLine: 7, EndLine: 8, Kind: &kind},
+	})
+}
+
+func TestBuildSelectionRanges(t *testing.T) {
+	doc, structure := parseTestStructure(t, `# Heading
+A paragraph with a #tag.
+`)
+
+	rng := func(startLine, startChar, endLine, endChar uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine, Character: endChar},
+		}
+	}
+
+	ranges := buildSelectionRanges(doc, structure, []protocol.Position{{Line: 1, Character: 21}})
+	assert.Equal(t, ranges, []protocol.SelectionRange{
+		{
+			Range: rng(1, 19, 1, 23),
+			Parent: &protocol.SelectionRange{
+				Range: rng(1, 0, 1, 24),
+				Parent: &protocol.SelectionRange{
+					Range: rng(0, 0, 2, 0),
+				},
+			},
+		},
+	})
+}
@@ -3,6 +3,7 @@ package extensions
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
@@ -16,6 +17,8 @@ type Tags struct {
 	ast.BaseInline
 	// Tags in this list.
 	Tags []string
+	// Segment is the position of the tags in the source.
+	Segment text.Segment
 }
 
 func (n *Tags) Dump(source []byte, level int) {
@@ -78,7 +81,7 @@ func (p *hashtagParser) Trigger() []byte {
 
 func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
 	previousChar := block.PrecendingCharacter()
-	line, _ := block.PeekLine()
+	line, segment := block.PeekLine()
 
 	// A hashtag can't be directly preceded by a # or any other valid character.
 	if isValidTagChar(previousChar, '\x00') {
@@ -95,6 +98,8 @@ func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 		parsingMultiWordTag = false // Finished parsing a hashtag, now attempt parsing a Bear multi-word tag
 		endPos              = 0     // Last position of the tag in the line
 		multiWordTagEndPos  = 0     // Last position of the multi-word tag in the line
+		charEnd             = 0     // Position following the current character
+		tagEnd              = 0     // Position following the last character of the tag
 	)
 
 	appendChar := func(c rune) {
@@ -102,6 +107,7 @@ func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 			multiWordTagCandidate += string(c)
 		} else {
 			tag += string(c)
+			tagEnd = charEnd
 		}
 	}
 
@@ -110,6 +116,7 @@ func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 			// Skip the first character, as it is #
 			continue
 		}
+		charEnd = i + utf8.RuneLen(char)
 		if parsingMultiWordTag {
 			multiWordTagEndPos = i
 		} else {
@@ -134,6 +141,7 @@ func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 				if !unicode.IsSpace(previousChar) {
 					tag = multiWordTagCandidate
 					endPos = multiWordTagEndPos
+					tagEnd = charEnd
 				}
 				break
 			}
@@ -169,6 +177,7 @@ func (p *hashtagParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 	return &Tags{
 		BaseInline: ast.BaseInline{},
 		Tags:       []string{tag},
+		Segment:    text.NewSegment(segment.Start, segment.Start+tagEnd),
 	}
 }
 
@@ -190,7 +199,7 @@ func (p *colontagParser) Trigger() []byte {
 
 func (p *colontagParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
 	previousChar := block.PrecendingCharacter()
-	line, _ := block.PeekLine()
+	line, segment := block.PeekLine()
 
 	// A colontag can't be directly preceded by a : or any other valid character.
 	if isValidTagChar(previousChar, '\x00') {
@@ -205,6 +214,7 @@ func (p *colontagParser) Parse(parent ast.Node, block text.Reader, pc parser.Con
 	var (
 		escaping = false // Found a backslash, next character will be literal
 		endPos   = 0     // Last position of the colontags in the line
+		tagsEnd  = 0     // Position following the last colon of the colontags
 	)
 
 	appendChar := func(c rune) {
@@ -230,6 +240,8 @@ func (p *colontagParser) Parse(parent ast.Node, block text.Reader, pc parser.Con
 			}
 			tags = append(tags, tag)
 			tag = ""
+			// i is relative to the character following the leading colon.
+			tagsEnd = i + 2
 
 		} else if !isValidTagChar(char, ':') {
 			// Found an invalid character, the colontag is complete.
@@ -249,6 +261,7 @@ func (p *colontagParser) Parse(parent ast.Node, block text.Reader, pc parser.Con
 	return &Tags{
 		BaseInline: ast.BaseInline{},
 		Tags:       tags,
+		Segment:    text.NewSegment(segment.Start, segment.Start+tagsEnd),
 	}
 }
 
@@ -21,6 +21,8 @@ type wikiLink struct{}
 // WikiLink represents a wiki link found in a Markdown document.
 type WikiLink struct {
 	ast.Link
+	// Segment is the position of the link in the source.
+	Segment text.Segment
 }
 
 func (w *wikiLink) Extend(m goldmark.Markdown) {
@@ -38,7 +40,7 @@ func (p *wlParser) Trigger() []byte {
 }
 
 func (p *wlParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
-	line, _ := block.PeekLine()
+	line, segment := block.PeekLine()
 
 	var (
 		href  string
@@ -54,6 +56,7 @@ func (p *wlParser) Parse(parent ast.Node, block text.Reader, pc parser.Context)
 		openerCharCount = 0     // Number of [ encountered
 		closerCharCount = 0     // Number of ] encountered
 		endPos          = 0     // Last position of the link in the line
+		linkEnd         = 0     // Position following the last character of the link
 	)
 
 	appendRune := func(c rune) {
@@ -71,6 +74,7 @@ func (p *wlParser) Parse(parent ast.Node, block text.Reader, pc parser.Context)
 			// Supports trailing hash syntax for Neuron's Folgezettel, e.g. [[id]]#
 			if char == '#' {
 				rel = core.LinkRelationDown
+				linkEnd = i + 1
 			}
 			break
 		}
@@ -107,6 +111,7 @@ func (p *wlParser) Parse(parent ast.Node, block text.Reader, pc parser.Context)
 				closerCharCount += 1
 				if closerCharCount == openerCharCount {
 					closed = true
+					linkEnd = i + 1
 					// Neuron's legacy [[[Folgezettel]]].
 					if closerCharCount == 3 {
 						rel = core.LinkRelationDown
@@ -140,7 +145,10 @@ func (p *wlParser) Parse(parent ast.Node, block text.Reader, pc parser.Context)
 		label = href
 	}
 
-	link := &WikiLink{Link: *ast.NewLink()}
+	link := &WikiLink{
+		Link:    *ast.NewLink(),
+		Segment: text.NewSegment(segment.Start, segment.Start+linkEnd),
+	}
 	link.Destination = []byte(href)
 	// Title will be parsed as the link's rel by the Markdown parser.
 	link.Title = []byte(rel)
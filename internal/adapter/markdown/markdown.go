@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/mvdan/xurls"
+	"github.com/relvacode/iso8601"
 	"github.com/yuin/goldmark"
 	meta "github.com/yuin/goldmark-meta"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 	"github.com/zk-org/zk/internal/adapter/markdown/extensions"
@@ -52,6 +57,7 @@ func NewParser(options ParserOpts, logger util.Logger) *Parser {
 						xurls.Strict,
 					),
 				),
+				extension.TaskList,
 				extensions.WikiLinkExt,
 				&extensions.TagExt{
 					HashtagEnabled:      options.HashtagEnabled,
@@ -95,12 +101,24 @@ func (p *Parser) ParseNoteContent(content string) (*core.NoteContent, error) {
 		return nil, err
 	}
 
+	tasks, err := parseTasks(root, bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := parseCards(root, bytes)
+	if err != nil {
+		return nil, err
+	}
+
 	return &core.NoteContent{
 		Title:    title,
 		Body:     body,
 		Lead:     parseLead(body),
 		Links:    links,
 		Tags:     tags,
+		Tasks:    tasks,
+		Cards:    cards,
 		Metadata: frontmatter.values,
 	}, nil
 }
@@ -112,7 +130,24 @@ func parseTitle(frontmatter frontmatter, root ast.Node, source []byte) (title op
 		return
 	}
 
-	var titleNode *ast.Heading
+	titleNode, err := findTitleHeading(root)
+	if err != nil {
+		return
+	}
+
+	if titleNode != nil {
+		title = opt.NewNotEmptyString(string(titleNode.Text(source)))
+
+		if lines := titleNode.Lines(); lines.Len() > 0 {
+			bodyStart = lines.At(lines.Len() - 1).Stop
+		}
+	}
+	return
+}
+
+// findTitleHeading returns the heading used as the note title: the first
+// heading with the highest level.
+func findTitleHeading(root ast.Node) (titleNode *ast.Heading, err error) {
 	err = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if heading, ok := n.(*ast.Heading); ok && entering &&
 			(titleNode == nil || heading.Level < titleNode.Level) {
@@ -125,17 +160,6 @@ func parseTitle(frontmatter frontmatter, root ast.Node, source []byte) (title op
 
 		return ast.WalkContinue, nil
 	})
-	if err != nil {
-		return
-	}
-
-	if titleNode != nil {
-		title = opt.NewNotEmptyString(string(titleNode.Text(source)))
-
-		if lines := titleNode.Lines(); lines.Len() > 0 {
-			bodyStart = lines.At(lines.Len() - 1).Stop
-		}
-	}
 	return
 }
 
@@ -235,6 +259,25 @@ func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 					})
 				}
 
+			case *ast.Image:
+				// Only local images are indexed, to track the note assets. They
+				// are kept apart from the links between notes.
+				href, err := url.PathUnescape(string(link.Destination))
+				p.logger.Err(err)
+				if href != "" && !strutil.IsURL(href) {
+					snippet, snStart, snEnd := extractLines(n, source)
+					links = append(links, core.Link{
+						Title:        string(link.Text(source)),
+						Href:         href,
+						Type:         core.LinkTypeImage,
+						Rels:         core.LinkRels(strings.Fields(string(link.Title))...),
+						IsExternal:   false,
+						Snippet:      snippet,
+						SnippetStart: snStart,
+						SnippetEnd:   snEnd,
+					})
+				}
+
 			case *ast.AutoLink:
 				if href := string(link.URL(source)); href != "" && link.AutoLinkType == ast.AutoLinkURL {
 					snippet, snStart, snEnd := extractLines(n, source)
@@ -272,6 +315,229 @@ func (p *Parser) parseLinks(root ast.Node, source []byte) ([]core.Link, error) {
 	return links, err
 }
 
+// taskCheckboxRegex matches the checkbox at the start of a task list item.
+var taskCheckboxRegex = regexp.MustCompile(`^\[[\sxX]\]\s*`)
+
+// taskDueRegex matches a due date in a task, e.g. @due(2026-10-20).
+var taskDueRegex = regexp.MustCompile(`@due\(([^)]+)\)`)
+
+// parseTasks extracts the task list items, e.g. `- [ ] Buy milk`.
+func parseTasks(root ast.Node, source []byte) ([]core.Task, error) {
+	tasks := make([]core.Task, 0)
+
+	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		checkbox, ok := n.(*extast.TaskCheckBox)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		block := checkbox.Parent()
+		segs := block.Lines()
+		if segs.Len() == 0 {
+			return ast.WalkContinue, nil
+		}
+
+		lines := make([]string, 0, segs.Len())
+		for i := 0; i < segs.Len(); i++ {
+			seg := segs.At(i)
+			lines = append(lines, strings.TrimSpace(string(seg.Value(source))))
+		}
+		text := taskCheckboxRegex.ReplaceAllString(strings.Join(lines, " "), "")
+
+		task := core.Task{
+			Text: text,
+			Done: checkbox.IsChecked,
+			Line: strings.Count(string(source[:segs.At(0).Start]), "\n") + 1,
+			Due:  parseTaskDue(text),
+			Tags: []string{},
+		}
+
+		// Nested list items are not children of the block, so their tags are
+		// not included.
+		err := ast.Walk(block, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if tagsNode, ok := n.(*extensions.Tags); ok && entering {
+				task.Tags = append(task.Tags, tagsNode.Tags...)
+			}
+			return ast.WalkContinue, nil
+		})
+		if err != nil {
+			return ast.WalkStop, err
+		}
+		task.Tags = strutil.RemoveDuplicates(task.Tags)
+
+		tasks = append(tasks, task)
+		return ast.WalkSkipChildren, nil
+	})
+
+	return tasks, err
+}
+
+// parseTaskDue extracts the due date of a task, if any.
+func parseTaskDue(text string) *time.Time {
+	match := taskDueRegex.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	due := strings.TrimSpace(match[1])
+	if date, err := iso8601.ParseString(due); err == nil {
+		date = date.UTC()
+		return &date
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if date, err := time.Parse(layout, due); err == nil {
+			return &date
+		}
+	}
+	return nil
+}
+
+// cardTagRegex matches the #card tag marking a heading as a flashcard.
+var cardTagRegex = regexp.MustCompile(`(^|\s)#card(\s|$)`)
+
+// parseCards extracts the flashcards, written either on a single line as
+// `Question::Answer`, or as a section whose heading is tagged with #card.
+func parseCards(root ast.Node, source []byte) ([]core.Card, error) {
+	cards := make([]core.Card, 0)
+
+	// Single line cards, in paragraphs and list items.
+	err := ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || (n.Kind() != ast.KindParagraph && n.Kind() != ast.KindTextBlock) {
+			return ast.WalkContinue, nil
+		}
+		segs := n.Lines()
+		for i := 0; i < segs.Len(); i++ {
+			seg := segs.At(i)
+			question, answer, ok := splitInlineCard(string(seg.Value(source)))
+			if ok {
+				line := strings.Count(string(source[:seg.Start]), "\n") + 1
+				cards = append(cards, core.NewCard(question, answer, line))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return cards, err
+	}
+
+	// Sections, which end at the next heading of the same or higher level.
+	var heading *ast.Heading
+	var question string
+	var answerStart int
+	addSection := func(answerEnd int) {
+		if heading == nil {
+			return
+		}
+		answer := strings.TrimSpace(string(source[answerStart:answerEnd]))
+		if question != "" && answer != "" {
+			line := strings.Count(string(source[:heading.Lines().At(0).Start]), "\n") + 1
+			cards = append(cards, core.NewCard(question, answer, line))
+		}
+		heading = nil
+	}
+
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		h, ok := n.(*ast.Heading)
+		if !ok || h.Lines().Len() == 0 {
+			continue
+		}
+		segs := h.Lines()
+		if heading != nil && h.Level <= heading.Level {
+			addSection(lineStart(source, segs.At(0).Start))
+		}
+		if heading != nil {
+			continue
+		}
+
+		// The raw text is used, as the tag is not part of the heading text
+		// when hashtags are enabled.
+		text := ""
+		for i := 0; i < segs.Len(); i++ {
+			seg := segs.At(i)
+			text += string(seg.Value(source))
+		}
+		if !cardTagRegex.MatchString(text) {
+			continue
+		}
+		heading = h
+		question = strings.TrimSpace(cardTagRegex.ReplaceAllString(text, " "))
+		answerStart = lineEnd(source, segs.At(segs.Len()-1).Stop)
+		if source[lineStart(source, segs.At(0).Start)] != '#' {
+			// Skip the underline of setext headings.
+			answerStart = lineEnd(source, answerStart)
+		}
+	}
+	addSection(len(source))
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Line < cards[j].Line
+	})
+	return cards, nil
+}
+
+// splitInlineCard splits a `Question :: Answer` line. The separator must be
+// surrounded by whitespace, and is ignored inside a code span.
+func splitInlineCard(line string) (question string, answer string, ok bool) {
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '`':
+			// Skip the code span closed by a backtick string of the same
+			// length, or only the opening backticks if it is never closed.
+			run := backtickRunLength(line[i:])
+			if end := closingBacktickRun(line[i+run:], run); end >= 0 {
+				i += run + end + run - 1
+			} else {
+				i += run - 1
+			}
+
+		case strings.HasPrefix(line[i:], "::") &&
+			i > 0 && unicode.IsSpace(rune(line[i-1])) &&
+			i+2 < len(line) && unicode.IsSpace(rune(line[i+2])):
+			question = strings.TrimSpace(line[:i])
+			answer = strings.TrimSpace(line[i+2:])
+			return question, answer, question != "" && answer != ""
+		}
+	}
+	return "", "", false
+}
+
+// backtickRunLength returns the number of consecutive backticks at the
+// beginning of s.
+func backtickRunLength(s string) int {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	return n
+}
+
+// closingBacktickRun returns the offset of the first backtick string of
+// exactly the given length in s, or -1 if there is none.
+func closingBacktickRun(s string, length int) int {
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRunLength(s[i:])
+		if run == length {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// lineEnd returns the offset of the beginning of the line following the
+// given offset.
+func lineEnd(source []byte, offset int) int {
+	for offset < len(source) && source[offset] != '\n' {
+		offset++
+	}
+	if offset < len(source) {
+		offset++
+	}
+	return offset
+}
+
 func extractLines(n ast.Node, source []byte) (content string, start, end int) {
 	if n == nil {
 		return
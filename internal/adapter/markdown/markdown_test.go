@@ -2,6 +2,7 @@ package markdown
 
 import (
 	"testing"
+	"time"
 
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
@@ -597,6 +598,24 @@ A link can have [one relation](one "rel-1") or [several relations](several "rel-
 	})
 }
 
+func TestParseLocalImages(t *testing.T) {
+	content := parse(t, `
+A local ![cat picture](img/cat%20photo.png "rel-1") and ![remote](https://example.com/cat.png).
+`)
+	assert.Equal(t, content.Links, []core.Link{
+		{
+			Title:        "cat picture",
+			Href:         "img/cat photo.png",
+			Type:         core.LinkTypeImage,
+			Rels:         core.LinkRels("rel-1"),
+			IsExternal:   false,
+			Snippet:      "A local ![cat picture](img/cat%20photo.png \"rel-1\") and ![remote](https://example.com/cat.png).",
+			SnippetStart: 1,
+			SnippetEnd:   96,
+		},
+	})
+}
+
 func TestParseMetadataFromFrontmatter(t *testing.T) {
 	test := func(source string, expectedMetadata map[string]interface{}) {
 		content := parse(t, source)
@@ -625,6 +644,133 @@ Paragraph
 	})
 }
 
+func TestParseTasks(t *testing.T) {
+	test := func(source string, expectedTasks []core.Task) {
+		content := parse(t, source)
+		assert.Equal(t, content.Tasks, expectedTasks)
+	}
+
+	due := func(year int, month time.Month, day int, hour int) *time.Time {
+		date := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	test("", []core.Task{})
+	test("- Not a task\n- [link](target)", []core.Task{})
+	test("[ ] Not in a list", []core.Task{})
+	test("```\n- [ ] In a code block\n```", []core.Task{})
+
+	test(`# Tasks
+
+- [ ] An open task
+- [x] A done task
+* [X] Upper case
+1. [ ] In an ordered list
+`, []core.Task{
+		{Text: "An open task", Line: 3, Tags: []string{}},
+		{Text: "A done task", Done: true, Line: 4, Tags: []string{}},
+		{Text: "Upper case", Done: true, Line: 5, Tags: []string{}},
+		{Text: "In an ordered list", Line: 6, Tags: []string{}},
+	})
+
+	test(`- [ ] Due #home #chores
+  on several lines @due(2026-10-20) :errand:
+  - [x] Nested task #garden
+- [ ] Due with a time @due(2026-10-20T14:00:00Z)
+- [ ] Invalid date @due(tomorrow)
+`, []core.Task{
+		{
+			Text: "Due #home #chores on several lines @due(2026-10-20) :errand:",
+			Line: 1,
+			Due:  due(2026, 10, 20, 0),
+			Tags: []string{"home", "chores", "errand"},
+		},
+		{Text: "Nested task #garden", Done: true, Line: 3, Tags: []string{"garden"}},
+		{Text: "Due with a time @due(2026-10-20T14:00:00Z)", Line: 4, Due: due(2026, 10, 20, 14), Tags: []string{}},
+		{Text: "Invalid date @due(tomorrow)", Line: 5, Tags: []string{}},
+	})
+}
+
+func TestSplitInlineCard(t *testing.T) {
+	test := func(line string, expectedQuestion string, expectedAnswer string, expectedOK bool) {
+		question, answer, ok := splitInlineCard(line)
+		assert.Equal(t, ok, expectedOK)
+		if ok {
+			assert.Equal(t, question, expectedQuestion)
+			assert.Equal(t, answer, expectedAnswer)
+		}
+	}
+
+	test("Italy :: Rome", "Italy", "Rome", true)
+	test("A\t::\tB :: C", "A", "B :: C", true)
+	test("`std::io` :: The I/O module", "`std::io`", "The I/O module", true)
+	test("`` a :: ` b `` :: c", "`` a :: ` b ``", "c", true)
+	test("Unclosed ` span :: Answer", "Unclosed ` span", "Answer", true)
+	test("std::io", "", "", false)
+	test(":a::b:", "", "", false)
+	test("fe80::1", "", "", false)
+	test("Question ::Answer", "", "", false)
+	test("`a :: b`", "", "", false)
+	test(" :: Answer", "", "", false)
+	test("Question :: ", "", "", false)
+}
+
+func TestParseCards(t *testing.T) {
+	test := func(source string, expectedCards []core.Card) {
+		content := parse(t, source)
+		assert.Equal(t, content.Cards, expectedCards)
+	}
+
+	test("", []core.Card{})
+	test("No cards here", []core.Card{})
+	test("::Missing question\nMissing answer::", []core.Card{})
+	test("```\nIn a code block::Not a card\n```", []core.Card{})
+	test("Call `std::vector` in code", []core.Card{})
+	test("Use std::io in Rust", []core.Card{})
+	test("The :a::b: tags", []core.Card{})
+	test("Ping fe80::1 on the link", []core.Card{})
+	test("Apple::Manzana", []core.Card{})
+	test(" :: Missing question\nMissing answer :: ", []core.Card{})
+	test("Call `` a :: b `` in code", []core.Card{})
+
+	test(`# Capitals
+
+Intro paragraph
+What is the capital of France? :: Paris
+- Italy :: Rome
+- Not a card
+`, []core.Card{
+		core.NewCard("What is the capital of France?", "Paris", 4),
+		core.NewCard("Italy", "Rome", 5),
+	})
+
+	test(`# Study
+
+## What is SM-2? #card
+
+A spaced repetition
+algorithm.
+
+### Details
+
+Created in 1987.
+
+## Next section
+
+Not in the answer.
+
+## Empty #card
+
+Setext card #card
+-----------------
+
+Answer
+`, []core.Card{
+		core.NewCard("What is SM-2?", "A spaced repetition\nalgorithm.\n\n### Details\n\nCreated in 1987.", 3),
+		core.NewCard("Setext card", "Answer", 18),
+	})
+}
+
 func parse(t *testing.T, source string) core.NoteContent {
 	return parseWithOptions(t, source, ParserOpts{
 		HashtagEnabled:      true,
@@ -0,0 +1,195 @@
+package markdown
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/zk-org/zk/internal/adapter/markdown/extensions"
+)
+
+// Span is a range of bytes in the source of a document.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Contains returns whether the given byte offset is inside the span.
+func (s Span) Contains(offset int) bool {
+	return offset >= s.Start && offset <= s.End
+}
+
+// Section is a heading with the content following it, until the next heading
+// of the same or a higher level.
+type Section struct {
+	Span
+	// Level of the heading, from 1 to 6.
+	Level int
+	// Heading is the span of the heading line.
+	Heading Span
+}
+
+// TaskSpan is the mark of a task checkbox, e.g. the `x` in `- [x] Done`.
+type TaskSpan struct {
+	Span
+	Done bool
+}
+
+// LinkSpan is a wiki link found in the source of a document.
+type LinkSpan struct {
+	Span
+	Href string
+}
+
+// Structure holds the position of the syntactic elements of a Markdown
+// document, useful to implement editor features.
+type Structure struct {
+	// Frontmatter is the span of the YAML frontmatter, delimiters included.
+	Frontmatter *Span
+	// FrontmatterKeys holds the top-level keys of the YAML frontmatter.
+	FrontmatterKeys []Span
+	// Title is the span of the title of the note, either its frontmatter key
+	// or its heading, following the same rules as the indexed title.
+	Title *Span
+	// Sections holds the headings of the document, in order of appearance.
+	Sections []Section
+	// Blocks holds the leaf blocks of the document, e.g. paragraphs, headings
+	// or code blocks.
+	Blocks []Span
+	// Tags holds the #hashtags and :colon:tags: found in the document.
+	Tags []Span
+	// WikiLinks holds the [[wiki links]] found in the document.
+	WikiLinks []LinkSpan
+	// Tasks holds the checkbox marks of the task list items.
+	Tasks []TaskSpan
+}
+
+var frontmatterKeyRegex = regexp.MustCompile(`(?m)^([^\s#:-][^:\n]*):`)
+
+// ParseStructure parses the position of the syntactic elements of the given
+// note content.
+func (p *Parser) ParseStructure(content string) (*Structure, error) {
+	bytes := []byte(content)
+
+	context := parser.NewContext()
+	root := p.md.Parser().Parse(
+		text.NewReader(bytes),
+		parser.WithContext(context),
+	)
+
+	structure := &Structure{}
+
+	frontmatter, err := parseFrontmatter(context, bytes)
+	if err != nil {
+		return nil, err
+	}
+	// The frontmatter is only valid at the very top of the document.
+	if frontmatter.end > 0 && strings.TrimSpace(content[:frontmatter.start]) == "" {
+		structure.Frontmatter = &Span{Start: frontmatter.start, End: frontmatter.end}
+		hasTitle := !frontmatter.getString("title").IsNull()
+		for _, match := range frontmatterKeyRegex.FindAllStringSubmatchIndex(content[frontmatter.start:frontmatter.end], -1) {
+			key := Span{
+				Start: frontmatter.start + match[2],
+				End:   frontmatter.start + match[3],
+			}
+			structure.FrontmatterKeys = append(structure.FrontmatterKeys, key)
+			if hasTitle && structure.Title == nil && strings.EqualFold(content[key.Start:key.End], "title") {
+				structure.Title = &key
+			}
+		}
+	}
+
+	if structure.Title == nil {
+		heading, err := findTitleHeading(root)
+		if err != nil {
+			return nil, err
+		}
+		if heading != nil && heading.Lines().Len() > 0 {
+			lines := heading.Lines()
+			structure.Title = &Span{
+				Start: lineStart(bytes, lines.At(0).Start),
+				End:   lines.At(lines.Len() - 1).Stop,
+			}
+		}
+	}
+
+	err = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Heading:
+			if lines := node.Lines(); lines.Len() > 0 {
+				start := lineStart(bytes, lines.At(0).Start)
+				heading := Span{Start: start, End: lines.At(lines.Len() - 1).Stop}
+				structure.Sections = append(structure.Sections, Section{
+					Span:    heading,
+					Level:   node.Level,
+					Heading: heading,
+				})
+			}
+		case *extensions.Tags:
+			structure.Tags = append(structure.Tags, Span{Start: node.Segment.Start, End: node.Segment.Stop})
+		case *extensions.WikiLink:
+			structure.WikiLinks = append(structure.WikiLinks, LinkSpan{
+				Span: Span{Start: node.Segment.Start, End: node.Segment.Stop},
+				Href: string(node.Destination),
+			})
+		case *extast.TaskCheckBox:
+			// The checkbox starts the first line of its parent block.
+			if lines := node.Parent().Lines(); lines.Len() > 0 {
+				start := lines.At(0).Start + 1
+				structure.Tasks = append(structure.Tasks, TaskSpan{
+					Span: Span{Start: start, End: start + 1},
+					Done: node.IsChecked,
+				})
+			}
+		}
+
+		if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			lines := n.Lines()
+			structure.Blocks = append(structure.Blocks, Span{
+				Start: lineStart(bytes, lines.At(0).Start),
+				End:   lines.At(lines.Len() - 1).Stop,
+			})
+		}
+
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A section ends where the next heading of the same or a higher level
+	// starts.
+	for i := range structure.Sections {
+		section := &structure.Sections[i]
+		section.End = len(bytes)
+		for _, next := range structure.Sections[i+1:] {
+			if next.Level <= section.Level {
+				section.End = next.Start
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(structure.Blocks, func(i, j int) bool {
+		return structure.Blocks[i].Start < structure.Blocks[j].Start
+	})
+
+	return structure, nil
+}
+
+// lineStart returns the offset of the beginning of the line containing the
+// given offset.
+func lineStart(source []byte, offset int) int {
+	for offset > 0 && source[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
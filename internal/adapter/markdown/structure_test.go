@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestParseStructure(t *testing.T) {
+	source := `---
+title: A note
+tags: [a, b]
+---
+
+# Heading 1
+Paragraph with #hashtag and :colon:tag: and [[a1b2]].
+
+## Heading 1.1
+Text [[other | label]]
+
+# Heading 2
+`
+	parser := NewParser(ParserOpts{
+		HashtagEnabled:  true,
+		ColontagEnabled: true,
+	}, &util.NullLogger)
+
+	structure, err := parser.ParseStructure(source)
+	assert.Nil(t, err)
+
+	sub := func(span Span) string {
+		return source[span.Start:span.End]
+	}
+
+	assert.Equal(t, *structure.Frontmatter, Span{Start: 0, End: 35})
+	assert.Equal(t, len(structure.FrontmatterKeys), 2)
+	assert.Equal(t, sub(structure.FrontmatterKeys[0]), "title")
+	assert.Equal(t, sub(structure.FrontmatterKeys[1]), "tags")
+	assert.Equal(t, sub(*structure.Title), "title")
+
+	assert.Equal(t, len(structure.Sections), 3)
+	assert.Equal(t, sub(structure.Sections[0].Heading), "# Heading 1")
+	assert.Equal(t, structure.Sections[0].Level, 1)
+	assert.Equal(t, structure.Sections[0].End, structure.Sections[2].Start)
+	assert.Equal(t, sub(structure.Sections[1].Heading), "## Heading 1.1")
+	assert.Equal(t, structure.Sections[1].End, structure.Sections[2].Start)
+	assert.Equal(t, sub(structure.Sections[2].Heading), "# Heading 2")
+	assert.Equal(t, structure.Sections[2].End, len(source))
+
+	assert.Equal(t, len(structure.Tags), 2)
+	assert.Equal(t, sub(structure.Tags[0]), "#hashtag")
+	assert.Equal(t, sub(structure.Tags[1]), ":colon:tag:")
+
+	assert.Equal(t, len(structure.WikiLinks), 2)
+	assert.Equal(t, sub(structure.WikiLinks[0].Span), "[[a1b2]]")
+	assert.Equal(t, structure.WikiLinks[0].Href, "a1b2")
+	assert.Equal(t, sub(structure.WikiLinks[1].Span), "[[other | label]]")
+	assert.Equal(t, structure.WikiLinks[1].Href, "other")
+}
+
+func TestParseStructureIgnoresFrontmatterNotAtTheTop(t *testing.T) {
+	parser := NewParser(ParserOpts{}, &util.NullLogger)
+
+	structure, err := parser.ParseStructure("Paragraph\n\n---\n\nOther paragraph\n\n---\n")
+	assert.Nil(t, err)
+	assert.Nil(t, structure.Frontmatter)
+}
+
+func TestParseStructureTitle(t *testing.T) {
+	parser := NewParser(ParserOpts{}, &util.NullLogger)
+
+	test := func(source string, expected string) {
+		structure, err := parser.ParseStructure(source)
+		assert.Nil(t, err)
+		if expected == "" {
+			assert.Nil(t, structure.Title)
+		} else {
+			assert.Equal(t, source[structure.Title.Start:structure.Title.End], expected)
+		}
+	}
+
+	test("Some content\nwithout heading", "")
+	test("Intro\n\n## Sub\n\n# Title", "# Title")
+	test("## Sub\n\n### Other", "## Sub")
+	test("---\nid: a1b2\nTitle: A note\n---\n\n# Heading", "Title")
+	test("---\nid: a1b2\n---\n\n# Heading", "# Heading")
+	// An empty frontmatter title falls back on the heading.
+	test("---\ntitle: \"\"\n---\n\n# Heading", "# Heading")
+	// Headings in code blocks are not titles.
+	test("```\n# Not a title\n```\n\n## Title", "## Title")
+	test("Title\n=====\n", "Title")
+}
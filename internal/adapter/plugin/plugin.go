@@ -0,0 +1,180 @@
+package plugin
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Context describes the running zk instance to a plugin.
+type Context struct {
+	// Version of zk.
+	Version string `json:"version"`
+	// Absolute path to the root of the current notebook, if any.
+	NotebookDir string `json:"notebookDir,omitempty"`
+	// Working directory from which zk was started.
+	WorkingDir string `json:"workingDir,omitempty"`
+	// Configuration of the current notebook.
+	Config core.Config `json:"config"`
+}
+
+// ContextEnv is the environment variable holding the JSON Context given to
+// the plugin subcommands.
+const ContextEnv = "ZK_PLUGIN_CONTEXT"
+
+// RPCEnv is the environment variable set when a plugin is started as a
+// JSON-RPC server.
+const RPCEnv = "ZK_PLUGIN_RPC"
+
+// Lookup returns the path to the executable of the plugin with the given
+// name. Plugins declared in the config take precedence over the executables
+// named `zk-<name>` found in the PATH.
+func Lookup(name string, declared map[string]string) (string, bool) {
+	if path, ok := declared[name]; ok {
+		// Declared plugins can also be executables in the PATH.
+		if found, err := exec.LookPath(path); err == nil {
+			path = found
+		}
+		return path, true
+	}
+	path, err := exec.LookPath("zk-" + name)
+	return path, err == nil
+}
+
+// Command returns a Cmd running the plugin at path as a subcommand, with the
+// given arguments.
+func Command(path string, args []string, context Context) (*exec.Cmd, error) {
+	data, err := json.Marshal(context)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), ContextEnv+"="+string(data))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
+
+// Manifest lists the extensions registered by a plugin during the handshake.
+type Manifest struct {
+	// Template helpers implemented by the plugin.
+	Helpers []HelperSpec `json:"helpers"`
+	// Named filters, mapping a name to a list of `zk list` arguments.
+	Filters map[string]string `json:"filters"`
+}
+
+// HelperSpec declares a template helper implemented by a plugin.
+type HelperSpec struct {
+	Name string `json:"name"`
+	// Number of positional parameters expected by the helper.
+	Params int `json:"params"`
+}
+
+// handshakeTimeout is the delay after which a plugin not answering the
+// handshake is considered broken.
+const handshakeTimeout = 5 * time.Second
+
+// exitTimeout is the delay given to a plugin to exit after its standard input
+// is closed, before killing it.
+const exitTimeout = 1 * time.Second
+
+// Plugin is a running plugin process serving JSON-RPC requests.
+type Plugin struct {
+	Name     string
+	Manifest Manifest
+	client   *Client
+	cmd      *exec.Cmd
+	stdin    io.Closer
+}
+
+// Start launches the plugin at path as a JSON-RPC server and performs the
+// handshake.
+func Start(name string, path string, context Context) (*Plugin, error) {
+	wrap := errors.Wrapperf("%s plugin", name)
+
+	cmd := exec.Command(path)
+	cmd.Env = append(os.Environ(), RPCEnv+"=1")
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, wrap(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, wrap(err)
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	plugin, err := handshake(name, NewClient(stdout, stdin), context)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	plugin.cmd = cmd
+	plugin.stdin = stdin
+	return plugin, nil
+}
+
+// Close asks the plugin to exit by closing its standard input, and kills it
+// if it is still running after a short delay.
+func (p *Plugin) Close() {
+	if p.cmd == nil {
+		return
+	}
+	p.stdin.Close()
+
+	done := make(chan struct{})
+	go func() {
+		p.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(exitTimeout):
+		p.cmd.Process.Kill()
+		<-done
+	}
+}
+
+// handshake sends the initialize request to the plugin to retrieve its
+// manifest.
+func handshake(name string, client *Client, context Context) (*Plugin, error) {
+	plugin := &Plugin{Name: name, client: client}
+	err := client.CallWithTimeout("initialize", context, &plugin.Manifest, handshakeTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s plugin: handshake failed", name)
+	}
+	return plugin, nil
+}
+
+// helperParams holds the parameters of the helper request.
+type helperParams struct {
+	Name   string                 `json:"name"`
+	Params []interface{}          `json:"params"`
+	Hash   map[string]interface{} `json:"hash"`
+}
+
+// CallHelper renders the template helper with the given name.
+func (p *Plugin) CallHelper(name string, params []interface{}, hash map[string]interface{}) (string, error) {
+	if hash == nil {
+		hash = map[string]interface{}{}
+	}
+	var result string
+	err := p.client.Call("helper", helperParams{
+		Name:   name,
+		Params: params,
+		Hash:   hash,
+	}, &result)
+	return result, errors.Wrapf(err, "%s plugin", p.Name)
+}
@@ -0,0 +1,140 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+// fakePlugin answers the requests sent by a Client with the given handler.
+func fakePlugin(handler func(method string, params json.RawMessage) (interface{}, error)) *Client {
+	requestsR, requestsW := io.Pipe()
+	responsesR, responsesW := io.Pipe()
+
+	go func() {
+		scanner := bufio.NewScanner(requestsR)
+		encoder := json.NewEncoder(responsesW)
+		for scanner.Scan() {
+			var req struct {
+				ID     int             `json:"id"`
+				Method string          `json:"method"`
+				Params json.RawMessage `json:"params"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+				panic(err)
+			}
+			if req.Method == "exit" {
+				responsesW.Close()
+				return
+			}
+
+			res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			result, err := handler(req.Method, req.Params)
+			if err != nil {
+				res["error"] = map[string]interface{}{"code": -32601, "message": err.Error()}
+			} else {
+				res["result"] = result
+			}
+			encoder.Encode(res)
+		}
+	}()
+
+	return NewClient(responsesR, requestsW)
+}
+
+func TestPluginHandshakeAndHelper(t *testing.T) {
+	client := fakePlugin(func(method string, params json.RawMessage) (interface{}, error) {
+		switch method {
+		case "initialize":
+			var context struct {
+				NotebookDir string `json:"notebookDir"`
+			}
+			if err := json.Unmarshal(params, &context); err != nil {
+				return nil, err
+			}
+			return Manifest{
+				Helpers: []HelperSpec{{Name: "shout", Params: 1}},
+				Filters: map[string]string{"inbox": "--tag inbox " + context.NotebookDir},
+			}, nil
+		case "helper":
+			var p helperParams
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("%v! (%v)", p.Params[0], p.Hash["times"]), nil
+		default:
+			return nil, fmt.Errorf("unknown method %s", method)
+		}
+	})
+
+	plugin, err := handshake("test", client, Context{NotebookDir: "/notebook"})
+	assert.Nil(t, err)
+	assert.Equal(t, plugin.Manifest, Manifest{
+		Helpers: []HelperSpec{{Name: "shout", Params: 1}},
+		Filters: map[string]string{"inbox": "--tag inbox /notebook"},
+	})
+
+	res, err := plugin.CallHelper("shout", []interface{}{"hello"}, map[string]interface{}{"times": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, res, "hello! (2)")
+
+	err = client.Call("unknown", nil, nil)
+	assert.Err(t, err, "unknown: unknown method unknown (-32601)")
+}
+
+func TestPluginExited(t *testing.T) {
+	client := fakePlugin(func(method string, params json.RawMessage) (interface{}, error) {
+		return nil, nil
+	})
+
+	client.Call("exit", nil, nil)
+	err := client.Call("helper", nil, nil)
+	assert.Err(t, err, "helper: plugin exited")
+}
+
+func TestRegistryUsesCachedManifests(t *testing.T) {
+	// This executable fails the handshake, so the manifest can only come from
+	// the cache.
+	path := filepath.Join(t.TempDir(), "zk-broken")
+	err := os.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0755)
+	assert.Nil(t, err)
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+
+	manifest := Manifest{Helpers: []HelperSpec{{Name: "shout", Params: 1}}}
+	data, err := json.Marshal(cachedManifest{
+		Path:     path,
+		Size:     info.Size(),
+		ModTime:  info.ModTime().UnixNano(),
+		Manifest: manifest,
+	})
+	assert.Nil(t, err)
+	cache := manifestCacheMock{"zk.plugin.broken": string(data)}
+
+	registry := NewRegistry(map[string]string{"broken": path}, Context{}, cache, &util.NullLogger)
+	assert.Equal(t, registry.Manifests(), map[string]Manifest{"broken": manifest})
+	assert.Equal(t, len(registry.running), 0)
+
+	// The cache is ignored when the executable changed.
+	err = os.WriteFile(path, []byte("#!/bin/sh\nexit 42\n"), 0755)
+	assert.Nil(t, err)
+	assert.Equal(t, registry.Manifests(), map[string]Manifest{})
+}
+
+type manifestCacheMock map[string]string
+
+func (m manifestCacheMock) Metadata(key string) (string, error) {
+	return m[key], nil
+}
+
+func (m manifestCacheMock) SetMetadata(key string, value string) error {
+	m[key] = value
+	return nil
+}
@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// ManifestCache persists the plugin manifests between two runs of zk, e.g.
+// in the notebook index.
+type ManifestCache interface {
+	Metadata(key string) (string, error)
+	SetMetadata(key string, value string) error
+}
+
+// cachedManifest is the manifest of a plugin executable, saved in the
+// ManifestCache.
+type cachedManifest struct {
+	Path     string   `json:"path"`
+	Size     int64    `json:"size"`
+	ModTime  int64    `json:"modTime"`
+	Manifest Manifest `json:"manifest"`
+}
+
+// Registry holds the plugins declared in a notebook config. The plugin
+// processes are started only when one of their helpers is used, or when their
+// manifest is not cached yet.
+type Registry struct {
+	declared map[string]string
+	context  Context
+	cache    ManifestCache
+	logger   util.Logger
+
+	mu      sync.Mutex
+	running map[string]*Plugin
+	// Plugins which failed to start, to report them only once.
+	broken map[string]bool
+}
+
+// NewRegistry creates a Registry of the given declared plugins, mapping their
+// name to their executable.
+func NewRegistry(declared map[string]string, context Context, cache ManifestCache, logger util.Logger) *Registry {
+	return &Registry{
+		declared: declared,
+		context:  context,
+		cache:    cache,
+		logger:   logger,
+		running:  map[string]*Plugin{},
+		broken:   map[string]bool{},
+	}
+}
+
+// Manifests returns the manifests of the declared plugins, indexed by the
+// plugin names. Broken plugins are reported and skipped.
+func (r *Registry) Manifests() map[string]Manifest {
+	names := make([]string, 0, len(r.declared))
+	for name := range r.declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifests := map[string]Manifest{}
+	for _, name := range names {
+		manifest, err := r.manifest(name)
+		if err != nil {
+			r.logger.Err(err)
+			continue
+		}
+		manifests[name] = manifest
+	}
+	return manifests
+}
+
+// manifest returns the cached manifest of the plugin with the given name, or
+// starts the plugin to retrieve it if the executable changed.
+func (r *Registry) manifest(name string) (Manifest, error) {
+	path, _ := Lookup(name, r.declared)
+	info, err := os.Stat(path)
+	if err != nil {
+		return Manifest{}, errors.Wrapf(err, "%s plugin", name)
+	}
+	key := "zk.plugin." + name
+
+	if value, err := r.cache.Metadata(key); err == nil && value != "" {
+		var cached cachedManifest
+		err = json.Unmarshal([]byte(value), &cached)
+		if err == nil && cached.Path == path && cached.Size == info.Size() && cached.ModTime == info.ModTime().UnixNano() {
+			return cached.Manifest, nil
+		}
+	}
+
+	plugin, err := r.Plugin(name)
+	if err != nil {
+		return Manifest{}, err
+	}
+	data, err := json.Marshal(cachedManifest{
+		Path:     path,
+		Size:     info.Size(),
+		ModTime:  info.ModTime().UnixNano(),
+		Manifest: plugin.Manifest,
+	})
+	if err == nil {
+		err = r.cache.SetMetadata(key, string(data))
+	}
+	r.logger.Err(errors.Wrapf(err, "%s plugin: failed to cache the manifest", name))
+	return plugin.Manifest, nil
+}
+
+// Plugin returns the running plugin with the given name, starting it if
+// needed.
+func (r *Registry) Plugin(name string) (*Plugin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if plugin, ok := r.running[name]; ok {
+		return plugin, nil
+	}
+	if r.broken[name] {
+		return nil, fmt.Errorf("%s plugin: failed to start", name)
+	}
+	path, ok := Lookup(name, r.declared)
+	if !ok {
+		return nil, fmt.Errorf("%s: plugin not found", name)
+	}
+	plugin, err := Start(name, path, r.context)
+	if err != nil {
+		r.broken[name] = true
+		return nil, err
+	}
+	r.running[name] = plugin
+	return plugin, nil
+}
+
+// CallHelper renders the template helper with the given name implemented by
+// the plugin pluginName, which is started if needed.
+func (r *Registry) CallHelper(pluginName string, name string, params []interface{}, hash map[string]interface{}) (string, error) {
+	plugin, err := r.Plugin(pluginName)
+	if err != nil {
+		return "", err
+	}
+	return plugin.CallHelper(name, params, hash)
+}
+
+// Close stops the running plugins.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, plugin := range r.running {
+		plugin.Close()
+		delete(r.running, name)
+	}
+}
@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// Client sends JSON-RPC 2.0 requests to a plugin. Messages are exchanged as
+// one JSON object per line.
+type Client struct {
+	encoder *json.Encoder
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan response
+	// Error which stopped the reading of the responses.
+	err error
+}
+
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewClient creates a new Client reading the responses from r and writing
+// the requests to w.
+func NewClient(r io.Reader, w io.Writer) *Client {
+	client := &Client{
+		encoder: json.NewEncoder(w),
+		pending: map[int]chan response{},
+	}
+	go client.listen(json.NewDecoder(r))
+	return client
+}
+
+// listen dispatches the incoming responses to their pending requests.
+func (c *Client) listen(decoder *json.Decoder) {
+	for {
+		var res response
+		err := decoder.Decode(&res)
+
+		c.mu.Lock()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("plugin exited")
+			}
+			c.err = err
+			for id, ch := range c.pending {
+				close(ch)
+				delete(c.pending, id)
+			}
+			c.mu.Unlock()
+			return
+		}
+		ch, ok := c.pending[res.ID]
+		delete(c.pending, res.ID)
+		c.mu.Unlock()
+
+		if ok {
+			ch <- res
+		}
+	}
+}
+
+// Call sends a request and waits for its result, which is decoded into
+// result.
+func (c *Client) Call(method string, params interface{}, result interface{}) error {
+	return c.CallWithTimeout(method, params, result, 0)
+}
+
+// CallWithTimeout sends a request and waits for its result, which is decoded
+// into result. Fails if no response is received before the given timeout,
+// unless it is 0.
+func (c *Client) CallWithTimeout(method string, params interface{}, result interface{}, timeout time.Duration) error {
+	wrap := errors.Wrapper(method)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return wrap(c.err)
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	err := c.encoder.Encode(request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	c.writeMu.Unlock()
+	if err != nil {
+		c.forget(id)
+		return wrap(err)
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout)
+	}
+
+	select {
+	case res, ok := <-ch:
+		if !ok {
+			c.mu.Lock()
+			err = c.err
+			c.mu.Unlock()
+			return wrap(err)
+		}
+		if res.Error != nil {
+			return wrap(fmt.Errorf("%s (%d)", res.Error.Message, res.Error.Code))
+		}
+		if result == nil {
+			return nil
+		}
+		return wrap(json.Unmarshal(res.Result, result))
+
+	case <-expired:
+		c.forget(id)
+		return wrap(fmt.Errorf("no response after %v", timeout))
+	}
+}
+
+func (c *Client) forget(id int) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
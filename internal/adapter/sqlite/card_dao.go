@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+)
+
+// CardDAO persists the flashcards of notes and their review schedule in the
+// SQLite database.
+type CardDAO struct {
+	tx     Transaction
+	logger util.Logger
+
+	// Prepared SQL statements
+	addCardStmt      *LazyStmt
+	removeCardsStmt  *LazyStmt
+	saveScheduleStmt *LazyStmt
+}
+
+// NewCardDAO creates a new instance of a DAO working on the given database
+// transaction.
+func NewCardDAO(tx Transaction, logger util.Logger) *CardDAO {
+	return &CardDAO{
+		tx:     tx,
+		logger: logger,
+
+		// Add a new card.
+		addCardStmt: tx.PrepareLazy(`
+			INSERT INTO cards (note_id, hash, question, answer, line)
+			VALUES (?, ?, ?, ?, ?)
+		`),
+
+		// Remove all the cards of a note. Their schedule is kept, in case
+		// they are added again.
+		removeCardsStmt: tx.PrepareLazy(`
+			DELETE FROM cards
+			 WHERE note_id = ?
+		`),
+
+		// Insert or replace the review schedule of a card.
+		saveScheduleStmt: tx.PrepareLazy(`
+			INSERT INTO cards_schedules (hash, repetitions, interval, ease, lapses, due, reviewed)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(hash) DO UPDATE SET
+				repetitions = excluded.repetitions,
+				interval = excluded.interval,
+				ease = excluded.ease,
+				lapses = excluded.lapses,
+				due = excluded.due,
+				reviewed = excluded.reviewed
+		`),
+	}
+}
+
+// Add inserts the given cards of a note.
+func (d *CardDAO) Add(noteID core.NoteID, cards []core.Card) error {
+	for _, card := range cards {
+		_, err := d.addCardStmt.Exec(noteIDToSQL(noteID), card.Hash, card.Question, card.Answer, card.Line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveAll removes all the cards of the given note.
+func (d *CardDAO) RemoveAll(noteID core.NoteID) error {
+	_, err := d.removeCardsStmt.Exec(noteIDToSQL(noteID))
+	return err
+}
+
+// SaveSchedule persists the review schedule of the card with the given hash.
+func (d *CardDAO) SaveSchedule(hash string, schedule core.CardSchedule) error {
+	_, err := d.saveScheduleStmt.Exec(
+		hash, schedule.Repetitions, schedule.Interval, schedule.Ease,
+		schedule.Lapses, schedule.Due.UTC(), schedule.Reviewed.UTC(),
+	)
+	return err
+}
+
+// Find returns the cards matching the given filtering options. The cards due
+// for a review come first, by due date, followed by the cards never reviewed.
+//
+// The cards sharing the same question also share their review schedule, so
+// only the first one is returned.
+func (d *CardDAO) Find(opts core.CardFindOpts) ([]core.NoteCard, error) {
+	cards := make([]core.NoteCard, 0)
+	found := map[string]bool{}
+
+	whereExprs := []string{}
+	args := []interface{}{}
+
+	if opts.DueBefore != nil {
+		whereExprs = append(whereExprs, "(s.hash IS NULL OR s.due <= ?)")
+		args = append(args, opts.DueBefore.UTC())
+	}
+	if opts.NoteIDs != nil {
+		whereExprs = append(whereExprs, "c.note_id IN ("+joinNoteIDs(opts.NoteIDs, ",")+")")
+	}
+
+	query := `
+		SELECT c.hash, c.question, c.answer, c.line, n.path, n.title,
+		       s.repetitions, s.interval, s.ease, s.lapses, s.due, s.reviewed
+		  FROM cards c
+		 INNER JOIN notes n ON n.id = c.note_id
+		  LEFT JOIN cards_schedules s ON s.hash = c.hash
+	`
+	if len(whereExprs) > 0 {
+		query += "\n WHERE " + strings.Join(whereExprs, "\n   AND ")
+	}
+	query += "\n ORDER BY s.due IS NULL, s.due, n.sortable_path, c.line"
+
+	rows, err := d.tx.Query(query, args...)
+	if err != nil {
+		return cards, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		card, err := d.scanCard(rows)
+		if err != nil {
+			d.logger.Err(err)
+			continue
+		}
+		if found[card.Hash] {
+			continue
+		}
+		found[card.Hash] = true
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+func (d *CardDAO) scanCard(row RowScanner) (core.NoteCard, error) {
+	var (
+		line                          int
+		hash, question, answer        string
+		path, title                   string
+		repetitions, interval, lapses sql.NullInt64
+		ease                          sql.NullFloat64
+		due, reviewed                 sql.NullTime
+	)
+
+	err := row.Scan(
+		&hash, &question, &answer, &line, &path, &title,
+		&repetitions, &interval, &ease, &lapses, &due, &reviewed,
+	)
+	if err != nil {
+		return core.NoteCard{}, err
+	}
+
+	card := core.NoteCard{
+		Card: core.Card{
+			Hash:     hash,
+			Question: question,
+			Answer:   answer,
+			Line:     line,
+		},
+		Path:  path,
+		Title: title,
+	}
+	if due.Valid {
+		card.Schedule = &core.CardSchedule{
+			Repetitions: int(repetitions.Int64),
+			Interval:    int(interval.Int64),
+			Ease:        ease.Float64,
+			Lapses:      int(lapses.Int64),
+			Due:         due.Time,
+			Reviewed:    reviewed.Time,
+		}
+	}
+	return card, nil
+}
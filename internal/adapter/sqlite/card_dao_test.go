@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func testCardDAO(t *testing.T, callback func(tx Transaction, dao *CardDAO)) {
+	testTransaction(t, func(tx Transaction) {
+		callback(tx, NewCardDAO(tx, &util.NullLogger))
+	})
+}
+
+func TestCardDAOAdd(t *testing.T) {
+	testCardDAO(t, func(tx Transaction, dao *CardDAO) {
+		err := dao.Add(2, []core.Card{
+			core.NewCard("Who wrote Dune?", "Frank Herbert", 2),
+			core.NewCard("What is zk?", "A note-taking tool", 4),
+		})
+		assert.Nil(t, err)
+
+		cards, err := dao.Find(core.CardFindOpts{NoteIDs: []core.NoteID{2}})
+		assert.Nil(t, err)
+		assert.Equal(t, len(cards), 2)
+		assert.Equal(t, cards[0].Question, "Who wrote Dune?")
+		assert.Equal(t, cards[0].Answer, "Frank Herbert")
+		assert.Equal(t, cards[0].Line, 2)
+		assert.Equal(t, cards[0].Path, "log/2021-01-04.md")
+		assert.Equal(t, cards[1].Question, "What is zk?")
+		assert.Nil(t, cards[1].Schedule)
+	})
+}
+
+func TestCardDAOFindDuplicateQuestions(t *testing.T) {
+	testCardDAO(t, func(tx Transaction, dao *CardDAO) {
+		assert.Nil(t, dao.Add(2, []core.Card{core.NewCard("What is zk?", "A note-taking tool", 2)}))
+		assert.Nil(t, dao.Add(4, []core.Card{core.NewCard("What  is ZK?", "A CLI", 5)}))
+
+		// The duplicate questions share a single schedule, so they are
+		// reviewed once.
+		cards, err := dao.Find(core.CardFindOpts{NoteIDs: []core.NoteID{2, 4}})
+		assert.Nil(t, err)
+		assert.Equal(t, len(cards), 1)
+		assert.Equal(t, cards[0].Answer, "A CLI")
+		assert.Equal(t, cards[0].Path, "f39c8.md")
+	})
+}
+
+func TestCardDAORemoveAll(t *testing.T) {
+	testCardDAO(t, func(tx Transaction, dao *CardDAO) {
+		err := dao.RemoveAll(1)
+		assert.Nil(t, err)
+		assertNotExistTx(t, tx, "SELECT id FROM cards WHERE note_id = ?", 1)
+		assertExistTx(t, tx, "SELECT id FROM cards WHERE note_id = ?", 3)
+		// The review schedules are kept.
+		assertExistTx(t, tx, "SELECT hash FROM cards_schedules WHERE hash = ?", "b81d2e4f6a0c3957")
+	})
+}
+
+func TestCardDAOSaveSchedule(t *testing.T) {
+	testCardDAO(t, func(tx Transaction, dao *CardDAO) {
+		due := time.Date(2021, 1, 20, 0, 0, 0, 0, time.UTC)
+		reviewed := time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC)
+		schedule := core.CardSchedule{
+			Repetitions: 3,
+			Interval:    15,
+			Ease:        2.7,
+			Lapses:      0,
+			Due:         due,
+			Reviewed:    reviewed,
+		}
+
+		// Update an existing schedule.
+		err := dao.SaveSchedule("b81d2e4f6a0c3957", schedule)
+		assert.Nil(t, err)
+		// Create a new schedule.
+		err = dao.SaveSchedule("4a5f0c1e2d3b6a79", schedule)
+		assert.Nil(t, err)
+
+		cards, err := dao.Find(core.CardFindOpts{NoteIDs: []core.NoteID{1}})
+		assert.Nil(t, err)
+		assert.Equal(t, len(cards), 2)
+		for _, card := range cards {
+			assert.NotNil(t, card.Schedule)
+			assert.Equal(t, card.Schedule.Repetitions, 3)
+			assert.Equal(t, card.Schedule.Interval, 15)
+			assert.Equal(t, card.Schedule.Ease, 2.7)
+			assert.Equal(t, card.Schedule.Due.Equal(due), true)
+			assert.Equal(t, card.Schedule.Reviewed.Equal(reviewed), true)
+		}
+	})
+}
+
+func TestCardDAOFind(t *testing.T) {
+	test := func(opts core.CardFindOpts, expected []string) {
+		testCardDAO(t, func(tx Transaction, dao *CardDAO) {
+			cards, err := dao.Find(opts)
+			assert.Nil(t, err)
+			actual := []string{}
+			for _, card := range cards {
+				actual = append(actual, card.Path+":"+card.Question)
+			}
+			assert.Equal(t, actual, expected)
+		})
+	}
+
+	// The scheduled cards are sorted by due date, before the new cards.
+	test(core.CardFindOpts{}, []string{
+		"index.md:What is SM-2?",
+		"log/2021-01-03.md:Who wrote Dune?",
+		"log/2021-01-03.md:What is the capital of France?",
+	})
+
+	dueBefore := time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC)
+	test(core.CardFindOpts{DueBefore: &dueBefore}, []string{
+		"index.md:What is SM-2?",
+		"log/2021-01-03.md:What is the capital of France?",
+	})
+
+	test(core.CardFindOpts{NoteIDs: []core.NoteID{3}}, []string{
+		"index.md:What is SM-2?",
+	})
+
+	test(core.CardFindOpts{NoteIDs: []core.NoteID{}}, []string{})
+}
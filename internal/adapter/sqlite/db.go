@@ -217,6 +217,87 @@ func (db *DB) migrate() error {
 				// https://github.com/zk-org/zk/issues/170#issuecomment-1107848441
 				NeedsReindexing: true,
 			},
+
+			{ // 8
+				SQL: []string{
+					// Tasks
+					`CREATE TABLE IF NOT EXISTS tasks (
+						id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+						note_id INTEGER NOT NULL REFERENCES notes(id)
+							ON DELETE CASCADE,
+						text TEXT DEFAULT('') NOT NULL,
+						done INT DEFAULT(0) NOT NULL,
+						line INTEGER DEFAULT(0) NOT NULL,
+						due DATETIME,
+						tags TEXT DEFAULT('') NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS index_tasks_note_id ON tasks (note_id)`,
+				},
+				NeedsReindexing: true,
+			},
+
+			{ // 9
+				SQL: []string{
+					// Add the path of the local file targeted by a link, when
+					// it is not a note, e.g. an image.
+					`ALTER TABLE links ADD COLUMN asset_path TEXT DEFAULT('') NOT NULL`,
+				},
+				NeedsReindexing: true,
+			},
+
+			{ // 10
+				SQL: []string{
+					// Term vectors of the notes, used to find similar notes.
+					`CREATE TABLE IF NOT EXISTS notes_terms (
+						note_id INTEGER NOT NULL REFERENCES notes(id)
+							ON DELETE CASCADE,
+						term TEXT NOT NULL,
+						weight REAL NOT NULL,
+						PRIMARY KEY(note_id, term)
+					)`,
+					`CREATE INDEX IF NOT EXISTS index_notes_terms_term ON notes_terms (term)`,
+				},
+				NeedsReindexing: true,
+			},
+
+			{ // 11
+				SQL: []string{
+					// Flashcards
+					`CREATE TABLE IF NOT EXISTS cards (
+						id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+						note_id INTEGER NOT NULL REFERENCES notes(id)
+							ON DELETE CASCADE,
+						hash TEXT NOT NULL,
+						question TEXT DEFAULT('') NOT NULL,
+						answer TEXT DEFAULT('') NOT NULL,
+						line INTEGER DEFAULT(0) NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS index_cards_note_id ON cards (note_id)`,
+					`CREATE INDEX IF NOT EXISTS index_cards_hash ON cards (hash)`,
+
+					// Review schedule of the flashcards, keyed by their hash
+					// to survive the reindexing of the notes.
+					`CREATE TABLE IF NOT EXISTS cards_schedules (
+						hash TEXT PRIMARY KEY NOT NULL,
+						repetitions INTEGER DEFAULT(0) NOT NULL,
+						interval INTEGER DEFAULT(0) NOT NULL,
+						ease REAL DEFAULT(2.5) NOT NULL,
+						lapses INTEGER DEFAULT(0) NOT NULL,
+						due DATETIME NOT NULL,
+						reviewed DATETIME NOT NULL
+					)`,
+				},
+				NeedsReindexing: true,
+			},
+
+			{ // 12
+				SQL: []string{
+					// Date of the last revision of the note in the notebook
+					// history, kept apart from the date of the file which is
+					// used to detect the changes.
+					`ALTER TABLE notes ADD COLUMN history_modified DATETIME`,
+				},
+			},
 		}
 
 		needsReindexing := false
@@ -28,8 +28,8 @@ func NewLinkDAO(tx Transaction, logger util.Logger) *LinkDAO {
 
 		// Add a new link.
 		addLinkStmt: tx.PrepareLazy(`
-			INSERT INTO links (source_id, target_id, title, href, type, external, rels, snippet, snippet_start, snippet_end)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO links (source_id, target_id, title, href, type, external, rels, snippet, snippet_start, snippet_end, asset_path)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`),
 
 		// Remove all the outbound links of a note.
@@ -52,7 +52,7 @@ func (d *LinkDAO) Add(links []core.ResolvedLink) error {
 		sourceID := noteIDToSQL(link.SourceID)
 		targetID := noteIDToSQL(link.TargetID)
 
-		_, err := d.addLinkStmt.Exec(sourceID, targetID, link.Title, link.Href, link.Type, link.IsExternal, joinLinkRels(link.Rels), link.Snippet, link.SnippetStart, link.SnippetEnd)
+		_, err := d.addLinkStmt.Exec(sourceID, targetID, link.Title, link.Href, link.Type, link.IsExternal, joinLinkRels(link.Rels), link.Snippet, link.SnippetStart, link.SnippetEnd, link.AssetPath)
 		if err != nil {
 			return err
 		}
@@ -87,9 +87,10 @@ func joinLinkRels(rels []core.LinkRelation) string {
 	return res
 }
 
-// FindInternal returns all the links internal to the notebook.
+// FindInternal returns all the links internal to the notebook, except the
+// embedded images.
 func (d *LinkDAO) FindInternal() ([]core.ResolvedLink, error) {
-	return d.findWhere("external = 0")
+	return d.findWhere("external = 0 AND type != '" + string(core.LinkTypeImage) + "'")
 }
 
 // FindBetweenNotes returns all the links existing between the given notes.
@@ -98,6 +99,36 @@ func (d *LinkDAO) FindBetweenNotes(ids []core.NoteID) ([]core.ResolvedLink, erro
 	return d.findWhere(fmt.Sprintf("source_id IN (%s) AND target_id IN (%s)", idsString, idsString))
 }
 
+// FindAssets returns the links targeting local files which are not notes,
+// e.g. images.
+func (d *LinkDAO) FindAssets() ([]core.AssetLink, error) {
+	links := make([]core.AssetLink, 0)
+
+	rows, err := d.tx.Query(`
+		SELECT l.asset_path, n.path
+		  FROM links l
+		 INNER JOIN notes n ON n.id = l.source_id
+		 WHERE l.asset_path != '' AND l.target_id IS NULL
+		 ORDER BY l.asset_path, n.sortable_path
+	`)
+	if err != nil {
+		return links, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var link core.AssetLink
+		err := rows.Scan(&link.Path, &link.SourcePath)
+		if err != nil {
+			d.logger.Err(err)
+			continue
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
 // findWhere returns all the links, filtered by the given where query.
 func (d *LinkDAO) findWhere(where string) ([]core.ResolvedLink, error) {
 	links := make([]core.ResolvedLink, 0)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -46,14 +47,14 @@ func NewNoteDAO(tx Transaction, logger util.Logger) *NoteDAO {
 
 		// Add a new note to the index.
 		addStmt: tx.PrepareLazy(`
-			INSERT INTO notes (path, sortable_path, title, lead, body, raw_content, word_count, metadata, checksum, created, modified)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO notes (path, sortable_path, title, lead, body, raw_content, word_count, metadata, checksum, created, modified, history_modified)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`),
 
 		// Update the content of a note.
 		updateStmt: tx.PrepareLazy(`
 			UPDATE notes
-			   SET title = ?, lead = ?, body = ?, raw_content = ?, word_count = ?, metadata = ?, checksum = ?, modified = ?
+			   SET title = ?, lead = ?, body = ?, raw_content = ?, word_count = ?, metadata = ?, checksum = ?, modified = ?, history_modified = ?
 			 WHERE path = ?
 		`),
 
@@ -138,7 +139,7 @@ func (d *NoteDAO) Add(note core.Note) (core.NoteID, error) {
 	res, err := d.addStmt.Exec(
 		note.Path, sortablePath, note.Title, note.Lead, note.Body,
 		note.RawContent, note.WordCount, metadata, note.Checksum, note.Created,
-		note.Modified,
+		note.Modified, note.HistoryModified,
 	)
 	if err != nil {
 		return 0, err
@@ -165,7 +166,7 @@ func (d *NoteDAO) Update(note core.Note) (core.NoteID, error) {
 	metadata := d.metadataToJSON(note)
 	_, err = d.updateStmt.Exec(
 		note.Title, note.Lead, note.Body, note.RawContent, note.WordCount,
-		metadata, note.Checksum, note.Modified, note.Path,
+		metadata, note.Checksum, note.Modified, note.HistoryModified, note.Path,
 	)
 	return id, err
 }
@@ -443,8 +444,39 @@ const (
 	noteSelectionFull
 )
 
+// maxSimilarCandidates is the number of most similar notes considered when
+// finding the notes similar to others. Each candidate binds two variables in
+// the score column, which must stay under the SQLite variable limit.
+const maxSimilarCandidates = 1000
+
+// scoreOrderTerm orders the notes by decreasing similarity score.
+const scoreOrderTerm = "score DESC"
+
+// topSimilarIDs returns the IDs of the notes with the highest scores, up to
+// the given limit.
+func topSimilarIDs(scores map[core.NoteID]float64, limit int) []core.NoteID {
+	ids := make([]core.NoteID, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
 func (d *NoteDAO) findRows(opts core.NoteFindOpts, selection noteSelection) (*sql.Rows, error) {
 	snippetCol := `n.lead`
+	scoreCol := `0`
+	// Arguments of the score column, bound either in the selected columns or
+	// in the order terms.
+	scoreArgs := []interface{}{}
 	joinClauses := []string{}
 	whereExprs := []string{}
 	additionalOrderTerms := []string{}
@@ -613,6 +645,21 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 		}
 	}
 
+	if len(opts.AnyTags) > 0 {
+		placeholders := make([]string, 0, len(opts.AnyTags))
+		for _, tag := range opts.AnyTags {
+			placeholders = append(placeholders, "?")
+			args = append(args, tag)
+		}
+		whereExprs = append(whereExprs, fmt.Sprintf(`n.id IN (
+SELECT note_id FROM notes_collections
+WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND t.name IN (%s))
+)`,
+			core.CollectionKindTag,
+			strings.Join(placeholders, ", "),
+		))
+	}
+
 	if opts.MentionedBy != nil {
 		ids, err := d.findIdsByHrefs(opts.MentionedBy, true /* allowPartialHrefs */)
 		if err != nil {
@@ -656,6 +703,33 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 		groupBy += " HAVING MIN(l_rel.distance) = 2"
 	}
 
+	if opts.SimilarTo != nil {
+		ids, err := d.findIdsByHrefs(opts.SimilarTo, true /* allowPartialHrefs */)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("could not find notes at: %s", strings.Join(opts.SimilarTo, ", "))
+		}
+
+		scores, err := NewTermDAO(d.tx, d.logger).Similarities(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		similarIDs := topSimilarIDs(scores, maxSimilarCandidates)
+		scoreCases := ""
+		for _, id := range similarIDs {
+			scoreCases += " WHEN ? THEN ?"
+			scoreArgs = append(scoreArgs, int64(id), scores[id])
+		}
+		whereExprs = append(whereExprs, "n.id IN ("+joinNoteIDs(similarIDs, ",")+")")
+		if scoreCases != "" {
+			scoreCol = "CASE n.id" + scoreCases + " ELSE 0 END"
+		}
+		additionalOrderTerms = append(additionalOrderTerms, scoreOrderTerm)
+	}
+
 	if opts.Orphan {
 		whereExprs = append(whereExprs, `n.id NOT IN (
 			SELECT target_id FROM links WHERE target_id IS NOT NULL
@@ -690,12 +764,12 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 	}
 
 	if opts.ModifiedStart != nil {
-		whereExprs = append(whereExprs, "modified >= ?")
+		whereExprs = append(whereExprs, modifiedColumn+" >= ?")
 		args = append(args, opts.ModifiedStart)
 	}
 
 	if opts.ModifiedEnd != nil {
-		whereExprs = append(whereExprs, "modified < ?")
+		whereExprs = append(whereExprs, modifiedColumn+" < ?")
 		args = append(args, opts.ModifiedEnd)
 	}
 
@@ -707,6 +781,10 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 		whereExprs = append(whereExprs, "n.id NOT IN ("+joinNoteIDs(opts.ExcludeIDs, ",")+")")
 	}
 
+	if opts.HistoryIDs != nil {
+		whereExprs = append(whereExprs, "n.id IN ("+joinNoteIDs(opts.HistoryIDs, ",")+")")
+	}
+
 	orderTerms := []string{}
 	for _, sorter := range opts.Sorters {
 		orderTerms = append(orderTerms, orderTerm(sorter))
@@ -714,6 +792,15 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 	orderTerms = append(orderTerms, additionalOrderTerms...)
 	orderTerms = append(orderTerms, `n.title ASC`)
 
+	// The score is ordered by its alias when it is selected, to bind its
+	// arguments only once.
+	scoreSelected := selection == noteSelectionFull
+	for i, term := range orderTerms {
+		if term == scoreOrderTerm && !scoreSelected {
+			orderTerms[i] = scoreCol + " DESC"
+		}
+	}
+
 	query := ""
 
 	// Credit to https://inviqa.com/blog/storing-graphs-database-sql-meets-social-network
@@ -748,9 +835,12 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 	if selection != noteSelectionID {
 		query += ", n.path, n.title, n.metadata"
 		if selection != noteSelectionMinimal {
-			query += fmt.Sprintf(", n.lead, n.body, n.raw_content, n.word_count, n.created, n.modified, n.checksum, n.tags, %s AS snippet", snippetCol)
+			query += fmt.Sprintf(", n.lead, n.body, n.raw_content, n.word_count, n.created, n.modified, n.history_modified, n.checksum, n.tags, %s AS snippet, %s AS score", snippetCol, scoreCol)
 		}
 	}
+	if scoreSelected {
+		args = append(append([]interface{}{}, scoreArgs...), args...)
+	}
 
 	query += "\nFROM notes_with_metadata n\n"
 
@@ -767,6 +857,9 @@ WHERE collection_id IN (SELECT id FROM collections t WHERE kind = '%s' AND (%s))
 	}
 
 	query += "ORDER BY " + strings.Join(orderTerms, ", ") + "\n"
+	if !scoreSelected {
+		args = append(args, scoreArgs...)
+	}
 
 	if opts.Limit > 0 {
 		query += fmt.Sprintf("LIMIT %d\n", opts.Limit)
@@ -825,11 +918,13 @@ func (d *NoteDAO) scanNote(row RowScanner) (*core.ContextualNote, error) {
 		snippets, tags                sql.NullString
 		path, metadataJSON, checksum  string
 		created, modified             time.Time
+		historyModified               sql.NullTime
+		score                         float64
 	)
 
 	err := row.Scan(
 		&id, &path, &title, &metadataJSON, &lead, &body, &rawContent,
-		&wordCount, &created, &modified, &checksum, &tags, &snippets,
+		&wordCount, &created, &modified, &historyModified, &checksum, &tags, &snippets, &score,
 	)
 	switch {
 	case err == sql.ErrNoRows:
@@ -842,27 +937,39 @@ func (d *NoteDAO) scanNote(row RowScanner) (*core.ContextualNote, error) {
 			d.logger.Err(errors.Wrap(err, path))
 		}
 
+		var historyModifiedPtr *time.Time
+		if historyModified.Valid {
+			historyModifiedPtr = &historyModified.Time
+			modified = historyModified.Time
+		}
+
 		return &core.ContextualNote{
 			Snippets: parseListFromNullString(snippets),
+			Score:    score,
 			Note: core.Note{
-				ID:         core.NoteID(id),
-				Path:       path,
-				Title:      title,
-				Lead:       lead,
-				Body:       body,
-				RawContent: rawContent,
-				WordCount:  wordCount,
-				Links:      []core.Link{},
-				Tags:       parseListFromNullString(tags),
-				Metadata:   metadata,
-				Created:    created,
-				Modified:   modified,
-				Checksum:   checksum,
+				ID:              core.NoteID(id),
+				Path:            path,
+				Title:           title,
+				Lead:            lead,
+				Body:            body,
+				RawContent:      rawContent,
+				WordCount:       wordCount,
+				Links:           []core.Link{},
+				Tags:            parseListFromNullString(tags),
+				Metadata:        metadata,
+				Created:         created,
+				Modified:        modified,
+				HistoryModified: historyModifiedPtr,
+				Checksum:        checksum,
 			},
 		}, nil
 	}
 }
 
+// modifiedColumn is the modification date of a note, taken from the notebook
+// history when available.
+const modifiedColumn = "IFNULL(n.history_modified, n.modified)"
+
 func orderTerm(sorter core.NoteSorter) string {
 	order := " ASC"
 	if !sorter.Ascending {
@@ -873,7 +980,7 @@ func orderTerm(sorter core.NoteSorter) string {
 	case core.NoteSortCreated:
 		return "n.created" + order
 	case core.NoteSortModified:
-		return "n.modified" + order
+		return modifiedColumn + order
 	case core.NoteSortPath:
 		return "n.path" + order
 	case core.NoteSortRandom:
@@ -177,6 +177,32 @@ func TestNoteDAOUpdate(t *testing.T) {
 	})
 }
 
+func TestNoteDAOUpdateWithHistoryModified(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		fileModified := time.Date(2020, 11, 22, 16, 49, 47, 0, time.UTC)
+		historyModified := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		_, err := dao.Update(core.Note{
+			Path:            "ref/test/a.md",
+			Modified:        fileModified,
+			HistoryModified: &historyModified,
+		})
+		assert.Nil(t, err)
+
+		// The file date is kept to detect the changes.
+		row, err := queryNoteRow(tx, `path = "ref/test/a.md"`)
+		assert.Nil(t, err)
+		assert.Equal(t, row.Modified, fileModified)
+
+		// The history date is reported and filtered.
+		start := time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)
+		notes, err := dao.Find(core.NoteFindOpts{ModifiedStart: &start})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 1)
+		assert.Equal(t, notes[0].Path, "ref/test/a.md")
+		assert.Equal(t, notes[0].Modified, historyModified)
+	})
+}
+
 func TestNoteDAOUpdateUnknown(t *testing.T) {
 	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
 		_, err := dao.Update(core.Note{
@@ -397,6 +423,18 @@ func TestNoteDAOFindLimit(t *testing.T) {
 	})
 }
 
+func TestNoteDAOFindAnyTags(t *testing.T) {
+	test := func(tags []string, expectedPaths []string) {
+		testNoteDAOFindPaths(t, core.NoteFindOpts{AnyTags: tags}, expectedPaths)
+	}
+
+	test([]string{"fiction", "fantasy"}, []string{"f39c8.md", "log/2021-01-03.md"})
+	// Tag names are not parsed as expressions or globs.
+	test([]string{"-fiction"}, []string{})
+	test([]string{"fiction OR fantasy"}, []string{})
+	test([]string{"fic*"}, []string{})
+}
+
 func TestNoteDAOFindTag(t *testing.T) {
 	test := func(tags []string, expectedPaths []string) {
 		testNoteDAOFindPaths(t, core.NoteFindOpts{Tags: tags}, expectedPaths)
@@ -624,6 +662,25 @@ func TestNoteDAOFindInMultiplePaths(t *testing.T) {
 	)
 }
 
+// The notes found in the history are restricted to the given paths, instead
+// of being added to them.
+func TestNoteDAOFindInPathWithHistoryIDs(t *testing.T) {
+	testNoteDAOFindPaths(t,
+		core.NoteFindOpts{
+			IncludeHrefs: []string{"log"},
+			HistoryIDs:   []core.NoteID{2, 3, 7},
+		},
+		[]string{"log/2021-02-04.md", "log/2021-01-04.md"},
+	)
+	testNoteDAOFindPaths(t,
+		core.NoteFindOpts{
+			IncludeHrefs: []string{"log"},
+			HistoryIDs:   []core.NoteID{},
+		},
+		[]string{},
+	)
+}
+
 func TestNoteDAOFindExcludingPath(t *testing.T) {
 	testNoteDAOFindPaths(t,
 		core.NoteFindOpts{
@@ -1009,6 +1066,44 @@ func TestNoteDAOFindRelated(t *testing.T) {
 	)
 }
 
+func TestNoteDAOFindSimilarTo(t *testing.T) {
+	testNoteDAO(t, func(tx Transaction, dao *NoteDAO) {
+		terms := NewTermDAO(tx, &util.NullLogger)
+		assert.Nil(t, terms.Add(1, core.Note{Title: "Gardening", Body: "Water the tomato plants in the garden."}))
+		assert.Nil(t, terms.Add(2, core.Note{Title: "Tomatoes", Body: "The tomato plants need water every day."}))
+		assert.Nil(t, terms.Add(3, core.Note{Title: "Garden", Body: "Water the flowers of the garden."}))
+		assert.Nil(t, terms.Add(4, core.Note{Title: "Concurrency", Body: "Threads share memory."}))
+
+		notes, err := dao.Find(core.NoteFindOpts{SimilarTo: []string{"log/2021-01-04.md"}})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 2)
+		assert.Equal(t, notes[0].Path, "log/2021-01-03.md")
+		assert.Equal(t, notes[1].Path, "index.md")
+		assert.Equal(t, notes[0].Score > notes[1].Score, true)
+
+		// The scores are bound along with the arguments of other filters.
+		start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		notes, err = dao.Find(core.NoteFindOpts{SimilarTo: []string{"log/2021-01-04.md"}, ModifiedStart: &start})
+		assert.Nil(t, err)
+		assert.Equal(t, len(notes), 2)
+		assert.Equal(t, notes[0].Path, "log/2021-01-03.md")
+
+		minimalNotes, err := dao.FindMinimal(core.NoteFindOpts{SimilarTo: []string{"log/2021-01-04.md"}, ModifiedStart: &start})
+		assert.Nil(t, err)
+		assert.Equal(t, len(minimalNotes), 2)
+		assert.Equal(t, minimalNotes[0].Path, "log/2021-01-03.md")
+
+		_, err = dao.Find(core.NoteFindOpts{SimilarTo: []string{"unknown.md"}})
+		assert.Err(t, err, "could not find notes at: unknown.md")
+	})
+}
+
+func TestTopSimilarIDs(t *testing.T) {
+	scores := map[core.NoteID]float64{1: 0.2, 2: 0.9, 3: 0.5, 4: 0.5}
+	assert.Equal(t, topSimilarIDs(scores, 10), []core.NoteID{2, 3, 4, 1})
+	assert.Equal(t, topSimilarIDs(scores, 2), []core.NoteID{2, 3})
+}
+
 func TestNoteDAOFindOrphan(t *testing.T) {
 	testNoteDAOFindPaths(t,
 		core.NoteFindOpts{Orphan: true},
@@ -26,6 +26,10 @@ type dao struct {
 	links       *LinkDAO
 	collections *CollectionDAO
 	metadata    *MetadataDAO
+	tasks       *TaskDAO
+	terms       *TermDAO
+	stats       *StatsDAO
+	cards       *CardDAO
 }
 
 func NewNoteIndex(notebookPath string, db *DB, logger util.Logger) *NoteIndex {
@@ -103,6 +107,49 @@ func (ni *NoteIndex) FindCollections(kind core.CollectionKind, sorters []core.Co
 	return
 }
 
+// FindTasks implements core.NoteIndex.
+func (ni *NoteIndex) FindTasks(opts core.TaskFindOpts) (tasks []core.NoteTask, err error) {
+	err = ni.commit(func(dao *dao) error {
+		tasks, err = dao.tasks.Find(opts)
+		return err
+	})
+	return
+}
+
+// FindAssetLinks implements core.NoteIndex.
+func (ni *NoteIndex) FindAssetLinks() (links []core.AssetLink, err error) {
+	err = ni.commit(func(dao *dao) error {
+		links, err = dao.links.FindAssets()
+		return err
+	})
+	return
+}
+
+// FindCards implements core.NoteIndex.
+func (ni *NoteIndex) FindCards(opts core.CardFindOpts) (cards []core.NoteCard, err error) {
+	err = ni.commit(func(dao *dao) error {
+		cards, err = dao.cards.Find(opts)
+		return err
+	})
+	return
+}
+
+// SaveCardSchedule implements core.NoteIndex.
+func (ni *NoteIndex) SaveCardSchedule(hash string, schedule core.CardSchedule) error {
+	return ni.commit(func(dao *dao) error {
+		return dao.cards.SaveSchedule(hash, schedule)
+	})
+}
+
+// Stats implements core.NoteIndex.
+func (ni *NoteIndex) Stats(opts core.StatsOpts) (stats core.NotebookStats, err error) {
+	err = ni.commit(func(dao *dao) error {
+		stats, err = dao.stats.Compute(opts)
+		return err
+	})
+	return
+}
+
 // IndexedPaths implements core.NoteIndex.
 func (ni *NoteIndex) IndexedPaths() (metadata <-chan paths.Metadata, err error) {
 	err = ni.commit(func(dao *dao) error {
@@ -132,6 +179,21 @@ func (ni *NoteIndex) Add(note core.Note) (id core.NoteID, err error) {
 			return err
 		}
 
+		err = dao.tasks.Add(id, note.Tasks)
+		if err != nil {
+			return err
+		}
+
+		err = dao.cards.Add(id, note.Cards)
+		if err != nil {
+			return err
+		}
+
+		err = dao.terms.Add(id, note)
+		if err != nil {
+			return err
+		}
+
 		return ni.associateTags(dao.collections, id, note.Tags)
 	})
 
@@ -235,6 +297,36 @@ func (ni *NoteIndex) Update(note core.Note) error {
 			return err
 		}
 
+		// Reset tasks
+		err = dao.tasks.RemoveAll(id)
+		if err != nil {
+			return err
+		}
+		err = dao.tasks.Add(id, note.Tasks)
+		if err != nil {
+			return err
+		}
+
+		// Reset cards
+		err = dao.cards.RemoveAll(id)
+		if err != nil {
+			return err
+		}
+		err = dao.cards.Add(id, note.Cards)
+		if err != nil {
+			return err
+		}
+
+		// Reset terms
+		err = dao.terms.RemoveAll(id)
+		if err != nil {
+			return err
+		}
+		err = dao.terms.Add(id, note)
+		if err != nil {
+			return err
+		}
+
 		// Reset tags
 		err = dao.collections.RemoveAssociations(id)
 		if err != nil {
@@ -273,9 +365,14 @@ func (ni *NoteIndex) resolveLinkNoteIDs(dao *dao, sourceID core.NoteID, links []
 	resolvedLinks := []core.ResolvedLink{}
 
 	for _, link := range links {
-		targetID, err := ni.findLinkMatch(dao, "" /* base dir */, link.Href, link.Type)
-		if err != nil {
-			return resolvedLinks, err
+		// Images never target notes.
+		var targetID core.NoteID
+		if link.Type != core.LinkTypeImage {
+			var err error
+			targetID, err = ni.findLinkMatch(dao, "" /* base dir */, link.Href, link.Type)
+			if err != nil {
+				return resolvedLinks, err
+			}
 		}
 
 		resolvedLinks = append(resolvedLinks, core.ResolvedLink{
@@ -329,6 +426,22 @@ func (ni *NoteIndex) SetNeedsReindexing(needsReindexing bool) error {
 	})
 }
 
+// Metadata implements core.NoteIndex.
+func (ni *NoteIndex) Metadata(key string) (value string, err error) {
+	err = ni.commit(func(dao *dao) error {
+		value, err = dao.metadata.Get(key)
+		return err
+	})
+	return
+}
+
+// SetMetadata implements core.NoteIndex.
+func (ni *NoteIndex) SetMetadata(key string, value string) error {
+	return ni.commit(func(dao *dao) error {
+		return dao.metadata.Set(key, value)
+	})
+}
+
 func (ni *NoteIndex) commit(transaction func(dao *dao) error) error {
 	if ni.dao != nil {
 		return transaction(ni.dao)
@@ -339,6 +452,10 @@ func (ni *NoteIndex) commit(transaction func(dao *dao) error) error {
 				links:       NewLinkDAO(tx, ni.logger),
 				collections: NewCollectionDAO(tx, ni.logger),
 				metadata:    NewMetadataDAO(tx),
+				tasks:       NewTaskDAO(tx, ni.logger),
+				terms:       NewTermDAO(tx, ni.logger),
+				stats:       NewStatsDAO(tx, ni.logger),
+				cards:       NewCardDAO(tx, ni.logger),
 			}
 			return transaction(&dao)
 		})
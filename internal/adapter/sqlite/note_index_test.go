@@ -180,6 +180,55 @@ func TestNoteIndexUpdateWithLinks(t *testing.T) {
 	})
 }
 
+func TestNoteIndexAddWithImageLinks(t *testing.T) {
+	db, index := testNoteIndex(t)
+
+	id, err := index.Add(core.Note{
+		Path: "log/images.md",
+		Links: []core.Link{
+			{Title: "Image", Href: "index", Type: core.LinkTypeImage},
+		},
+	})
+	assert.Nil(t, err)
+	// Adding a note matching the href doesn't resolve the image either.
+	_, err = index.Add(core.Note{Path: "log/index.md"})
+	assert.Nil(t, err)
+
+	rows := queryLinkRows(t, db.db, fmt.Sprintf("source_id = %d", id))
+	assert.Equal(t, rows, []linkRow{
+		{SourceId: id, TargetId: nil, Title: "Image", Href: "index", Type: "image"},
+	})
+}
+
+func TestNoteIndexFindAssetLinks(t *testing.T) {
+	_, index := testNoteIndex(t)
+
+	_, err := index.Add(core.Note{
+		Path: "log/assets.md",
+		Links: []core.Link{
+			{Title: "Image", Href: "log/img/cat.png", AssetPath: "log/img/cat.png"},
+			{Title: "Note", Href: "log/2021-01-04"},
+			{Title: "Document", Href: "doc.pdf#page=2", AssetPath: "doc.pdf"},
+		},
+	})
+	assert.Nil(t, err)
+	_, err = index.Add(core.Note{
+		Path: "added.md",
+		Links: []core.Link{
+			{Title: "Image", Href: "log/img/cat.png", AssetPath: "log/img/cat.png"},
+		},
+	})
+	assert.Nil(t, err)
+
+	links, err := index.FindAssetLinks()
+	assert.Nil(t, err)
+	assert.Equal(t, links, []core.AssetLink{
+		{Path: "doc.pdf", SourcePath: "log/assets.md"},
+		{Path: "log/img/cat.png", SourcePath: "added.md"},
+		{Path: "log/img/cat.png", SourcePath: "log/assets.md"},
+	})
+}
+
 func TestNoteIndexAddWithTags(t *testing.T) {
 	db, index := testNoteIndex(t)
 
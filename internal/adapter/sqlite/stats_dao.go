@@ -0,0 +1,218 @@
+package sqlite
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// StatsDAO computes aggregated statistics about the notes indexed in the
+// SQLite database.
+type StatsDAO struct {
+	tx     Transaction
+	logger util.Logger
+}
+
+// NewStatsDAO creates a new instance of a DAO working on the given database
+// transaction.
+func NewStatsDAO(tx Transaction, logger util.Logger) *StatsDAO {
+	return &StatsDAO{
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+// Compute returns the statistics of the notes matching the given options.
+func (d *StatsDAO) Compute(opts core.StatsOpts) (core.NotebookStats, error) {
+	wrap := errors.Wrapper("failed to compute the notebook statistics")
+	stats := core.NotebookStats{
+		Period:       opts.Period,
+		Created:      []core.StatsPeriodCount{},
+		Modified:     []core.StatsPeriodCount{},
+		Tags:         []core.StatsTag{},
+		LargestNotes: []core.StatsNote{},
+	}
+
+	// SQL clause selecting the notes aliased as n, all of them by default.
+	where := ""
+	if opts.NoteIDs != nil {
+		where = "WHERE n.id IN (" + joinNoteIDs(opts.NoteIDs, ",") + ")"
+	}
+	// SQL subquery listing the IDs of the selected notes.
+	selectedIDs := "SELECT n.id FROM notes n " + where
+
+	var incoming int
+	row := d.tx.QueryRow(`
+		SELECT COUNT(*),
+		       IFNULL(SUM(n.word_count), 0),
+		       (SELECT COUNT(DISTINCT nc.collection_id)
+		          FROM notes_collections nc
+		         INNER JOIN collections c ON c.id = nc.collection_id
+		         WHERE c.kind = '` + string(core.CollectionKindTag) + `'
+		           AND nc.note_id IN (` + selectedIDs + `)),
+		       (SELECT COUNT(*)
+		          FROM links l
+		         WHERE l.source_id IN (` + selectedIDs + `)
+		           AND l.target_id IS NOT NULL
+		           AND l.target_id != l.source_id),
+		       (SELECT COUNT(*)
+		          FROM links l
+		         WHERE l.target_id IN (` + selectedIDs + `)
+		           AND l.target_id != l.source_id),
+		       IFNULL(SUM(n.id NOT IN (SELECT target_id FROM links WHERE target_id IS NOT NULL)), 0)
+		  FROM notes n
+		 ` + where,
+	)
+	err := row.Scan(&stats.NoteCount, &stats.WordCount, &stats.TagCount, &stats.LinkCount, &incoming, &stats.OrphanCount)
+	if err != nil {
+		return stats, wrap(err)
+	}
+	if stats.NoteCount > 0 {
+		stats.OrphanRatio = round(float64(stats.OrphanCount) / float64(stats.NoteCount))
+		stats.AverageDegree = round(float64(stats.LinkCount+incoming) / float64(stats.NoteCount))
+	}
+
+	stats.Created, err = d.countByPeriod("n.created", where, opts)
+	if err != nil {
+		return stats, wrap(err)
+	}
+	stats.Modified, err = d.countByPeriod(modifiedColumn, where, opts)
+	if err != nil {
+		return stats, wrap(err)
+	}
+
+	stats.Tags, err = d.findTopTags(selectedIDs, opts.Limit)
+	if err != nil {
+		return stats, wrap(err)
+	}
+
+	stats.LargestNotes, err = d.findLargestNotes(where, opts.Limit)
+	if err != nil {
+		return stats, wrap(err)
+	}
+
+	return stats, nil
+}
+
+// countByPeriod counts the notes grouped by the period of the given date
+// expression. Only the most recent periods are returned, in chronological order.
+func (d *StatsDAO) countByPeriod(date string, where string, opts core.StatsOpts) ([]core.StatsPeriodCount, error) {
+	counts := []core.StatsPeriodCount{}
+
+	var period string
+	switch opts.Period {
+	case core.StatsPeriodWeek:
+		// ISO 8601 week, which belongs to the year of its Thursday.
+		thursday := "date(" + date + ", '-3 days', 'weekday 4')"
+		period = "printf('%s-W%02d', strftime('%Y', " + thursday + "), (strftime('%j', " + thursday + ") - 1) / 7 + 1)"
+	case core.StatsPeriodMonth, "":
+		period = "strftime('%Y-%m', " + date + ")"
+	default:
+		return counts, fmt.Errorf("%s: unknown period", opts.Period)
+	}
+
+	rows, err := d.tx.Query(`
+		SELECT `+period+` AS period, COUNT(*)
+		  FROM notes n
+		 `+where+`
+		 GROUP BY period
+		 ORDER BY period DESC
+		 LIMIT ?
+	`, sqlLimit(opts.Limit))
+	if err != nil {
+		return counts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count core.StatsPeriodCount
+		err := rows.Scan(&count.Period, &count.NoteCount)
+		if err != nil {
+			d.logger.Err(err)
+			continue
+		}
+		// Prepend to list the periods chronologically.
+		counts = append([]core.StatsPeriodCount{count}, counts...)
+	}
+
+	return counts, rows.Err()
+}
+
+// findTopTags returns the tags attached to the most notes among the
+// selected ones.
+func (d *StatsDAO) findTopTags(selectedIDs string, limit int) ([]core.StatsTag, error) {
+	tags := []core.StatsTag{}
+
+	rows, err := d.tx.Query(`
+		SELECT c.name, COUNT(DISTINCT nc.note_id) AS count
+		  FROM collections c
+		 INNER JOIN notes_collections nc ON nc.collection_id = c.id
+		 WHERE c.kind = '`+string(core.CollectionKindTag)+`'
+		   AND nc.note_id IN (`+selectedIDs+`)
+		 GROUP BY c.id
+		 ORDER BY count DESC, c.name
+		 LIMIT ?
+	`, sqlLimit(limit))
+	if err != nil {
+		return tags, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag core.StatsTag
+		err := rows.Scan(&tag.Name, &tag.NoteCount)
+		if err != nil {
+			d.logger.Err(err)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// findLargestNotes returns the selected notes with the most words.
+func (d *StatsDAO) findLargestNotes(where string, limit int) ([]core.StatsNote, error) {
+	notes := []core.StatsNote{}
+
+	rows, err := d.tx.Query(`
+		SELECT n.path, n.title, n.word_count
+		  FROM notes n
+		 `+where+`
+		 ORDER BY n.word_count DESC, n.sortable_path
+		 LIMIT ?
+	`, sqlLimit(limit))
+	if err != nil {
+		return notes, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note core.StatsNote
+		err := rows.Scan(&note.Path, &note.Title, &note.WordCount)
+		if err != nil {
+			d.logger.Err(err)
+			continue
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// round rounds the ratios to two decimals, for readability.
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// sqlLimit converts a limit option to a SQLite LIMIT value, where a negative
+// value means no limit.
+func sqlLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
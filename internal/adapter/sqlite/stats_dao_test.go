@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func testStatsDAO(t *testing.T, callback func(tx Transaction, dao *StatsDAO)) {
+	testTransaction(t, func(tx Transaction) {
+		callback(tx, NewStatsDAO(tx, &util.NullLogger))
+	})
+}
+
+func TestStatsDAOCompute(t *testing.T) {
+	testStatsDAO(t, func(tx Transaction, dao *StatsDAO) {
+		stats, err := dao.Compute(core.StatsOpts{
+			Period: core.StatsPeriodMonth,
+			Limit:  3,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, stats, core.NotebookStats{
+			NoteCount:     8,
+			WordCount:     38,
+			TagCount:      5,
+			LinkCount:     6,
+			OrphanCount:   3,
+			OrphanRatio:   0.38,
+			AverageDegree: 1.5,
+			Period:        core.StatsPeriodMonth,
+			Created: []core.StatsPeriodCount{
+				{Period: "2019-12", NoteCount: 1},
+				{Period: "2020-01", NoteCount: 1},
+				{Period: "2020-11", NoteCount: 3},
+			},
+			Modified: []core.StatsPeriodCount{
+				{Period: "2019-12", NoteCount: 1},
+				{Period: "2020-01", NoteCount: 1},
+				{Period: "2020-11", NoteCount: 3},
+			},
+			Tags: []core.StatsTag{
+				{Name: "adventure", NoteCount: 2},
+				{Name: "science", NoteCount: 2},
+				{Name: "fantasy", NoteCount: 1},
+			},
+			LargestNotes: []core.StatsNote{
+				{Path: "ref/test/b.md", Title: "A nested note", WordCount: 8},
+				{Path: "f39c8.md", Title: "An interesting note", WordCount: 5},
+				{Path: "ref/test/ref.md", Title: "", WordCount: 5},
+			},
+		})
+	})
+}
+
+func TestStatsDAOComputeWithNoteIDs(t *testing.T) {
+	testStatsDAO(t, func(tx Transaction, dao *StatsDAO) {
+		stats, err := dao.Compute(core.StatsOpts{
+			NoteIDs: []core.NoteID{1, 4, 5},
+			Period:  core.StatsPeriodWeek,
+			Limit:   2,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, stats.NoteCount, 3)
+		assert.Equal(t, stats.WordCount, 16)
+		assert.Equal(t, stats.TagCount, 5)
+		assert.Equal(t, stats.LinkCount, 4)
+		assert.Equal(t, stats.OrphanCount, 1)
+		assert.Equal(t, stats.Created, []core.StatsPeriodCount{
+			{Period: "2020-W03", NoteCount: 1},
+			{Period: "2020-W47", NoteCount: 1},
+		})
+		assert.Equal(t, stats.Tags, []core.StatsTag{
+			{Name: "adventure", NoteCount: 2},
+			{Name: "science", NoteCount: 2},
+		})
+	})
+}
+
+func TestStatsDAOComputeWithISOWeeks(t *testing.T) {
+	testStatsDAO(t, func(tx Transaction, dao *StatsDAO) {
+		// The first days of January may belong to the last week of the
+		// previous year, and the last days of December to the first week of
+		// the next year.
+		_, err := tx.Exec(`
+			UPDATE notes SET created = CASE id
+				WHEN 1 THEN '2021-01-03T10:00:00Z'
+				WHEN 4 THEN '2021-01-04T10:00:00Z'
+				WHEN 5 THEN '2019-12-30T10:00:00Z'
+			END
+			WHERE id IN (1, 4, 5)
+		`)
+		assert.Nil(t, err)
+
+		stats, err := dao.Compute(core.StatsOpts{
+			NoteIDs: []core.NoteID{1, 4, 5},
+			Period:  core.StatsPeriodWeek,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, stats.Created, []core.StatsPeriodCount{
+			{Period: "2020-W01", NoteCount: 1},
+			{Period: "2020-W53", NoteCount: 1},
+			{Period: "2021-W01", NoteCount: 1},
+		})
+	})
+}
+
+func TestStatsDAOComputeWithoutNotes(t *testing.T) {
+	testStatsDAO(t, func(tx Transaction, dao *StatsDAO) {
+		stats, err := dao.Compute(core.StatsOpts{NoteIDs: []core.NoteID{}})
+		assert.Nil(t, err)
+		assert.Equal(t, stats.NoteCount, 0)
+		assert.Equal(t, stats.OrphanRatio, 0.0)
+		assert.Equal(t, stats.AverageDegree, 0.0)
+		assert.Equal(t, stats.Created, []core.StatsPeriodCount{})
+		assert.Equal(t, stats.LargestNotes, []core.StatsNote{})
+	})
+}
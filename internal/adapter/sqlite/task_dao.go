@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+)
+
+// TaskDAO persists the task list items of notes in the SQLite database.
+type TaskDAO struct {
+	tx     Transaction
+	logger util.Logger
+
+	// Prepared SQL statements
+	addTaskStmt     *LazyStmt
+	removeTasksStmt *LazyStmt
+}
+
+// NewTaskDAO creates a new instance of a DAO working on the given database
+// transaction.
+func NewTaskDAO(tx Transaction, logger util.Logger) *TaskDAO {
+	return &TaskDAO{
+		tx:     tx,
+		logger: logger,
+
+		// Add a new task.
+		addTaskStmt: tx.PrepareLazy(`
+			INSERT INTO tasks (note_id, text, done, line, due, tags)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`),
+
+		// Remove all the tasks of a note.
+		removeTasksStmt: tx.PrepareLazy(`
+			DELETE FROM tasks
+			 WHERE note_id = ?
+		`),
+	}
+}
+
+// Add inserts the given tasks of a note.
+func (d *TaskDAO) Add(noteID core.NoteID, tasks []core.Task) error {
+	for _, task := range tasks {
+		var due sql.NullTime
+		if task.Due != nil {
+			due = sql.NullTime{Time: task.Due.UTC(), Valid: true}
+		}
+
+		_, err := d.addTaskStmt.Exec(noteIDToSQL(noteID), task.Text, task.Done, task.Line, due, joinTaskTags(task.Tags))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveAll removes all the tasks of the given note.
+func (d *TaskDAO) RemoveAll(noteID core.NoteID) error {
+	_, err := d.removeTasksStmt.Exec(noteIDToSQL(noteID))
+	return err
+}
+
+// Find returns the tasks matching the given filtering options, ordered by
+// note path and line.
+func (d *TaskDAO) Find(opts core.TaskFindOpts) ([]core.NoteTask, error) {
+	tasks := make([]core.NoteTask, 0)
+
+	whereExprs := []string{}
+	args := []interface{}{}
+
+	if opts.Done != nil {
+		whereExprs = append(whereExprs, "t.done = ?")
+		args = append(args, *opts.Done)
+	}
+	if opts.DueBefore != nil {
+		whereExprs = append(whereExprs, "t.due < ?")
+		args = append(args, opts.DueBefore.UTC())
+	}
+	if opts.DueAfter != nil {
+		whereExprs = append(whereExprs, "t.due >= ?")
+		args = append(args, opts.DueAfter.UTC())
+	}
+	for _, tag := range opts.Tags {
+		whereExprs = append(whereExprs, "t.tags LIKE ? ESCAPE '\\'")
+		args = append(args, "%\x01"+escapeLikeTerm(tag, '\\')+"\x01%")
+	}
+	if opts.NoteIDs != nil {
+		whereExprs = append(whereExprs, "t.note_id IN ("+joinNoteIDs(opts.NoteIDs, ",")+")")
+	}
+
+	query := `
+		SELECT t.note_id, n.path, n.title, t.text, t.done, t.line, t.due, t.tags
+		  FROM tasks t
+		 INNER JOIN notes n ON n.id = t.note_id
+	`
+	if len(whereExprs) > 0 {
+		query += "\n WHERE " + strings.Join(whereExprs, "\n   AND ")
+	}
+	query += "\n ORDER BY n.sortable_path, t.line"
+
+	rows, err := d.tx.Query(query, args...)
+	if err != nil {
+		return tasks, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := d.scanTask(rows)
+		if err != nil {
+			d.logger.Err(err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+func (d *TaskDAO) scanTask(row RowScanner) (core.NoteTask, error) {
+	var (
+		noteID, line      int
+		path, title, text string
+		done              bool
+		due               sql.NullTime
+		tags              sql.NullString
+	)
+
+	err := row.Scan(&noteID, &path, &title, &text, &done, &line, &due, &tags)
+	if err != nil {
+		return core.NoteTask{}, err
+	}
+
+	task := core.NoteTask{
+		Path:  path,
+		Title: title,
+		Task: core.Task{
+			Text: text,
+			Done: done,
+			Line: line,
+			Tags: parseListFromNullString(tags),
+		},
+	}
+	if due.Valid {
+		task.Due = &due.Time
+	}
+	return task, nil
+}
+
+// joinTaskTags concatenates a list of tags into a SQLite ready string. Each
+// tag is delimited by \x01 for easy matching in queries.
+func joinTaskTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "\x01" + strings.Join(tags, "\x01") + "\x01"
+}
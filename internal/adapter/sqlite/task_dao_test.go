@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func testTaskDAO(t *testing.T, callback func(tx Transaction, dao *TaskDAO)) {
+	testTransaction(t, func(tx Transaction) {
+		callback(tx, NewTaskDAO(tx, &util.NullLogger))
+	})
+}
+
+func TestTaskDAOAdd(t *testing.T) {
+	testTaskDAO(t, func(tx Transaction, dao *TaskDAO) {
+		due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+		err := dao.Add(2, []core.Task{
+			{Text: "Open task", Line: 2, Due: &due, Tags: []string{"a", "b"}},
+			{Text: "Done task", Done: true, Line: 3, Tags: []string{}},
+		})
+		assert.Nil(t, err)
+
+		done := false
+		tasks, err := dao.Find(core.TaskFindOpts{NoteIDs: []core.NoteID{2}})
+		assert.Nil(t, err)
+		assert.Equal(t, len(tasks), 2)
+		assert.Equal(t, tasks[0].Text, "Open task")
+		assert.Equal(t, tasks[0].Due.Equal(due), true)
+		assert.Equal(t, tasks[0].Tags, []string{"a", "b"})
+		assert.Equal(t, tasks[1].Done, true)
+		assert.Nil(t, tasks[1].Due)
+
+		tasks, err = dao.Find(core.TaskFindOpts{NoteIDs: []core.NoteID{2}, Done: &done})
+		assert.Nil(t, err)
+		assert.Equal(t, len(tasks), 1)
+	})
+}
+
+func TestTaskDAORemoveAll(t *testing.T) {
+	testTaskDAO(t, func(tx Transaction, dao *TaskDAO) {
+		err := dao.RemoveAll(1)
+		assert.Nil(t, err)
+		assertNotExistTx(t, tx, "SELECT id FROM tasks WHERE note_id = ?", 1)
+		assertExistTx(t, tx, "SELECT id FROM tasks WHERE note_id = ?", 3)
+	})
+}
+
+func TestTaskDAOFind(t *testing.T) {
+	test := func(opts core.TaskFindOpts, expected []string) {
+		testTaskDAO(t, func(tx Transaction, dao *TaskDAO) {
+			tasks, err := dao.Find(opts)
+			assert.Nil(t, err)
+			actual := []string{}
+			for _, task := range tasks {
+				actual = append(actual, task.Path+":"+task.Text)
+			}
+			assert.Equal(t, actual, expected)
+		})
+	}
+
+	open := false
+	done := true
+	dueBefore := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	test(core.TaskFindOpts{}, []string{
+		"index.md:Write an index",
+		"log/2021-01-03.md:Water the plants",
+		"log/2021-01-03.md:Call mom",
+	})
+	test(core.TaskFindOpts{Done: &open}, []string{
+		"index.md:Write an index",
+		"log/2021-01-03.md:Water the plants",
+	})
+	test(core.TaskFindOpts{Done: &done}, []string{"log/2021-01-03.md:Call mom"})
+	test(core.TaskFindOpts{DueBefore: &dueBefore}, []string{"index.md:Write an index"})
+	test(core.TaskFindOpts{DueAfter: &dueBefore}, []string{"log/2021-01-03.md:Water the plants"})
+	test(core.TaskFindOpts{Tags: []string{"home"}}, []string{
+		"index.md:Write an index",
+		"log/2021-01-03.md:Water the plants",
+	})
+	test(core.TaskFindOpts{Tags: []string{"home", "writing"}}, []string{"index.md:Write an index"})
+	test(core.TaskFindOpts{Tags: []string{"hom"}}, []string{})
+	test(core.TaskFindOpts{NoteIDs: []core.NoteID{1}}, []string{
+		"log/2021-01-03.md:Water the plants",
+		"log/2021-01-03.md:Call mom",
+	})
+	test(core.TaskFindOpts{NoteIDs: []core.NoteID{}}, []string{})
+}
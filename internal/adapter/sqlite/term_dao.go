@@ -0,0 +1,296 @@
+package sqlite
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+)
+
+// TermDAO persists the term vectors of the notes in the SQLite database, to
+// compute their content similarity.
+type TermDAO struct {
+	tx     Transaction
+	logger util.Logger
+
+	// Prepared SQL statements
+	addTermStmt     *LazyStmt
+	removeTermsStmt *LazyStmt
+	countNotesStmt  *LazyStmt
+}
+
+// NewTermDAO creates a new instance of a DAO working on the given database
+// transaction.
+func NewTermDAO(tx Transaction, logger util.Logger) *TermDAO {
+	return &TermDAO{
+		tx:     tx,
+		logger: logger,
+
+		// Add a term of a note.
+		addTermStmt: tx.PrepareLazy(`
+			INSERT INTO notes_terms (note_id, term, weight)
+			VALUES (?, ?, ?)
+		`),
+
+		// Remove all the terms of a note.
+		removeTermsStmt: tx.PrepareLazy(`
+			DELETE FROM notes_terms
+			 WHERE note_id = ?
+		`),
+
+		// Count the indexed notes.
+		countNotesStmt: tx.PrepareLazy(`
+			SELECT COUNT(*) FROM notes
+		`),
+	}
+}
+
+// Add indexes the term vector of the given note, made of its title and body.
+func (d *TermDAO) Add(noteID core.NoteID, note core.Note) error {
+	for term, weight := range termWeights(note.Title, note.Body) {
+		_, err := d.addTermStmt.Exec(noteIDToSQL(noteID), term, weight)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveAll removes the term vector of the given note.
+func (d *TermDAO) RemoveAll(noteID core.NoteID) error {
+	_, err := d.removeTermsStmt.Exec(noteIDToSQL(noteID))
+	return err
+}
+
+// similarTermsLimit is the number of most significant terms of the source
+// notes used to find similar notes.
+const similarTermsLimit = 50
+
+// Similarities returns the notes sharing terms with the given ones, indexed
+// by their similarity score between 0 and 1.
+//
+// The score is the cosine similarity between the TF-IDF vector of the most
+// significant terms of the source notes, and the term frequency vector of
+// each candidate note.
+func (d *TermDAO) Similarities(ids []core.NoteID) (map[core.NoteID]float64, error) {
+	scores := map[core.NoteID]float64{}
+	idsList := joinNoteIDs(ids, ",")
+
+	row, err := d.countNotesStmt.QueryRow()
+	if err != nil {
+		return scores, err
+	}
+	var count int
+	err = row.Scan(&count)
+	if err != nil || count == 0 {
+		return scores, err
+	}
+
+	// Weights of the terms of the source notes.
+	source := map[string]float64{}
+	rows, err := d.tx.Query(`
+		SELECT term, SUM(weight) FROM notes_terms
+		 WHERE note_id IN (` + idsList + `)
+		 GROUP BY term
+	`)
+	if err != nil {
+		return scores, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var term string
+		var weight float64
+		if err := rows.Scan(&term, &weight); err != nil {
+			return scores, err
+		}
+		source[term] = weight
+	}
+	if len(source) == 0 {
+		return scores, nil
+	}
+
+	// Inverse document frequencies of the source terms.
+	terms := make([]string, 0, len(source))
+	for term := range source {
+		terms = append(terms, term)
+	}
+	idf := map[string]float64{}
+	err = d.queryTerms(terms, `
+		SELECT term, COUNT(*) FROM notes_terms
+		 WHERE term IN (%s)
+		 GROUP BY term
+	`, func(rows RowScanner) error {
+		var term string
+		var df int
+		if err := rows.Scan(&term, &df); err != nil {
+			return err
+		}
+		idf[term] = math.Log(1 + float64(count)/float64(df))
+		return nil
+	})
+	if err != nil {
+		return scores, err
+	}
+
+	// Keep only the most significant terms of the source notes.
+	sort.Slice(terms, func(i, j int) bool {
+		wi, wj := source[terms[i]]*idf[terms[i]], source[terms[j]]*idf[terms[j]]
+		if wi == wj {
+			return terms[i] < terms[j]
+		}
+		return wi > wj
+	})
+	if len(terms) > similarTermsLimit {
+		terms = terms[:similarTermsLimit]
+	}
+	sourceNorm := 0.0
+	for _, term := range terms {
+		source[term] *= idf[term]
+		sourceNorm += source[term] * source[term]
+	}
+	sourceNorm = math.Sqrt(sourceNorm)
+
+	// Dot products with the candidate notes.
+	dots := map[core.NoteID]float64{}
+	err = d.queryTerms(terms, `
+		SELECT note_id, term, weight FROM notes_terms
+		 WHERE term IN (%s) AND note_id NOT IN (`+idsList+`)
+	`, func(rows RowScanner) error {
+		var id int64
+		var term string
+		var weight float64
+		if err := rows.Scan(&id, &term, &weight); err != nil {
+			return err
+		}
+		dots[core.NoteID(id)] += source[term] * weight
+		return nil
+	})
+	if err != nil || len(dots) == 0 {
+		return scores, err
+	}
+
+	candidates := make([]core.NoteID, 0, len(dots))
+	for id := range dots {
+		candidates = append(candidates, id)
+	}
+	normRows, err := d.tx.Query(`
+		SELECT note_id, SUM(weight * weight) FROM notes_terms
+		 WHERE note_id IN (` + joinNoteIDs(candidates, ",") + `)
+		 GROUP BY note_id
+	`)
+	if err != nil {
+		return scores, err
+	}
+	defer normRows.Close()
+	for normRows.Next() {
+		var id int64
+		var norm float64
+		if err := normRows.Scan(&id, &norm); err != nil {
+			return scores, err
+		}
+		if norm > 0 && sourceNorm > 0 {
+			scores[core.NoteID(id)] = dots[core.NoteID(id)] / (sourceNorm * math.Sqrt(norm))
+		}
+	}
+
+	return scores, normRows.Err()
+}
+
+// queryTerms runs the given query with the list of terms expanded as
+// arguments in the %s placeholder, by chunks to stay below the SQLite
+// variables limit.
+func (d *TermDAO) queryTerms(terms []string, query string, scan func(rows RowScanner) error) error {
+	const chunkSize = 500
+	for start := 0; start < len(terms); start += chunkSize {
+		end := start + chunkSize
+		if end > len(terms) {
+			end = len(terms)
+		}
+		chunk := terms[start:end]
+
+		args := make([]interface{}, len(chunk))
+		for i, term := range chunk {
+			args[i] = term
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+
+		rows, err := d.tx.Query(strings.Replace(query, "%s", placeholders, 1), args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// termWeights returns the weight of each significant term of a note, using
+// a logarithmic term frequency. The words of the title count twice.
+func termWeights(title string, body string) map[string]float64 {
+	frequencies := map[string]int{}
+	for _, term := range tokenize(title) {
+		frequencies[term] += 2
+	}
+	for _, term := range tokenize(body) {
+		frequencies[term]++
+	}
+
+	weights := make(map[string]float64, len(frequencies))
+	for term, frequency := range frequencies {
+		weights[term] = 1 + math.Log(float64(frequency))
+	}
+	return weights
+}
+
+// tokenize splits the given text into lowercase words, ignoring the short
+// words, numbers and common English stop words.
+func tokenize(text string) []string {
+	terms := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < 3 || stopWords[word] || isNumber(word) {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+var stopWords = map[string]bool{
+	"about": true, "after": true, "all": true, "also": true, "and": true,
+	"any": true, "are": true, "because": true, "been": true, "before": true,
+	"but": true, "can": true, "could": true, "did": true, "does": true,
+	"each": true, "for": true, "from": true, "had": true, "has": true,
+	"have": true, "her": true, "his": true, "how": true, "into": true,
+	"its": true, "just": true, "more": true, "most": true, "not": true,
+	"now": true, "only": true, "other": true, "our": true, "out": true,
+	"over": true, "she": true, "should": true, "some": true, "such": true,
+	"than": true, "that": true, "the": true, "their": true, "them": true,
+	"then": true, "there": true, "these": true, "they": true, "this": true,
+	"those": true, "through": true, "too": true, "very": true, "was": true,
+	"were": true, "what": true, "when": true, "where": true, "which": true,
+	"while": true, "who": true, "why": true, "will": true, "with": true,
+	"would": true, "you": true, "your": true,
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func testTermDAO(t *testing.T, callback func(tx Transaction, dao *TermDAO)) {
+	testTransaction(t, func(tx Transaction) {
+		callback(tx, NewTermDAO(tx, &util.NullLogger))
+	})
+}
+
+func TestTermDAOAdd(t *testing.T) {
+	testTermDAO(t, func(tx Transaction, dao *TermDAO) {
+		err := dao.Add(2, core.Note{Title: "Gardening", Body: "Water the plants and the gardening tools."})
+		assert.Nil(t, err)
+
+		assertExistTx(t, tx, "SELECT note_id FROM notes_terms WHERE note_id = ? AND term = ?", 2, "plants")
+		assertExistTx(t, tx, "SELECT note_id FROM notes_terms WHERE note_id = ? AND term = ?", 2, "gardening")
+		assertNotExistTx(t, tx, "SELECT note_id FROM notes_terms WHERE note_id = ? AND term = ?", 2, "the")
+	})
+}
+
+func TestTermDAORemoveAll(t *testing.T) {
+	testTermDAO(t, func(tx Transaction, dao *TermDAO) {
+		assert.Nil(t, dao.Add(1, core.Note{Title: "Gardening"}))
+		assert.Nil(t, dao.Add(2, core.Note{Title: "Gardening"}))
+
+		err := dao.RemoveAll(1)
+		assert.Nil(t, err)
+		assertNotExistTx(t, tx, "SELECT note_id FROM notes_terms WHERE note_id = ?", 1)
+		assertExistTx(t, tx, "SELECT note_id FROM notes_terms WHERE note_id = ?", 2)
+	})
+}
+
+func TestTermDAOSimilarities(t *testing.T) {
+	testTermDAO(t, func(tx Transaction, dao *TermDAO) {
+		assert.Nil(t, dao.Add(1, core.Note{Title: "Gardening", Body: "Water the tomato plants in the garden."}))
+		assert.Nil(t, dao.Add(2, core.Note{Title: "Tomatoes", Body: "The tomato plants need water every day."}))
+		assert.Nil(t, dao.Add(3, core.Note{Title: "Garden", Body: "Water the flowers of the garden."}))
+		assert.Nil(t, dao.Add(4, core.Note{Title: "Concurrency", Body: "Threads share memory."}))
+
+		scores, err := dao.Similarities([]core.NoteID{1})
+		assert.Nil(t, err)
+		assert.Equal(t, len(scores), 2)
+		assert.Equal(t, scores[2] > 0 && scores[2] <= 1, true)
+		assert.Equal(t, scores[3] > 0 && scores[3] <= 1, true)
+		_, ok := scores[4]
+		assert.Equal(t, ok, false)
+
+		scores, err = dao.Similarities([]core.NoteID{2})
+		assert.Nil(t, err)
+		assert.Equal(t, scores[1] > scores[3], true)
+	})
+}
+
+func TestTokenize(t *testing.T) {
+	test := func(text string, expected []string) {
+		assert.Equal(t, tokenize(text), expected)
+	}
+
+	test("", []string{})
+	test("The Borrow-Checker, in 2021!", []string{"borrow", "checker"})
+	test("Écrire une note à propos de Zettelkasten", []string{"écrire", "une", "note", "propos", "zettelkasten"})
+	test("go 1024 ownership", []string{"ownership"})
+}
+
+func TestTermWeights(t *testing.T) {
+	weights := termWeights("Rust", "Rust ownership")
+	assert.Equal(t, len(weights), 2)
+	assert.Equal(t, weights["ownership"], 1.0)
+	assert.Equal(t, weights["rust"] > weights["ownership"], true)
+}
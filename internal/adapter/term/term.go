@@ -7,6 +7,7 @@ import (
 
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
 )
 
 // Terminal offers utilities to interact with the terminal.
@@ -30,6 +31,16 @@ func (t *Terminal) IsTTY() bool {
 	return isatty.IsTerminal(os.Stdin.Fd())
 }
 
+// Width returns the number of columns of the terminal the standard output is
+// attached to, or 0 if it's not a terminal.
+func (t *Terminal) Width() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
 // SupportsUTF8 returns whether the computer is configured to support UTF-8.
 func (t *Terminal) SupportsUTF8() bool {
 	lang := strings.ToUpper(os.Getenv("LANG"))
@@ -71,3 +82,35 @@ func (t *Terminal) forceConfirm(msg string, answer bool) (confirmed, skipped boo
 
 	return answer, false
 }
+
+// Input prompts the user for a line of text.
+func (t *Terminal) Input(msg string) (answer string, skipped bool) {
+	if !t.IsInteractive() {
+		return "", true
+	}
+
+	prompt := &survey.Input{
+		Message: msg,
+	}
+	survey.AskOne(prompt, &answer)
+	return answer, false
+}
+
+// Select prompts the user to choose one of the given options, and returns its
+// index.
+func (t *Terminal) Select(msg string, options []string) (index int, skipped bool) {
+	if !t.IsInteractive() {
+		return -1, true
+	}
+
+	prompt := &survey.Select{
+		Message: msg,
+		Options: options,
+	}
+	err := survey.AskOne(prompt, &index)
+	if err != nil {
+		// e.g. interrupted with Ctrl-C
+		return -1, true
+	}
+	return index, false
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Assets manages the local files referenced by the notes, e.g. images.
+type Assets struct {
+	List AssetsList `cmd group:"cmd" default:"withargs" help:"List the assets of the notebook."`
+	Mv   AssetsMv   `cmd group:"cmd" help:"Move an asset and update the links referencing it."`
+}
+
+// AssetsList lists the local files referenced by the notes, and the ones
+// which are not referenced.
+type AssetsList struct {
+	Format    string `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: path, oneline, full, json, jsonl."`
+	Header    string `group:format                                help:"Arbitrary text printed at the start of the list."`
+	Footer    string `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
+	Delimiter string "group:format short:d default:\n             help:\"Print assets delimited by the given separator.\""
+	NoPager   bool   `group:format short:P help:"Do not pipe output into a pager."`
+	Quiet     bool   `group:format short:q help:"Do not print the total number of assets found."`
+
+	Missing bool `group:filter short:m help:"Find only the assets referenced by notes which don't exist."`
+	Unused  bool `group:filter short:u help:"Find only the files which are not referenced by any note."`
+}
+
+func (cmd *AssetsList) Run(container *cli.Container) error {
+	layout, err := newListLayout(cmd.Format, cmd.Header, cmd.Footer, cmd.Delimiter, cmd.NoPager)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	format, err := notebook.NewAssetFormatter(cmd.assetTemplate())
+	if err != nil {
+		return err
+	}
+
+	assets, err := notebook.FindAssets()
+	if err != nil {
+		return err
+	}
+	assets = cmd.filter(assets)
+
+	count := len(assets)
+	err = printItems(container, layout, count, func(i int) (string, error) {
+		return format(assets[i])
+	})
+
+	if err == nil && !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("asset", count))
+	}
+
+	return err
+}
+
+// filter keeps only the assets matching the --missing and --unused flags.
+func (cmd *AssetsList) filter(assets []core.Asset) []core.Asset {
+	if !cmd.Missing && !cmd.Unused {
+		return assets
+	}
+
+	filtered := make([]core.Asset, 0)
+	for _, asset := range assets {
+		if (cmd.Missing && asset.Status == core.AssetMissing) || (cmd.Unused && asset.Status == core.AssetUnused) {
+			filtered = append(filtered, asset)
+		}
+	}
+	return filtered
+}
+
+func (cmd *AssetsList) assetTemplate() string {
+	format := cmd.Format
+	if format == "" {
+		format = "oneline"
+	}
+
+	templ, ok := defaultAssetFormats[format]
+	if !ok {
+		templ = strutil.ExpandWhitespaceLiterals(format)
+	}
+
+	return templ
+}
+
+var defaultAssetFormats = map[string]string{
+	"json":  `{{json .}}`,
+	"jsonl": `{{json .}}`,
+
+	"path":    `{{path}}`,
+	"oneline": `{{style "path" path}} {{style "understate" status}}`,
+
+	"full": `{{style "path" path}} {{style "understate" status}}{{#each sources}}
+  {{this}}{{/each}}
+`,
+}
+
+// AssetsMv moves an asset and rewrites the links of the notes referencing it.
+type AssetsMv struct {
+	Source string `arg help:"Path to the asset to move."`
+	Target string `arg help:"New path of the asset."`
+}
+
+func (cmd *AssetsMv) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	src, err := notebook.RelPath(cmd.Source)
+	if err != nil {
+		return err
+	}
+	dst, err := notebook.RelPath(cmd.Target)
+	if err != nil {
+		return err
+	}
+
+	updated, err := notebook.MoveAsset(src, dst)
+	if err != nil {
+		return err
+	}
+
+	if len(updated) > 0 {
+		absPaths := make([]string, 0, len(updated))
+		for _, path := range updated {
+			absPaths = append(absPaths, filepath.Join(notebook.Path, path))
+		}
+		_, err = notebook.IndexPaths(absPaths)
+		if err != nil {
+			return err
+		}
+	}
+
+	count := len(updated)
+	fmt.Fprintf(os.Stderr, "Moved %s to %s, updated %d %s\n", src, dst, count, strutil.Pluralize("note", count))
+	return nil
+}
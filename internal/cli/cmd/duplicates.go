@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Duplicates reports the notes duplicating each other, and merges them.
+type Duplicates struct {
+	Format    string `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: oneline, full, json, jsonl."`
+	Header    string `group:format                                help:"Arbitrary text printed at the start of the list."`
+	Footer    string `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
+	Delimiter string "group:format short:d default:\n             help:\"Print groups delimited by the given separator.\""
+	NoPager   bool   `group:format short:P help:"Do not pipe output into a pager."`
+	Quiet     bool   `group:format short:q help:"Do not print the total number of groups found."`
+
+	Exact     bool    `group:filter short:e help:"Find only the notes with the same content."`
+	Similar   bool    `group:filter short:s help:"Find only the notes with nearly the same body."`
+	Title     bool    `group:filter short:t help:"Find only the notes with the same title."`
+	Threshold float64 `group:filter default:0.8 placeholder:RATIO help:"Minimum similarity of near-duplicate notes, between 0 and 1."`
+
+	Merge bool `help:"Merge each group into its oldest note, after confirmation."`
+}
+
+func (cmd *Duplicates) Run(container *cli.Container) error {
+	if cmd.Threshold <= 0 || cmd.Threshold > 1 {
+		return errors.New("--threshold must be between 0 and 1")
+	}
+
+	if cmd.Merge && (cmd.Format == "json" || cmd.Format == "jsonl") {
+		return errors.New("--merge can't be used with JSON format")
+	}
+
+	layout, err := newListLayout(cmd.Format, cmd.Header, cmd.Footer, cmd.Delimiter, cmd.NoPager)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	format, err := notebook.NewDuplicateGroupFormatter(cmd.groupTemplate())
+	if err != nil {
+		return err
+	}
+
+	groups, err := notebook.FindDuplicates(core.DuplicateFindOpts{
+		Kinds:     cmd.kinds(),
+		Threshold: cmd.Threshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cmd.Merge {
+		return cmd.merge(container, notebook, groups, format)
+	}
+
+	count := len(groups)
+	err = printItems(container, layout, count, func(i int) (string, error) {
+		return format(groups[i])
+	})
+
+	if err == nil && !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("group", count))
+	}
+
+	return err
+}
+
+// merge asks to merge each group of duplicates into its oldest note.
+func (cmd *Duplicates) merge(container *cli.Container, notebook *core.Notebook, groups []core.DuplicateGroup, format core.DuplicateGroupFormatter) error {
+	// A note can belong to several groups, e.g. exact and title duplicates.
+	removed := map[string]bool{}
+
+	for _, group := range groups {
+		notes := []core.ContextualNote{}
+		for _, note := range group.Notes {
+			if !removed[note.Path] {
+				notes = append(notes, note)
+			}
+		}
+		if len(notes) < 2 {
+			continue
+		}
+		group.Notes = notes
+
+		fg, err := format(group)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.TrimRight(fg, "\n"))
+
+		survivor := notes[0]
+		paths := []string{}
+		for _, note := range notes[1:] {
+			paths = append(paths, note.Path)
+		}
+		count := len(paths)
+		confirmed, skipped := container.Terminal.Confirm(fmt.Sprintf("Merge %d %s into %s?", count, strutil.Pluralize("note", count), survivor.Path), false)
+		if skipped || !confirmed {
+			continue
+		}
+
+		updated, err := notebook.MergeNotes(survivor.Path, paths)
+		if err != nil {
+			return err
+		}
+
+		absPaths := []string{}
+		for _, path := range append(updated, paths...) {
+			absPaths = append(absPaths, filepath.Join(notebook.Path, path))
+		}
+		_, err = notebook.IndexPaths(absPaths)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			removed[path] = true
+		}
+
+		// The survivor is always updated.
+		updatedCount := len(updated) - 1
+		fmt.Fprintf(os.Stderr, "Merged %d %s into %s, updated %d %s\n", count, strutil.Pluralize("note", count), survivor.Path, updatedCount, strutil.Pluralize("note", updatedCount))
+	}
+
+	return nil
+}
+
+// kinds returns the kinds of duplicates selected by the filter flags.
+func (cmd *Duplicates) kinds() []core.DuplicateKind {
+	kinds := []core.DuplicateKind{}
+	if cmd.Exact {
+		kinds = append(kinds, core.DuplicateExact)
+	}
+	if cmd.Similar {
+		kinds = append(kinds, core.DuplicateSimilar)
+	}
+	if cmd.Title {
+		kinds = append(kinds, core.DuplicateTitle)
+	}
+	return kinds
+}
+
+func (cmd *Duplicates) groupTemplate() string {
+	format := cmd.Format
+	if format == "" {
+		format = "oneline"
+	}
+
+	templ, ok := defaultDuplicateFormats[format]
+	if !ok {
+		templ = strutil.ExpandWhitespaceLiterals(format)
+	}
+
+	return templ
+}
+
+var defaultDuplicateFormats = map[string]string{
+	"json":  `{{json .}}`,
+	"jsonl": `{{json .}}`,
+
+	"oneline": `{{style "understate" kind}} {{#each notes}}{{#if @index}} {{/if}}{{style "path" path}}{{/each}}`,
+
+	"full": `{{style "understate" kind}}{{#if similarity}} ({{similarity}}){{/if}}{{#each notes}}
+  {{style "path" path}} {{style "title" title}}{{/each}}
+`,
+}
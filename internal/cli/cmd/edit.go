@@ -15,38 +15,52 @@ import (
 type Edit struct {
 	Force bool `short:f help:"Do not confirm before editing many notes at the same time."`
 	cli.Filtering
+
+	AllNotebooks bool `group:filter help:"Find notes in all the notebooks declared in the global config."`
+	Live         bool `group:filter help:"Search the notes interactively as the query is typed, implies --interactive."`
 }
 
 func (cmd *Edit) Run(container *cli.Container) error {
-	notebook, err := container.CurrentNotebook()
-	if err != nil {
-		return err
+	if cmd.Live && cmd.AllNotebooks {
+		return errors.New("--live can't be used with --all-notebooks")
 	}
 
-	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
+	notes, err := findNotes(container, cmd.Filtering, cmd.AllNotebooks)
 	if err != nil {
-		return errors.Wrapf(err, "incorrect criteria")
+		return err
 	}
 
-	notes, err := notebook.FindNotes(findOpts)
-	if err != nil {
+	// The "new note" binding creates the note in the current notebook.
+	var newNoteDir *core.Dir
+	notebook, err := container.CurrentNotebook()
+	if err == nil {
+		newNoteDir = cmd.newNoteDir(notebook)
+	} else if !cmd.AllNotebooks {
 		return err
 	}
 
-	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+	filterOpts := fzf.NoteFilterOpts{
 		Interactive:  cmd.Interactive,
 		AlwaysFilter: true,
-		NewNoteDir:   cmd.newNoteDir(notebook),
-		NotebookDir:  notebook.Path,
-	})
+		NewNoteDir:   newNoteDir,
+	}
+	if cmd.Live {
+		filterOpts, err = withLiveSearch(container, filterOpts, cmd.Filtering)
+		if err != nil {
+			return err
+		}
+	}
 
-	notes, err = filter.Apply(notes)
+	notes, action, err := filterNotes(container, filterOpts, notes)
 	if err != nil {
 		if err == fzf.ErrCancelled {
 			return nil
 		}
 		return err
 	}
+	if action != "" && action != "open" {
+		return runNoteAction(container, action, notes)
+	}
 
 	count := len(notes)
 
@@ -61,10 +75,13 @@ func (cmd *Edit) Run(container *cli.Container) error {
 		}
 		paths := make([]string, 0)
 		for _, note := range notes {
-			absPath := filepath.Join(notebook.Path, note.Path)
+			absPath := filepath.Join(note.Notebook.Path, note.Path)
 			paths = append(paths, absPath)
 		}
 
+		if notebook == nil {
+			notebook = notes[0].Notebook
+		}
 		editor, err := container.NewNoteEditor(notebook)
 		if err != nil {
 			return err
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zk-org/zk/internal/cli"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Fmt normalizes the notes matching a set of criteria, according to the
+// notebook configuration.
+type Fmt struct {
+	Check bool `help:"Print the notes which are not formatted without modifying them, and fail if there are any."`
+	cli.Filtering
+}
+
+func (cmd *Fmt) Run(container *cli.Container) error {
+	notes, err := selectNotes(container, cmd.Filtering)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	absPaths := []string{}
+	for _, note := range notes {
+		changed, err := notebook.FormatNoteAt(note.Path, !cmd.Check)
+		if err != nil {
+			return err
+		}
+		if changed {
+			fmt.Println(note.Path)
+			absPaths = append(absPaths, filepath.Join(notebook.Path, note.Path))
+		}
+	}
+
+	count := len(absPaths)
+	if cmd.Check {
+		if count > 0 {
+			return fmt.Errorf("%d %s not formatted", count, strutil.Pluralize("note", count))
+		}
+		return nil
+	}
+
+	if count > 0 {
+		_, err = notebook.IndexPaths(absPaths)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Formatted %d %s\n", count, strutil.Pluralize("note", count))
+	return nil
+}
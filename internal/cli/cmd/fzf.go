@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/opt"
+	osutil "github.com/zk-org/zk/internal/util/os"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// fzfFilteringEnv is the environment variable holding the filtering options
+// of a live search, for the fzf-reload command.
+const fzfFilteringEnv = "ZK_FZF_FILTERING"
+
+// liveFiltering is the JSON representation of the filtering options of a live
+// search. It includes the options which are not serialized by cli.Filtering.
+type liveFiltering struct {
+	cli.Filtering
+	NoLinkTo   []string `json:"noLinkTo"`
+	NoLinkedBy []string `json:"noLinkedBy"`
+}
+
+// FzfReload prints the fzf lines of the notes matching a query during a live
+// search. It is run by fzf each time the query changes.
+type FzfReload struct {
+	Query string `arg optional`
+}
+
+func (cmd *FzfReload) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	var filtering liveFiltering
+	err = json.Unmarshal([]byte(os.Getenv(fzfFilteringEnv)), &filtering)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", fzfFilteringEnv)
+	}
+	filtering.Filtering.NoLinkTo = filtering.NoLinkTo
+	filtering.Filtering.NoLinkedBy = filtering.NoLinkedBy
+
+	notes, err := findLiveNotes(notebook, filtering.Filtering, cmd.Query)
+	if err != nil {
+		// The query is often invalid while being typed, so errors are not
+		// reported to keep the fzf window clean.
+		return nil
+	}
+
+	return container.NewNoteFilter(fzf.NoteFilterOpts{
+		NotebookDir: notebook.Path,
+	}).PrintLines(os.Stdout, notes)
+}
+
+// findLiveNotes returns the notes matching the filtering options and the
+// query of a live search.
+func findLiveNotes(notebook *core.Notebook, filtering cli.Filtering, query string) ([]core.ContextualNote, error) {
+	if query != "" {
+		filtering.Match = append(filtering.Match, query)
+	}
+	opts, err := filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return nil, err
+	}
+	return notebook.FindNotes(opts)
+}
+
+// withLiveSearch configures the fzf options to search live the notes matching
+// the given filtering options, as the query changes.
+func withLiveSearch(container *cli.Container, opts fzf.NoteFilterOpts, filtering cli.Filtering) (fzf.NoteFilterOpts, error) {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return opts, err
+	}
+	zkBin, err := os.Executable()
+	if err != nil {
+		return opts, err
+	}
+	filteringJSON, err := json.Marshal(liveFiltering{
+		Filtering:  filtering,
+		NoLinkTo:   filtering.NoLinkTo,
+		NoLinkedBy: filtering.NoLinkedBy,
+	})
+	if err != nil {
+		return opts, err
+	}
+
+	opts.Interactive = true
+	opts.AlwaysFilter = true
+	opts.NotebookDir = notebook.Path
+	opts.ReloadCmd = opt.NewString(shellquote.Join(
+		zkBin, "--notebook-dir", notebook.Path, "--working-dir", container.FS.WorkingDir(), "fzf-reload", "--",
+	) + " {q}")
+	opts.ReloadEnv = []string{fzfFilteringEnv + "=" + string(filteringJSON)}
+	opts.Requery = func(query string) ([]core.ContextualNote, error) {
+		return findLiveNotes(notebook, filtering, query)
+	}
+	return opts, nil
+}
+
+// noteActions returns the key bindings of the fzf actions set in the config.
+func noteActions(container *cli.Container) (map[string]string, error) {
+	actions := map[string]string{}
+	for action, keys := range container.Config.Tool.FzfActions {
+		if _, ok := fzf.NoteActions[action]; !ok {
+			return nil, fmt.Errorf("%s: unknown fzf action in the [tool.fzf-actions] config", action)
+		}
+		if keys != "" {
+			actions[action] = keys
+		}
+	}
+	return actions, nil
+}
+
+// runNoteAction performs the fzf action with the given name on the selected
+// notes.
+func runNoteAction(container *cli.Container, action string, notes []core.NotebookNote) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(notes))
+	for _, note := range notes {
+		paths = append(paths, filepath.Join(note.Notebook.Path, note.Path))
+	}
+
+	switch action {
+	case "open":
+		editor, err := container.NewNoteEditor(notes[0].Notebook)
+		if err != nil {
+			return err
+		}
+		return editor.Open(paths...)
+
+	case "link":
+		links, err := formatLinks(notes, container.FS.WorkingDir())
+		if err != nil {
+			return err
+		}
+		for _, link := range links {
+			fmt.Println(link)
+		}
+		return nil
+
+	case "copy-path":
+		relPaths := make([]string, 0, len(paths))
+		for _, path := range paths {
+			if relPath, err := container.FS.Rel(path); err == nil {
+				path = relPath
+			}
+			relPaths = append(relPaths, path)
+		}
+		err := osutil.CopyToClipboard(strings.Join(relPaths, "\n"))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Copied %d %s to the clipboard\n", len(paths), strutil.Pluralize("path", len(paths)))
+		return nil
+
+	case "delete":
+		count := len(notes)
+		confirmed, skipped := container.Terminal.Confirm(fmt.Sprintf("Are you sure you want to delete %d %s?", count, strutil.Pluralize("note", count)), false)
+		if skipped || !confirmed {
+			return nil
+		}
+		for i, note := range notes {
+			err := note.Notebook.DeleteNotes([]string{paths[i]})
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Deleted %d %s\n", count, strutil.Pluralize("note", count))
+		return nil
+
+	case "backlinks":
+		backlinks, err := findBacklinks(notes)
+		if err != nil {
+			return err
+		}
+		for _, note := range backlinks {
+			path := filepath.Join(note.Notebook.Path, note.Path)
+			if relPath, err := container.FS.Rel(path); err == nil {
+				path = relPath
+			}
+			fmt.Println(path)
+		}
+		return nil
+
+	case "tag":
+		answer, skipped := container.Terminal.Input("Tags to add:")
+		if skipped {
+			return nil
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return nil
+		}
+		tags := tagSeparatorRegex.Split(answer, -1)
+		for i, note := range notes {
+			err := note.Notebook.AddNoteTags(paths[i], tags)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%s: unsupported fzf action", action)
+	}
+}
+
+// tagSeparatorRegex matches the separators between the tags typed by the
+// user.
+var tagSeparatorRegex = regexp.MustCompile(`[\s,]+`)
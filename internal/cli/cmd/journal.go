@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	dateutil "github.com/zk-org/zk/internal/util/date"
+)
+
+// Journal creates or edits the note of a journal period.
+type Journal struct {
+	Period    string `arg optional placeholder:PERIOD help:"Period of the journal note among: daily, weekly, monthly. Defaults to daily."`
+	Date      string `arg optional placeholder:DATE   help:"Any date in the period, e.g. yesterday. Defaults to today."`
+	PrintPath bool   `short:p                         help:"Print the path of the journal note instead of editing it."`
+	DryRun    bool   `short:n                         help:"Don't actually create the note. Instead, prints its content on stdout and the generated path on stderr."`
+	Missing   bool   `                                help:"List the periods without a journal note between --from and --to."`
+	From      string `placeholder:DATE                help:"Beginning of the range of --missing. Defaults to the beginning of the year."`
+	To        string `placeholder:DATE                help:"End of the range of --missing. Defaults to today."`
+}
+
+func (cmd *Journal) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	// The period can be omitted, e.g. `zk journal yesterday`.
+	period, err := core.JournalPeriodFromString(cmd.Period)
+	if err != nil {
+		if cmd.Date != "" {
+			return err
+		}
+		cmd.Date = cmd.Period
+		period = core.JournalDaily
+	}
+
+	date := time.Now()
+	if cmd.Date != "" {
+		date, err = dateutil.TimeFromNatural(cmd.Date)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.Missing {
+		return cmd.printMissing(notebook, period)
+	}
+
+	note, err := notebook.NewJournalNote(core.JournalNoteOpts{
+		Period: period,
+		Date:   date,
+		DryRun: cmd.DryRun,
+	})
+
+	var noteExists core.ErrNoteExists
+	var path string
+	switch {
+	case errors.As(err, &noteExists):
+		path = noteExists.Path
+	case err != nil:
+		return err
+	case cmd.DryRun:
+		fmt.Fprintln(os.Stderr, filepath.Join(notebook.Path, note.Path))
+		fmt.Print(note.RawContent)
+		return nil
+	default:
+		path = filepath.Join(notebook.Path, note.Path)
+	}
+
+	if cmd.PrintPath || cmd.DryRun {
+		fmt.Println(path)
+		return nil
+	}
+
+	editor, err := container.NewNoteEditor(notebook)
+	if err != nil {
+		return err
+	}
+	return editor.Open(path)
+}
+
+// printMissing prints the beginning of each period without a journal note.
+func (cmd *Journal) printMissing(notebook *core.Notebook, period core.JournalPeriod) error {
+	to := time.Now()
+	if cmd.To != "" {
+		date, err := dateutil.TimeFromNatural(cmd.To)
+		if err != nil {
+			return err
+		}
+		to = date
+	}
+
+	from := time.Date(to.Year(), 1, 1, 0, 0, 0, 0, to.Location())
+	if cmd.From != "" {
+		date, err := dateutil.TimeFromNatural(cmd.From)
+		if err != nil {
+			return err
+		}
+		from = date
+	}
+
+	missing, err := notebook.MissingJournalPeriods(period, from, to)
+	if err != nil {
+		return err
+	}
+	for _, date := range missing {
+		fmt.Println(date.Format("2006-01-02"))
+	}
+	return nil
+}
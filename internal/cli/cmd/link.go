@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+)
+
+// Link prints links to notes matching a set of criteria, to be inserted in
+// another note.
+type Link struct {
+	From string `short:F type:path placeholder:PATH help:"Note in which the links will be inserted, to make them relative to it."`
+	cli.Filtering
+}
+
+func (cmd *Link) Run(container *cli.Container) error {
+	notes, err := findNotes(container, cmd.Filtering, false)
+	if err != nil {
+		return err
+	}
+
+	notes, _, err = filterNotesOnce(container, fzf.NoteFilterOpts{
+		Interactive:  cmd.Interactive,
+		AlwaysFilter: true,
+	}, notes)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	dir := container.FS.WorkingDir()
+	if cmd.From != "" {
+		from, err := container.FS.Abs(cmd.From)
+		if err != nil {
+			return err
+		}
+		dir = filepath.Dir(from)
+	}
+
+	links, err := formatLinks(notes, dir)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		fmt.Println(link)
+	}
+	return nil
+}
+
+// formatLinks returns the internal links to the given notes, relative to the
+// absolute directory dir.
+func formatLinks(notes []core.NotebookNote, dir string) ([]string, error) {
+	formatters := map[*core.Notebook]core.LinkFormatter{}
+	links := make([]string, 0, len(notes))
+
+	for _, note := range notes {
+		formatter, ok := formatters[note.Notebook]
+		if !ok {
+			var err error
+			formatter, err = note.Notebook.NewLinkFormatter()
+			if err != nil {
+				return nil, err
+			}
+			formatters[note.Notebook] = formatter
+		}
+
+		context, err := core.NewLinkFormatterContext(core.NotebookPath{
+			Path:       note.Path,
+			BasePath:   note.Notebook.Path,
+			WorkingDir: dir,
+		}, note.Title, note.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		link, err := formatter(context)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
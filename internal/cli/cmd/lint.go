@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Lint reports the issues found in the notes matching a set of criteria,
+// using the rules enabled in the notebook configuration.
+type Lint struct {
+	Quiet bool `short:q help:"Do not print the total number of issues found."`
+	cli.Filtering
+}
+
+func (cmd *Lint) Run(container *cli.Container) error {
+	notes, err := selectNotes(container, cmd.Filtering)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	errorCount := 0
+	for _, note := range notes {
+		issues, err := notebook.LintNoteAt(note.Path)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			fmt.Printf("%s:%d: %s: %s (%s)\n", note.Path, issue.Line, issue.Severity, issue.Message, issue.Rule)
+			count++
+			if issue.Severity == core.LSPDiagnosticError {
+				errorCount++
+			}
+		}
+	}
+
+	if !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("issue", count))
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("found %d %s", errorCount, strutil.Pluralize("error", errorCount))
+	}
+	return nil
+}
@@ -7,20 +7,25 @@ import (
 
 	"github.com/zk-org/zk/internal/adapter/fzf"
 	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/strings"
 )
 
 // List displays notes matching a set of criteria.
 type List struct {
-	Format     string `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: oneline, short, medium, long, full, json, jsonl."`
-	Header     string `group:format                                help:"Arbitrary text printed at the start of the list."`
-	Footer     string `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
-	Delimiter  string "group:format short:d default:\n             help:\"Print notes delimited by the given separator.\""
-	Delimiter0 bool   "group:format short:0 name:delimiter0        help:\"Print notes delimited by ASCII NUL characters. This is useful when used in conjunction with `xargs -0`.\""
-	NoPager    bool   `group:format short:P help:"Do not pipe output into a pager."`
-	Quiet      bool   `group:format short:q help:"Do not print the total number of notes found."`
+	Format     string   `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: oneline, short, medium, long, full, json, jsonl, table, csv, tsv, yaml."`
+	Columns    []string `group:format short:c placeholder:COLUMN     help:"Columns printed with the table, csv, tsv and yaml formats, e.g. title,path,tags,created,meta.status. Implies --format table."`
+	Header     string   `group:format                                help:"Arbitrary text printed at the start of the list."`
+	Footer     string   `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
+	Delimiter  string   "group:format short:d default:\n             help:\"Print notes delimited by the given separator.\""
+	Delimiter0 bool     "group:format short:0 name:delimiter0        help:\"Print notes delimited by ASCII NUL characters. This is useful when used in conjunction with `xargs -0`.\""
+	NoPager    bool     `group:format short:P help:"Do not pipe output into a pager."`
+	Quiet      bool     `group:format short:q help:"Do not print the total number of notes found."`
 	cli.Filtering
+
+	AllNotebooks bool `group:filter help:"Find notes in all the notebooks declared in the global config."`
+	Live         bool `group:filter help:"Search the notes interactively as the query is typed, implies --interactive."`
 }
 
 func (cmd *List) Run(container *cli.Container) error {
@@ -28,6 +33,10 @@ func (cmd *List) Run(container *cli.Container) error {
 	cmd.Footer = strings.ExpandWhitespaceLiterals(cmd.Footer)
 	cmd.Delimiter = strings.ExpandWhitespaceLiterals(cmd.Delimiter)
 
+	if cmd.Live && cmd.AllNotebooks {
+		return errors.New("--live can't be used with --all-notebooks")
+	}
+
 	if cmd.Delimiter0 {
 		if cmd.Delimiter != "\n" {
 			return errors.New("--delimiter and --delimiter0 can't be used together")
@@ -43,6 +52,13 @@ func (cmd *List) Run(container *cli.Container) error {
 		cmd.Footer = "\x00"
 	}
 
+	var err error
+	hasLayout := cmd.Header != "" || cmd.Footer != "\n" || cmd.Delimiter != "\n"
+	cmd.Format, cmd.Columns, err = cli.ResolveRecordColumns(cmd.Format, cmd.Columns, hasLayout, core.DefaultNoteColumns, core.ValidateNoteColumns)
+	if err != nil {
+		return err
+	}
+
 	if cmd.Format == "json" || cmd.Format == "jsonl" {
 		if cmd.Header != "" {
 			return errors.New("--header can't be used with JSON format")
@@ -69,63 +85,40 @@ func (cmd *List) Run(container *cli.Container) error {
 		}
 	}
 
-	notebook, err := container.CurrentNotebook()
-	if err != nil {
-		return err
-	}
-
-	format, err := notebook.NewNoteFormatter(cmd.noteTemplate())
-	if err != nil {
-		return err
-	}
-
-	findOpts, err := cmd.Filtering.NewNoteFindOpts(notebook)
-	if err != nil {
-		return errors.Wrapf(err, "incorrect criteria")
-	}
-
-	notes, err := notebook.FindNotes(findOpts)
+	notes, err := findNotes(container, cmd.Filtering, cmd.AllNotebooks)
 	if err != nil {
 		return err
 	}
 
-	filter := container.NewNoteFilter(fzf.NoteFilterOpts{
+	filterOpts := fzf.NoteFilterOpts{
 		Interactive:  cmd.Interactive,
 		AlwaysFilter: false,
-		NotebookDir:  notebook.Path,
-	})
+	}
+	if cmd.Live {
+		filterOpts, err = withLiveSearch(container, filterOpts, cmd.Filtering)
+		if err != nil {
+			return err
+		}
+	}
 
-	notes, err = filter.Apply(notes)
+	notes, action, err := filterNotes(container, filterOpts, notes)
 	if err != nil {
 		if err == fzf.ErrCancelled {
 			return nil
 		}
 		return err
 	}
+	if action != "" {
+		return runNoteAction(container, action, notes)
+	}
 
 	count := len(notes)
 	if count > 0 {
-		err = container.Paginate(cmd.NoPager, func(out io.Writer) error {
-			if cmd.Header != "" {
-				fmt.Fprint(out, cmd.Header)
-			}
-			for i, note := range notes {
-				if i > 0 {
-					fmt.Fprint(out, cmd.Delimiter)
-				}
-
-				ft, err := format(note)
-				if err != nil {
-					return err
-				}
-				fmt.Fprint(out, ft)
-			}
-			if cmd.Footer != "" {
-				fmt.Fprint(out, cmd.Footer)
-			}
-
-			return nil
-		})
+		if cli.IsRecordFormat(cmd.Format) {
+			err = cmd.printRecords(container, notes)
+		} else {
+			err = cmd.printNotes(container, notes)
+		}
 	}
 
 	if err == nil && !cmd.Quiet {
@@ -135,6 +128,74 @@ func (cmd *List) Run(container *cli.Container) error {
 	return err
 }
 
+// printNotes prints the notes formatted with the note template.
+func (cmd *List) printNotes(container *cli.Container, notes []core.NotebookNote) error {
+	// Templates and paths depend on the notebook of each note.
+	formats := map[*core.Notebook]core.NoteFormatter{}
+	for _, note := range notes {
+		if _, ok := formats[note.Notebook]; ok {
+			continue
+		}
+		var err error
+		formats[note.Notebook], err = note.Notebook.NewNamedNoteFormatter(note.NotebookName, cmd.noteTemplate())
+		if err != nil {
+			return err
+		}
+	}
+
+	return container.Paginate(cmd.NoPager, func(out io.Writer) error {
+		if cmd.Header != "" {
+			fmt.Fprint(out, cmd.Header)
+		}
+		for i, note := range notes {
+			if i > 0 {
+				fmt.Fprint(out, cmd.Delimiter)
+			}
+
+			ft, err := formats[note.Notebook](note.ContextualNote)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(out, ft)
+		}
+		if cmd.Footer != "" {
+			fmt.Fprint(out, cmd.Footer)
+		}
+
+		return nil
+	})
+}
+
+// printRecords prints the columns of the notes as records, e.g. in a table.
+func (cmd *List) printRecords(container *cli.Container, notes []core.NotebookNote) error {
+	formats := map[*core.Notebook]core.NoteColumnsFormatter{}
+	records := make([][]interface{}, 0, len(notes))
+	for _, note := range notes {
+		format, ok := formats[note.Notebook]
+		if !ok {
+			var err error
+			format, err = note.Notebook.NewNoteColumnsFormatter(note.NotebookName, cmd.Columns)
+			if err != nil {
+				return err
+			}
+			formats[note.Notebook] = format
+		}
+
+		record, err := format(note.ContextualNote)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	return container.Paginate(cmd.NoPager, func(out io.Writer) error {
+		return cli.WriteRecords(out, cmd.Format, cmd.Columns, records, cli.RecordsOpts{
+			Width:  container.Terminal.Width(),
+			Styler: container.Styler,
+		})
+	})
+}
+
 func (cmd *List) noteTemplate() string {
 	format := cmd.Format
 	if format == "" {
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zk-org/zk/internal/cli"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Log lists the revisions of a note from the git history.
+type Log struct {
+	Path string `arg placeholder:PATH help:"Path to the note."`
+
+	Format    string `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: oneline, full, json, jsonl."`
+	Header    string `group:format                                help:"Arbitrary text printed at the start of the list."`
+	Footer    string `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
+	Delimiter string "group:format short:d default:\n             help:\"Print revisions delimited by the given separator.\""
+	NoPager   bool   `group:format short:P help:"Do not pipe output into a pager."`
+	Quiet     bool   `group:format short:q help:"Do not print the total number of revisions found."`
+}
+
+func (cmd *Log) Run(container *cli.Container) error {
+	layout, err := newListLayout(cmd.Format, cmd.Header, cmd.Footer, cmd.Delimiter, cmd.NoPager)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	format, err := notebook.NewNoteRevisionFormatter(cmd.revisionTemplate())
+	if err != nil {
+		return err
+	}
+
+	path, err := notebook.RelPath(cmd.Path)
+	if err != nil {
+		return err
+	}
+	note, err := notebook.FindByHref(path, false)
+	if err != nil {
+		return err
+	}
+	if note == nil {
+		return fmt.Errorf("%s: note not found", cmd.Path)
+	}
+
+	revisions, err := notebook.NoteRevisions(note.Path)
+	if err != nil {
+		return err
+	}
+
+	count := len(revisions)
+	err = printItems(container, layout, count, func(i int) (string, error) {
+		return format(revisions[i])
+	})
+
+	if err == nil && !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("revision", count))
+	}
+
+	return err
+}
+
+func (cmd *Log) revisionTemplate() string {
+	format := cmd.Format
+	if format == "" {
+		format = "oneline"
+	}
+
+	templ, ok := defaultRevisionFormats[format]
+	if !ok {
+		templ = strutil.ExpandWhitespaceLiterals(format)
+	}
+
+	return templ
+}
+
+var defaultRevisionFormats = map[string]string{
+	"json":  `{{json .}}`,
+	"jsonl": `{{json .}}`,
+
+	"oneline": `{{style "understate" short-hash}} {{format-date date "%Y-%m-%d"}} {{style "title" author}} {{message}}`,
+
+	"full": `{{style "understate" hash}}
+Author: {{author}} <{{email}}>
+Date:   {{format-date date "full"}}
+
+  {{message}}
+`,
+}
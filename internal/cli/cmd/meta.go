@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Meta reads and edits the YAML frontmatter of the notes.
+type Meta struct {
+	Get   MetaGet   `cmd group:"cmd" help:"Print the value of a frontmatter key for the notes matching the given criteria."`
+	Set   MetaSet   `cmd group:"cmd" help:"Set the value of a frontmatter key in the notes matching the given criteria."`
+	Unset MetaUnset `cmd group:"cmd" help:"Remove a frontmatter key from the notes matching the given criteria."`
+	Add   MetaAdd   `cmd group:"cmd" help:"Add items to a frontmatter list, e.g. tags, in the notes matching the given criteria."`
+}
+
+// MetaGet prints the value of a frontmatter key for each note.
+type MetaGet struct {
+	Key string `arg placeholder:KEY help:"Frontmatter key to print."`
+	cli.Filtering
+}
+
+func (cmd *MetaGet) Run(container *cli.Container) error {
+	if err := core.ValidateFrontmatterKey(cmd.Key); err != nil {
+		return err
+	}
+
+	notes, err := selectNotes(container, cmd.Filtering)
+	if err != nil || len(notes) == 0 {
+		return err
+	}
+
+	// The metadata keys are lowercased in the index.
+	key := strings.ToLower(cmd.Key)
+	for _, note := range notes {
+		value, ok := note.Metadata[key]
+		if !ok {
+			continue
+		}
+		formatted, err := formatMetaValue(value)
+		if err != nil {
+			return errors.Wrapf(err, "%s", note.Path)
+		}
+		fmt.Printf("%s %s\n", note.Path, formatted)
+	}
+	return nil
+}
+
+// formatMetaValue prints strings and lists of strings as-is, and other values
+// as JSON.
+func formatMetaValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case []interface{}:
+		items := []string{}
+		for _, item := range value {
+			str, ok := item.(string)
+			if !ok {
+				break
+			}
+			items = append(items, str)
+		}
+		if len(items) == len(value) {
+			return strings.Join(items, ", "), nil
+		}
+	}
+
+	res, err := json.Marshal(value)
+	return string(res), err
+}
+
+// MetaSet sets the value of a frontmatter key.
+type MetaSet struct {
+	Assignment string `arg placeholder:KEY=VALUE help:"Frontmatter key and its new value."`
+	Force      bool   `short:f help:"Do not confirm before editing many notes at the same time."`
+	cli.Filtering
+}
+
+func (cmd *MetaSet) Run(container *cli.Container) error {
+	key, value, err := parseMetaAssignment(cmd.Assignment)
+	if err != nil {
+		return err
+	}
+	return editMeta(container, cmd.Filtering, cmd.Force, core.SetFrontmatterValue(key, value))
+}
+
+// MetaUnset removes a frontmatter key.
+type MetaUnset struct {
+	Key   string `arg placeholder:KEY help:"Frontmatter key to remove."`
+	Force bool   `short:f help:"Do not confirm before editing many notes at the same time."`
+	cli.Filtering
+}
+
+func (cmd *MetaUnset) Run(container *cli.Container) error {
+	if err := core.ValidateFrontmatterKey(cmd.Key); err != nil {
+		return err
+	}
+	return editMeta(container, cmd.Filtering, cmd.Force, core.UnsetFrontmatterKey(cmd.Key))
+}
+
+// MetaAdd adds items to a frontmatter list.
+type MetaAdd struct {
+	Assignment string `arg placeholder:KEY=ITEMS help:"Frontmatter list key and the comma-separated items to add."`
+	Force      bool   `short:f help:"Do not confirm before editing many notes at the same time."`
+	cli.Filtering
+}
+
+func (cmd *MetaAdd) Run(container *cli.Container) error {
+	key, value, err := parseMetaAssignment(cmd.Assignment)
+	if err != nil {
+		return err
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("%s: no items to add", cmd.Assignment)
+	}
+	return editMeta(container, cmd.Filtering, cmd.Force, core.AddFrontmatterItems(key, items))
+}
+
+// parseMetaAssignment splits a KEY=VALUE argument.
+func parseMetaAssignment(assignment string) (key string, value string, err error) {
+	key, value, found := strings.Cut(assignment, "=")
+	if !found {
+		return "", "", fmt.Errorf("%s: expected KEY=VALUE", assignment)
+	}
+	key = strings.TrimSpace(key)
+	if err := core.ValidateFrontmatterKey(key); err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// editMeta applies the given frontmatter edit to the notes matching the
+// filtering criteria, then updates the index.
+func editMeta(container *cli.Container, filtering cli.Filtering, force bool, edit core.FrontmatterEdit) error {
+	notes, err := selectNotes(container, filtering)
+	if err != nil {
+		return err
+	}
+
+	count := len(notes)
+	if count == 0 {
+		fmt.Fprintln(os.Stderr, "Found 0 notes.")
+		return nil
+	}
+	if !force && count > 5 {
+		confirmed, skipped := container.Terminal.Confirm(fmt.Sprintf("Are you sure you want to edit %v notes?", count), false)
+		if skipped {
+			return fmt.Errorf("too many notes to be edited, aborting…")
+		} else if !confirmed {
+			return nil
+		}
+	}
+
+	notebook := notes[0].Notebook
+	absPaths := []string{}
+	for _, note := range notes {
+		absPath := filepath.Join(note.Notebook.Path, note.Path)
+		changed, err := notebook.EditNoteFrontmatter(absPath, edit)
+		if err != nil {
+			return err
+		}
+		if changed {
+			absPaths = append(absPaths, absPath)
+		}
+	}
+
+	if len(absPaths) > 0 {
+		_, err = notebook.IndexPaths(absPaths)
+		if err != nil {
+			return err
+		}
+	}
+
+	updated := len(absPaths)
+	fmt.Fprintf(os.Stderr, "Updated %d %s\n", updated, strutil.Pluralize("note", updated))
+	return nil
+}
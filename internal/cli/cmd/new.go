@@ -26,6 +26,7 @@ type New struct {
 	PrintPath   bool              `short:p                     help:"Print the path of the created note instead of editing it."`
 	DryRun      bool              `short:n                     help:"Don't actually create the note. Instead, prints its content on stdout and the generated path on stderr."`
 	ID          string            `          placeholder:ID    help:"Skip id generation and use provided value."`
+	After       string            `          placeholder:ID    help:"Derive the ID from the given parent note, with the folgezettel ID strategy."`
 }
 
 func (cmd *New) Run(container *cli.Container) error {
@@ -60,6 +61,7 @@ func (cmd *New) Run(container *cli.Container) error {
 		Date:      date,
 		DryRun:    cmd.DryRun,
 		ID:        cmd.ID,
+		After:     cmd.After,
 	})
 
 	if cmd.DryRun {
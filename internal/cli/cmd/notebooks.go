@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/opt"
+)
+
+// findNotes retrieves the notes matching the given filtering options, either
+// in the current notebook or in all the known notebooks.
+//
+// Notes found in the current notebook have an empty NotebookName.
+func findNotes(container *cli.Container, filtering cli.Filtering, allNotebooks bool) ([]core.NotebookNote, error) {
+	if allNotebooks {
+		notebooks, err := container.AllNotebooks()
+		if err != nil {
+			return nil, err
+		}
+		err = indexOtherNotebooks(container, notebooks)
+		if err != nil {
+			return nil, err
+		}
+		notes, err := core.FindNotesInNotebooks(notebooks, func(notebook *core.Notebook) (core.NoteFindOpts, error) {
+			opts, err := filtering.NewNoteFindOpts(notebook)
+			return opts, errors.Wrapf(err, "incorrect criteria")
+		})
+		return notes, err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return nil, err
+	}
+
+	findOpts, err := filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return nil, errors.Wrapf(err, "incorrect criteria")
+	}
+
+	found, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]core.NotebookNote, 0, len(found))
+	for _, note := range found {
+		notes = append(notes, core.NotebookNote{
+			ContextualNote: note,
+			Notebook:       notebook,
+		})
+	}
+	return notes, nil
+}
+
+// indexOtherNotebooks brings the index of the given notebooks up to date,
+// except the current one which is indexed when zk starts.
+func indexOtherNotebooks(container *cli.Container, notebooks map[string]*core.Notebook) error {
+	current, _ := container.CurrentNotebook()
+	for _, notebook := range notebooks {
+		if current != nil && notebook.Path == current.Path {
+			continue
+		}
+		index := Index{Quiet: true}
+		err := index.RunWithNotebook(container, notebook)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectNotes returns the notes of the current notebook matching the given
+// criteria, optionally filtered interactively.
+func selectNotes(container *cli.Container, filtering cli.Filtering) ([]core.NotebookNote, error) {
+	notes, err := findNotes(container, filtering, false)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, _, err = filterNotesOnce(container, fzf.NoteFilterOpts{
+		Interactive:  filtering.Interactive,
+		AlwaysFilter: false,
+	}, notes)
+	if err == fzf.ErrCancelled {
+		return nil, nil
+	}
+	return notes, err
+}
+
+// findNoteIDs returns the IDs of the notes of the given notebook matching the
+// filtering options, optionally selected with fzf. Returns nil when all the
+// notes are selected.
+func findNoteIDs(container *cli.Container, notebook *core.Notebook, filtering cli.Filtering) ([]core.NoteID, error) {
+	if filtering.IsEmpty() && !filtering.Interactive {
+		return nil, nil
+	}
+
+	findOpts, err := filtering.NewNoteFindOpts(notebook)
+	if err != nil {
+		return nil, errors.Wrapf(err, "incorrect criteria")
+	}
+	notes, err := notebook.FindNotes(findOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err = container.NewNoteFilter(fzf.NoteFilterOpts{
+		Interactive:  filtering.Interactive,
+		AlwaysFilter: false,
+		NotebookDir:  notebook.Path,
+	}).Apply(notes)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]core.NoteID, 0, len(notes))
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	return ids, nil
+}
+
+// filterNotes filters the given notes with fzf, if needed. It returns the
+// selected notes and the name of the action triggered by the user on them, or
+// an empty string for the default action.
+//
+// The backlinks action is handled by filtering again the notes linking to the
+// selection.
+func filterNotes(container *cli.Container, opts fzf.NoteFilterOpts, notes []core.NotebookNote) ([]core.NotebookNote, string, error) {
+	actions, err := noteActions(container)
+	if err != nil {
+		return nil, "", err
+	}
+	opts.Actions = actions
+
+	for {
+		selected, action, err := filterNotesOnce(container, opts, notes)
+		if err != nil || action != "backlinks" {
+			return selected, action, err
+		}
+
+		notes, err = findBacklinks(selected)
+		if err != nil {
+			return nil, "", err
+		}
+		// The live search only applies to the initial filtering options.
+		opts.ReloadCmd = opt.NullString
+		opts.AlwaysFilter = true
+	}
+}
+
+func filterNotesOnce(container *cli.Container, opts fzf.NoteFilterOpts, notes []core.NotebookNote) ([]core.NotebookNote, string, error) {
+	contextualNotes := make([]core.ContextualNote, 0, len(notes))
+	opts.NotebookDirs = make([]string, 0, len(notes))
+	for _, note := range notes {
+		contextualNotes = append(contextualNotes, note.ContextualNote)
+		opts.NotebookDirs = append(opts.NotebookDirs, note.Notebook.Path)
+	}
+
+	selection, err := container.NewNoteFilter(opts).Select(contextualNotes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// After a live search, the notes all belong to the current notebook.
+	var notebook *core.Notebook
+	if !opts.ReloadCmd.IsNull() {
+		notebook, err = container.CurrentNotebook()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	selectedNotes := make([]core.NotebookNote, 0, len(selection.Indexes))
+	for _, i := range selection.Indexes {
+		if notebook != nil {
+			selectedNotes = append(selectedNotes, core.NotebookNote{
+				ContextualNote: selection.Notes[i],
+				Notebook:       notebook,
+			})
+		} else {
+			selectedNotes = append(selectedNotes, notes[i])
+		}
+	}
+	return selectedNotes, selection.Action, nil
+}
+
+// findBacklinks returns the notes linking to the given ones.
+func findBacklinks(notes []core.NotebookNote) ([]core.NotebookNote, error) {
+	hrefs := map[*core.Notebook][]string{}
+	notebooks := []*core.Notebook{}
+	for _, note := range notes {
+		if _, ok := hrefs[note.Notebook]; !ok {
+			notebooks = append(notebooks, note.Notebook)
+		}
+		hrefs[note.Notebook] = append(hrefs[note.Notebook], note.Path)
+	}
+
+	backlinks := []core.NotebookNote{}
+	for _, notebook := range notebooks {
+		found, err := notebook.FindNotes(core.NoteFindOpts{
+			LinkTo: &core.LinkFilter{Hrefs: hrefs[notebook]},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range found {
+			backlinks = append(backlinks, core.NotebookNote{
+				ContextualNote: note,
+				Notebook:       notebook,
+			})
+		}
+	}
+	return backlinks, nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// listLayout holds the options shaping the output of a listing command.
+type listLayout struct {
+	Header    string
+	Footer    string
+	Delimiter string
+	NoPager   bool
+}
+
+// newListLayout creates the layout of a listing command from its flags,
+// expanding the whitespace literals. The JSON formats require their own
+// header, footer and delimiter.
+func newListLayout(format string, header string, footer string, delimiter string, noPager bool) (listLayout, error) {
+	layout := listLayout{
+		Header:    strutil.ExpandWhitespaceLiterals(header),
+		Footer:    strutil.ExpandWhitespaceLiterals(footer),
+		Delimiter: strutil.ExpandWhitespaceLiterals(delimiter),
+		NoPager:   noPager,
+	}
+
+	if format == "json" || format == "jsonl" {
+		if layout.Header != "" {
+			return layout, errors.New("--header can't be used with JSON format")
+		}
+		if layout.Footer != "\n" {
+			return layout, errors.New("--footer can't be used with JSON format")
+		}
+		if layout.Delimiter != "\n" {
+			return layout, errors.New("--delimiter can't be used with JSON format")
+		}
+
+		switch format {
+		case "json":
+			layout.Delimiter = ","
+			layout.Header = "["
+			layout.Footer = "]\n"
+
+		case "jsonl":
+			// > The last character in the file may be a line separator, and it
+			// > will be treated the same as if there was no line separator
+			// > present.
+			// > https://jsonlines.org/
+			layout.Footer = "\n"
+		}
+	}
+
+	return layout, nil
+}
+
+// printItems prints count items in the user's pager, each of them formatted
+// by the format callback with its index.
+func printItems(container *cli.Container, layout listLayout, count int, format func(i int) (string, error)) error {
+	if count == 0 {
+		return nil
+	}
+
+	return container.Paginate(layout.NoPager, func(out io.Writer) error {
+		if layout.Header != "" {
+			fmt.Fprint(out, layout.Header)
+		}
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				fmt.Fprint(out, layout.Delimiter)
+			}
+
+			item, err := format(i)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(out, item)
+		}
+		if layout.Footer != "" {
+			fmt.Fprint(out, layout.Footer)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNewListLayout(t *testing.T) {
+	test := func(format, header, footer, delimiter string, expected listLayout) {
+		layout, err := newListLayout(format, header, footer, delimiter, false)
+		assert.Nil(t, err)
+		assert.Equal(t, layout, expected)
+	}
+
+	test("oneline", "", "\n", "\n", listLayout{Footer: "\n", Delimiter: "\n"})
+	test("oneline", `\t`, `\n\n`, "; ", listLayout{Header: "\t", Footer: "\n\n", Delimiter: "; "})
+	test("json", "", "\n", "\n", listLayout{Header: "[", Footer: "]\n", Delimiter: ","})
+	test("jsonl", "", "\n", "\n", listLayout{Footer: "\n", Delimiter: "\n"})
+}
+
+func TestNewListLayoutRejectsJSONWithLayout(t *testing.T) {
+	test := func(header, footer, delimiter string, expected string) {
+		_, err := newListLayout("json", header, footer, delimiter, false)
+		assert.Err(t, err, expected)
+	}
+
+	test("[", "\n", "\n", "--header can't be used with JSON format")
+	test("", "]", "\n", "--footer can't be used with JSON format")
+	test("", "\n", ",", "--delimiter can't be used with JSON format")
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Review runs a spaced repetition session with the flashcards found in the
+// notes matching a set of criteria.
+type Review struct {
+	List     bool `help:"List the cards due for a review instead of reviewing them."`
+	All      bool `short:a help:"Include the cards which are not due yet."`
+	MaxCards int  `placeholder:COUNT help:"Review at most the given number of cards."`
+	NoPager  bool `short:P help:"Do not pipe the list of cards into a pager."`
+	Quiet    bool `short:q help:"Do not print the total number of cards found."`
+	cli.Filtering
+}
+
+// reviewGrades are the answers offered after revealing a card, in the order
+// of their core.CardGrade value.
+var reviewGrades = []string{"Again", "Hard", "Good", "Easy", "Quit"}
+
+func (cmd *Review) Run(container *cli.Container) error {
+	if cmd.MaxCards < 0 {
+		return errors.New("--max-cards must be a positive number")
+	}
+	if !cmd.List && !container.Terminal.IsInteractive() {
+		return errors.New("a review session needs an interactive terminal, use --list to print the due cards")
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	noteIDs, err := findNoteIDs(container, notebook, cmd.Filtering)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	opts := core.CardFindOpts{NoteIDs: noteIDs}
+	if !cmd.All {
+		opts.DueBefore = &now
+	}
+	cards, err := notebook.FindCards(opts)
+	if err != nil {
+		return err
+	}
+	if cmd.MaxCards > 0 && len(cards) > cmd.MaxCards {
+		cards = cards[:cmd.MaxCards]
+	}
+
+	if cmd.List {
+		return cmd.list(container, cards)
+	}
+	return cmd.review(container, notebook, cards)
+}
+
+// list prints the given cards with their due date.
+func (cmd *Review) list(container *cli.Container, cards []core.NoteCard) error {
+	var err error
+	count := len(cards)
+	if count > 0 {
+		err = container.Paginate(cmd.NoPager, func(out io.Writer) error {
+			for _, card := range cards {
+				due := "new"
+				if card.Schedule != nil {
+					due = card.Schedule.Due.Local().Format("2006-01-02")
+				}
+				fmt.Fprintf(out, "%s:%d %s %s\n",
+					container.Terminal.MustStyle(card.Path, core.StylePath),
+					card.Line,
+					container.Terminal.MustStyle(due, core.StyleUnderstate),
+					card.Question,
+				)
+			}
+			return nil
+		})
+	}
+
+	if err == nil && !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("card", count))
+	}
+	return err
+}
+
+// review asks the user to recall the answer of each card, then schedules its
+// next review according to the given grade. Forgotten cards are asked again
+// at the end of the session.
+func (cmd *Review) review(container *cli.Container, notebook *core.Notebook, cards []core.NoteCard) error {
+	term := container.Terminal
+	if len(cards) == 0 {
+		fmt.Fprintln(os.Stderr, "No cards to review.")
+		return nil
+	}
+
+	reviewed := 0
+	for i := 0; i < len(cards); i++ {
+		card := cards[i]
+
+		fmt.Printf("\n%s %s\n\n%s\n\n",
+			term.MustStyle(fmt.Sprintf("[%d/%d]", i+1, len(cards)), core.StyleUnderstate),
+			term.MustStyle(fmt.Sprintf("%s:%d", card.Path, card.Line), core.StylePath),
+			term.MustStyle(card.Question, core.StyleTitle),
+		)
+		if _, skipped := term.Input("Press Enter to show the answer"); skipped {
+			break
+		}
+		fmt.Printf("\n%s\n\n", card.Answer)
+
+		answer, skipped := term.Select("How well did you remember it?", reviewGrades)
+		if skipped || answer == len(reviewGrades)-1 {
+			break
+		}
+
+		grade := core.CardGrade(answer + 1)
+		schedule, err := notebook.ReviewCard(card, grade, time.Now())
+		if err != nil {
+			return err
+		}
+		reviewed++
+
+		if grade == core.CardAgain {
+			card.Schedule = &schedule
+			cards = append(cards, card)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\nReviewed %d %s\n", reviewed, strutil.Pluralize("card", reviewed))
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Stats summarizes the notes matching a set of criteria.
+type Stats struct {
+	Format  string `group:format short:f placeholder:TEMPLATE help:"Pretty print the report using a custom template or one of the predefined formats: text, json."`
+	NoPager bool   `group:format short:P help:"Do not pipe output into a pager."`
+	Period  string `group:format default:month placeholder:PERIOD help:"Group the created and modified notes by week or month."`
+	Top     int    `group:format default:10 placeholder:COUNT help:"Maximum number of periods, tags and notes listed, 0 for all of them."`
+
+	cli.Filtering
+}
+
+func (cmd *Stats) Run(container *cli.Container) error {
+	period, err := core.StatsPeriodFromString(cmd.Period)
+	if err != nil {
+		return err
+	}
+	if cmd.Top < 0 {
+		return errors.New("--top must be a positive number")
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	format, err := notebook.NewStatsFormatter(cmd.statsTemplate())
+	if err != nil {
+		return err
+	}
+
+	noteIDs, err := findNoteIDs(container, notebook, cmd.Filtering)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	stats, err := notebook.Stats(core.StatsOpts{
+		NoteIDs: noteIDs,
+		Period:  period,
+		Limit:   cmd.Top,
+	})
+	if err != nil {
+		return err
+	}
+
+	report, err := format(stats)
+	if err != nil {
+		return err
+	}
+
+	return container.Paginate(cmd.NoPager, func(out io.Writer) error {
+		fmt.Fprintln(out, strings.TrimRight(report, "\n"))
+		return nil
+	})
+}
+
+func (cmd *Stats) statsTemplate() string {
+	format := cmd.Format
+	if format == "" {
+		format = "text"
+	}
+
+	templ, ok := defaultStatsFormats[format]
+	if !ok {
+		templ = strutil.ExpandWhitespaceLiterals(format)
+	}
+
+	return templ
+}
+
+var defaultStatsFormats = map[string]string{
+	"json": `{{json .}}`,
+
+	"text": `Notes:          {{note-count}}
+Words:          {{word-count}}
+Tags:           {{tag-count}}
+Links:          {{link-count}}
+Orphans:        {{orphan-count}} ({{orphan-ratio}})
+Average degree: {{average-degree}}{{#if created}}
+
+{{style "title" (concat "Created per " period)}}
+{{#each created}}  {{period}}: {{note-count}}
+{{/each}}{{/if}}{{#if modified}}
+{{style "title" (concat "Modified per " period)}}
+{{#each modified}}  {{period}}: {{note-count}}
+{{/each}}{{/if}}{{#if tags}}
+{{style "title" "Most active tags"}}
+{{#each tags}}  {{style "term" name}}: {{note-count}}
+{{/each}}{{/if}}{{#if largest-notes}}
+{{style "title" "Largest notes"}}
+{{#each largest-notes}}  {{style "path" path}}: {{word-count}} words
+{{/each}}{{/if}}`,
+}
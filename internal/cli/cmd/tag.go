@@ -5,10 +5,11 @@ import (
 	"io"
 	"os"
 
+	"github.com/zk-org/zk/internal/adapter/fzf"
 	"github.com/zk-org/zk/internal/cli"
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util/errors"
-	"github.com/zk-org/zk/internal/util/strings"
+	strutil "github.com/zk-org/zk/internal/util/strings"
 )
 
 // Tag manages the note tags in the notebook.
@@ -18,7 +19,8 @@ type Tag struct {
 
 // TagList lists all the note tags.
 type TagList struct {
-	Format     string   `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: name, full, json, jsonl."`
+	Format     string   `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: name, full, json, jsonl, table, csv, tsv, yaml."`
+	Columns    []string `group:format short:c placeholder:COLUMN     help:"Columns printed with the table, csv, tsv and yaml formats, among: id, kind, name, note-count. Implies --format table."`
 	Header     string   `group:format                                help:"Arbitrary text printed at the start of the list."`
 	Footer     string   `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
 	Delimiter  string   "group:format short:d default:\n             help:\"Print tags delimited by the given separator.\""
@@ -26,12 +28,19 @@ type TagList struct {
 	NoPager    bool     `group:format short:P help:"Do not pipe output into a pager."`
 	Quiet      bool     `group:format short:q help:"Do not print the total number of tags found."`
 	Sort       []string `group:sort short:s placeholder:TERM help:"Order the tags by the given criterion."`
+
+	Interactive bool `group:filter short:i help:"Select tags interactively with fzf, then list their notes."`
+	Edit        bool `group:filter short:e help:"Edit the notes of the tags selected with --interactive instead of listing them."`
 }
 
 func (cmd *TagList) Run(container *cli.Container) error {
-	cmd.Header = strings.ExpandWhitespaceLiterals(cmd.Header)
-	cmd.Footer = strings.ExpandWhitespaceLiterals(cmd.Footer)
-	cmd.Delimiter = strings.ExpandWhitespaceLiterals(cmd.Delimiter)
+	cmd.Header = strutil.ExpandWhitespaceLiterals(cmd.Header)
+	cmd.Footer = strutil.ExpandWhitespaceLiterals(cmd.Footer)
+	cmd.Delimiter = strutil.ExpandWhitespaceLiterals(cmd.Delimiter)
+
+	if cmd.Edit && !cmd.Interactive {
+		return errors.New("--edit requires --interactive")
+	}
 
 	if cmd.Delimiter0 {
 		if cmd.Delimiter != "\n" {
@@ -48,6 +57,20 @@ func (cmd *TagList) Run(container *cli.Container) error {
 		cmd.Footer = "\x00"
 	}
 
+	var err error
+	hasLayout := cmd.Header != "" || cmd.Footer != "\n" || cmd.Delimiter != "\n"
+	cmd.Format, cmd.Columns, err = cli.ResolveRecordColumns(cmd.Format, cmd.Columns, hasLayout, core.DefaultCollectionColumns, core.ValidateCollectionColumns)
+	if err != nil {
+		return err
+	}
+	var columnsFormat core.CollectionColumnsFormatter
+	if cli.IsRecordFormat(cmd.Format) {
+		columnsFormat, err = core.NewCollectionColumnsFormatter(cmd.Columns)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmd.Format == "json" || cmd.Format == "jsonl" {
 		if cmd.Header != "" {
 			return errors.New("--header can't be used with JSON format")
@@ -94,8 +117,33 @@ func (cmd *TagList) Run(container *cli.Container) error {
 		return err
 	}
 
+	if cmd.Interactive {
+		tags, err = container.NewCollectionFilter(fzf.CollectionFilterOpts{
+			Interactive: true,
+			NotebookDir: notebook.Path,
+		}).Apply(tags)
+		if err != nil {
+			if err == fzf.ErrCancelled {
+				return nil
+			}
+			return err
+		}
+		return cmd.runWithNotes(container, tags)
+	}
+
 	count := len(tags)
-	if count > 0 {
+	if count > 0 && columnsFormat != nil {
+		records := make([][]interface{}, 0, count)
+		for _, tag := range tags {
+			records = append(records, columnsFormat(tag))
+		}
+		err = container.Paginate(cmd.NoPager, func(out io.Writer) error {
+			return cli.WriteRecords(out, cmd.Format, cmd.Columns, records, cli.RecordsOpts{
+				Width:  container.Terminal.Width(),
+				Styler: container.Styler,
+			})
+		})
+	} else if count > 0 {
 		err = container.Paginate(cmd.NoPager, func(out io.Writer) error {
 			if cmd.Header != "" {
 				fmt.Fprint(out, cmd.Header)
@@ -120,12 +168,41 @@ func (cmd *TagList) Run(container *cli.Container) error {
 	}
 
 	if err == nil && !cmd.Quiet {
-		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strings.Pluralize("tag", count))
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("tag", count))
 	}
 
 	return err
 }
 
+// runWithNotes lists or edits the notes tagged with any of the given tags.
+func (cmd *TagList) runWithNotes(container *cli.Container, tags []core.Collection) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	filtering := cli.Filtering{
+		AnyTag: names,
+	}
+
+	if cmd.Edit {
+		edit := Edit{Filtering: filtering}
+		return edit.Run(container)
+	}
+
+	list := List{
+		Footer:    "\n",
+		Delimiter: "\n",
+		NoPager:   cmd.NoPager,
+		Quiet:     cmd.Quiet,
+		Filtering: filtering,
+	}
+	return list.Run(container)
+}
+
 func (cmd *TagList) tagTemplate() string {
 	format := cmd.Format
 	if format == "" {
@@ -134,7 +211,7 @@ func (cmd *TagList) tagTemplate() string {
 
 	templ, ok := defaultTagFormats[format]
 	if !ok {
-		templ = strings.ExpandWhitespaceLiterals(format)
+		templ = strutil.ExpandWhitespaceLiterals(format)
 	}
 
 	return templ
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/core"
+	dateutil "github.com/zk-org/zk/internal/util/date"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Tasks lists the task list items found in the notes matching a set of
+// criteria.
+type Tasks struct {
+	Format    string `group:format short:f placeholder:TEMPLATE   help:"Pretty print the list using a custom template or one of the predefined formats: oneline, full, json, jsonl."`
+	Header    string `group:format                                help:"Arbitrary text printed at the start of the list."`
+	Footer    string `group:format default:\n                     help:"Arbitrary text printed at the end of the list."`
+	Delimiter string "group:format short:d default:\n             help:\"Print tasks delimited by the given separator.\""
+	NoPager   bool   `group:format short:P help:"Do not pipe output into a pager."`
+	Quiet     bool   `group:format short:q help:"Do not print the total number of tasks found."`
+
+	Open      bool     `group:filter help:"Find only the tasks which are not checked."`
+	Done      bool     `group:filter help:"Find only the checked tasks."`
+	DueBefore string   `group:filter placeholder:DATE help:"Find tasks due before the given date."`
+	DueAfter  string   `group:filter placeholder:DATE help:"Find tasks due after the given date."`
+	TaskTag   []string `group:filter placeholder:TAG help:"Find tasks with the given inline tags."`
+	cli.Filtering
+}
+
+func (cmd *Tasks) Run(container *cli.Container) error {
+	if cmd.Open && cmd.Done {
+		return errors.New("--open and --done can't be used together")
+	}
+
+	layout, err := newListLayout(cmd.Format, cmd.Header, cmd.Footer, cmd.Delimiter, cmd.NoPager)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	format, err := notebook.NewTaskFormatter(cmd.taskTemplate())
+	if err != nil {
+		return err
+	}
+
+	opts, err := cmd.newTaskFindOpts()
+	if err != nil {
+		return errors.Wrapf(err, "incorrect criteria")
+	}
+	opts.NoteIDs, err = findNoteIDs(container, notebook, cmd.Filtering)
+	if err != nil {
+		if err == fzf.ErrCancelled {
+			return nil
+		}
+		return err
+	}
+
+	tasks, err := notebook.FindTasks(opts)
+	if err != nil {
+		return err
+	}
+
+	count := len(tasks)
+	err = printItems(container, layout, count, func(i int) (string, error) {
+		return format(tasks[i])
+	})
+
+	if err == nil && !cmd.Quiet {
+		fmt.Fprintf(os.Stderr, "\nFound %d %s\n", count, strutil.Pluralize("task", count))
+	}
+
+	return err
+}
+
+// newTaskFindOpts creates the task filtering options from the command flags.
+func (cmd *Tasks) newTaskFindOpts() (core.TaskFindOpts, error) {
+	opts := core.TaskFindOpts{
+		Tags: cmd.TaskTag,
+	}
+
+	if cmd.Open || cmd.Done {
+		done := cmd.Done
+		opts.Done = &done
+	}
+	if cmd.DueBefore != "" {
+		date, err := dateutil.TimeFromNatural(cmd.DueBefore)
+		if err != nil {
+			return opts, err
+		}
+		opts.DueBefore = &date
+	}
+	if cmd.DueAfter != "" {
+		date, err := dateutil.TimeFromNatural(cmd.DueAfter)
+		if err != nil {
+			return opts, err
+		}
+		opts.DueAfter = &date
+	}
+
+	return opts, nil
+}
+
+func (cmd *Tasks) taskTemplate() string {
+	format := cmd.Format
+	if format == "" {
+		format = "oneline"
+	}
+
+	templ, ok := defaultTaskFormats[format]
+	if !ok {
+		templ = strutil.ExpandWhitespaceLiterals(format)
+	}
+
+	return templ
+}
+
+var defaultTaskFormats = map[string]string{
+	"json":  `{{json .}}`,
+	"jsonl": `{{json .}}`,
+
+	"oneline": `{{style "path" path}}:{{line}} {{#if done}}[x]{{else}}[ ]{{/if}} {{text}}`,
+
+	"full": `{{#if done}}[x]{{else}}[ ]{{/if}} {{text}}
+  {{style "title" title}} {{style "path" path}}:{{line}}{{#if due}}
+  Due: {{format-date due "short"}}{{/if}}
+`,
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zk-org/zk/internal/adapter/handlebars"
+	"github.com/zk-org/zk/internal/cli"
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// Template manages the note templates.
+type Template struct {
+	List     TemplateList     `cmd group:"cmd" default:"withargs" help:"List the available templates."`
+	Show     TemplateShow     `cmd group:"cmd" help:"Print the content of a template."`
+	Validate TemplateValidate `cmd group:"cmd" help:"Render templates against a sample note to check them for errors."`
+}
+
+// TemplateList lists the templates available in the notebook.
+type TemplateList struct {
+	Path bool `short:p help:"Print the absolute paths of the templates."`
+}
+
+func (cmd *TemplateList) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	templates, err := findTemplates(container.TemplateDirs(notebook))
+	if err != nil {
+		return err
+	}
+	for _, template := range templates {
+		if cmd.Path {
+			fmt.Println(template.path)
+		} else {
+			fmt.Println(template.name)
+		}
+	}
+	return nil
+}
+
+// TemplateShow prints the content of a template.
+type TemplateShow struct {
+	Name string `arg placeholder:TEMPLATE help:"Name of the template, e.g. default.md or partials/header.md."`
+}
+
+func (cmd *TemplateShow) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	path := cmd.Name
+	if !filepath.IsAbs(path) {
+		templates, err := findTemplates(container.TemplateDirs(notebook))
+		if err != nil {
+			return err
+		}
+		path = ""
+		for _, template := range templates {
+			if template.name == filepath.ToSlash(cmd.Name) {
+				path = template.path
+				break
+			}
+		}
+		if path == "" {
+			return fmt.Errorf("%s: template not found", cmd.Name)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(content))
+	return nil
+}
+
+// TemplateValidate renders templates against a sample note.
+type TemplateValidate struct {
+	Names []string `arg optional placeholder:TEMPLATE help:"Names of the templates to validate. Defaults to all of them, except the partials."`
+	Print bool     `short:p help:"Print the rendered templates."`
+}
+
+func (cmd *TemplateValidate) Run(container *cli.Container) error {
+	notebook, err := container.CurrentNotebook()
+	if err != nil {
+		return err
+	}
+
+	names := cmd.Names
+	if len(names) == 0 {
+		templates, err := findTemplates(container.TemplateDirs(notebook))
+		if err != nil {
+			return err
+		}
+		for _, template := range templates {
+			if !strings.HasPrefix(template.name, handlebars.PartialsDir+"/") {
+				names = append(names, template.name)
+			}
+		}
+	}
+
+	invalid := 0
+	for _, name := range names {
+		res, err := notebook.RenderTemplateSample(name)
+		if err != nil {
+			invalid++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			continue
+		}
+		if cmd.Print {
+			fmt.Printf("%s:\n%s\n", name, res)
+		} else {
+			fmt.Printf("%s: OK\n", name)
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid %s", invalid, strutil.Pluralize("template", invalid))
+	}
+	return nil
+}
+
+// templateFile is a template found in one of the templates directories.
+type templateFile struct {
+	// Path relative to the templates directory.
+	name string
+	path string
+}
+
+// findTemplates returns the template files found in the given directories,
+// sorted by name. A template shadowed by another one in a previous directory
+// is skipped.
+func findTemplates(dirs []string) ([]templateFile, error) {
+	templates := []templateFile{}
+	found := map[string]bool{}
+
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir && os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if strings.HasPrefix(d.Name(), ".") && path != dir {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			name, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			name = filepath.ToSlash(name)
+			if !found[name] {
+				found[name] = true
+				templates = append(templates, templateFile{name: name, path: path})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list the templates")
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].name < templates[j].name
+	})
+	return templates, nil
+}
@@ -1,21 +1,27 @@
 package cli
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/zk-org/zk/internal/adapter/editor"
 	"github.com/zk-org/zk/internal/adapter/fs"
 	"github.com/zk-org/zk/internal/adapter/fzf"
+	"github.com/zk-org/zk/internal/adapter/git"
 	"github.com/zk-org/zk/internal/adapter/handlebars"
 	hbhelpers "github.com/zk-org/zk/internal/adapter/handlebars/helpers"
 	"github.com/zk-org/zk/internal/adapter/markdown"
+	"github.com/zk-org/zk/internal/adapter/plugin"
 	"github.com/zk-org/zk/internal/adapter/sqlite"
 	"github.com/zk-org/zk/internal/adapter/term"
 	"github.com/zk-org/zk/internal/core"
 	"github.com/zk-org/zk/internal/util"
 	"github.com/zk-org/zk/internal/util/errors"
+	executil "github.com/zk-org/zk/internal/util/exec"
 	osutil "github.com/zk-org/zk/internal/util/os"
 	"github.com/zk-org/zk/internal/util/pager"
 	"github.com/zk-org/zk/internal/util/paths"
@@ -39,6 +45,7 @@ type Container struct {
 	Notebooks          *core.NotebookStore
 	currentNotebook    *core.Notebook
 	currentNotebookErr error
+	plugins            []*plugin.Registry
 }
 
 func NewContainer(version string) (*Container, error) {
@@ -86,7 +93,7 @@ func NewContainer(version string) (*Container, error) {
 		os.Setenv("ZK_SHELL", config.Tool.Shell.Unwrap())
 	}
 
-	return &Container{
+	container := &Container{
 		Version:        version,
 		Config:         config,
 		Logger:         logger,
@@ -94,60 +101,192 @@ func NewContainer(version string) (*Container, error) {
 		Terminal:       term,
 		FS:             fs,
 		TemplateLoader: templateLoader,
-		Notebooks: core.NewNotebookStore(config, core.NotebookStorePorts{
-			FS:             fs,
-			TemplateLoader: templateLoader,
-			NotebookFactory: func(path string, config core.Config) (*core.Notebook, error) {
-				dbPath := filepath.Join(path, ".zk/notebook.db")
-				db, err := sqlite.Open(dbPath)
-				if err != nil {
-					return nil, err
-				}
-
-				notebook := core.NewNotebook(path, config, core.NotebookPorts{
-					NoteIndex: sqlite.NewNoteIndex(path, db, logger),
-					NoteContentParser: markdown.NewParser(
-						markdown.ParserOpts{
-							HashtagEnabled:      config.Format.Markdown.Hashtags,
-							MultiWordTagEnabled: config.Format.Markdown.MultiwordTags,
-							ColontagEnabled:     config.Format.Markdown.ColonTags,
-						},
-						logger,
-					),
-					TemplateLoaderFactory: func(language string) (core.TemplateLoader, error) {
-						loader := handlebars.NewLoader(handlebars.LoaderOpts{
-							LookupPaths: []string{
-								filepath.Join(globalConfigDir(), "templates"),
-								filepath.Join(path, ".zk/templates"),
-							},
-							Styler: styler,
-						})
-
-						loader.RegisterHelper("style", hbhelpers.NewStyleHelper(styler, logger))
-						loader.RegisterHelper("slug", hbhelpers.NewSlugHelper(language, logger))
-
-						linkFormatter, err := core.NewLinkFormatter(config.Format.Markdown, loader)
-						if err != nil {
-							return nil, err
-						}
-						loader.RegisterHelper("format-link", hbhelpers.NewLinkHelper(linkFormatter, logger))
+	}
+	container.Notebooks = core.NewNotebookStore(config, core.NotebookStorePorts{
+		FS:             fs,
+		TemplateLoader: templateLoader,
+		NotebookFactory: func(path string, config core.Config) (*core.Notebook, error) {
+			dbPath := filepath.Join(path, ".zk/notebook.db")
+			db, err := sqlite.Open(dbPath)
+			if err != nil {
+				return nil, err
+			}
 
-						return loader, nil
-					},
-					IDGeneratorFactory: func(opts core.IDOptions) func() string {
-						return rand.NewIDGenerator(opts)
-					},
-					FS:     fs,
-					Logger: logger,
-					OSEnv: func() map[string]string {
-						return osutil.Env()
+			noteIndex := sqlite.NewNoteIndex(path, db, logger)
+
+			plugins := plugin.NewRegistry(config.Plugins, plugin.Context{
+				Version:     version,
+				NotebookDir: path,
+				Config:      config,
+			}, noteIndex, logger)
+			container.plugins = append(container.plugins, plugins)
+			manifests := plugins.Manifests()
+			config.Filters = pluginFilters(manifests, config.Filters)
+
+			// The template helpers querying the notebook need the
+			// notebook, which is not created yet.
+			querier := &notebookQuerier{}
+
+			notebook := core.NewNotebook(path, config, core.NotebookPorts{
+				NoteIndex: noteIndex,
+				NoteContentParser: markdown.NewParser(
+					markdown.ParserOpts{
+						HashtagEnabled:      config.Format.Markdown.Hashtags,
+						MultiWordTagEnabled: config.Format.Markdown.MultiwordTags,
+						ColontagEnabled:     config.Format.Markdown.ColonTags,
 					},
-				})
+					logger,
+				),
+				TemplateLoaderFactory: func(noteConfig core.NoteConfig) (core.TemplateLoader, error) {
+					loader := handlebars.NewLoader(handlebars.LoaderOpts{
+						LookupPaths: templateDirs(path),
+						Styler:      styler,
+					})
+
+					loader.RegisterHelper("style", hbhelpers.NewStyleHelper(styler, logger))
+					loader.RegisterHelper("slug", hbhelpers.NewSlugHelper(noteConfig.Lang, logger))
+					loader.RegisterHelper("weekday", hbhelpers.NewWeekdayHelper(noteConfig.Lang, logger))
+					loader.RegisterHelper("start-of", hbhelpers.NewStartOfHelper(noteConfig.WeekStart, logger))
+					loader.RegisterHelper("end-of", hbhelpers.NewEndOfHelper(noteConfig.WeekStart, logger))
+
+					linkFormatter, err := core.NewLinkFormatter(config.Format.Markdown, loader)
+					if err != nil {
+						return nil, err
+					}
+					loader.RegisterHelper("format-link", hbhelpers.NewLinkHelper(linkFormatter, logger))
+					loader.RegisterHelper("notes", hbhelpers.NewNotesHelper(querier, logger))
+					loader.RegisterHelper("backlinks", hbhelpers.NewBacklinksHelper(querier, logger))
+					loader.RegisterHelper("note-link", hbhelpers.NewNoteLinkHelper(querier, logger))
+					loader.RegisterHelper("tags", hbhelpers.NewTagsHelper(querier, logger))
+
+					for name, manifest := range manifests {
+						name := name
+						callHelper := func(helper string, params []interface{}, hash map[string]interface{}) (string, error) {
+							return plugins.CallHelper(name, helper, params, hash)
+						}
+						for _, helper := range manifest.Helpers {
+							loader.RegisterHelper(helper.Name, hbhelpers.NewPluginHelper(helper.Name, helper.Params, callHelper, logger))
+						}
+					}
+
+					return loader, nil
+				},
+				IDGeneratorFactory: func(opts core.IDOptions) func() string {
+					return rand.NewIDGenerator(opts)
+				},
+				FS:          fs,
+				HookRunner:  runHook,
+				NoteHistory: git.NewHistory(path, logger),
+				Logger:      logger,
+				OSEnv: func() map[string]string {
+					return osutil.Env()
+				},
+			})
+			querier.notebook = notebook
+
+			return notebook, nil
+		},
+	})
+
+	return container, nil
+}
+
+// pluginFilters returns the named filters of the config merged with the ones
+// registered by the plugins. The filters of the config take precedence.
+func pluginFilters(manifests map[string]plugin.Manifest, filters map[string]string) map[string]string {
+	if len(manifests) == 0 {
+		return filters
+	}
+
+	merged := map[string]string{}
+	for _, manifest := range manifests {
+		for name, filter := range manifest.Filters {
+			merged[name] = filter
+		}
+	}
+	for name, filter := range filters {
+		merged[name] = filter
+	}
+	return merged
+}
+
+// Close stops the plugins started by the opened notebooks.
+func (c *Container) Close() {
+	for _, plugins := range c.plugins {
+		plugins.Close()
+	}
+}
+
+// notebookQuerier gives the template helpers access to the notes of a
+// notebook, using the `zk list` filtering options.
+type notebookQuerier struct {
+	notebook *core.Notebook
+}
+
+func (q *notebookQuerier) FindNotes(query string, limit int) ([]interface{}, error) {
+	filtering, err := ParseFiltering(query)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		filtering.Limit = limit
+	}
+
+	opts, err := filtering.NewNoteFindOpts(q.notebook)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid query `%s`", query)
+	}
+	notes, err := q.notebook.FindNotes(opts)
+	if err != nil {
+		return nil, err
+	}
+	return q.notebook.NoteFormatContexts(notes)
+}
+
+func (q *notebookQuerier) FindNote(path string) (interface{}, error) {
+	relPath, err := q.notebook.RelPath(path)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := q.notebook.FindNotes(core.NoteFindOpts{
+		IncludeHrefs: []string{relPath},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The paths are matched by prefix, but the helper expects a single note.
+	exact := []core.ContextualNote{}
+	for _, note := range notes {
+		if note.Path == relPath || paths.DropExt(note.Path) == relPath {
+			exact = append(exact, note)
+		}
+	}
+	switch len(exact) {
+	case 0:
+		return nil, nil
+	case 1:
+		contexts, err := q.notebook.NoteFormatContexts(exact)
+		if err != nil {
+			return nil, err
+		}
+		return contexts[0], nil
+	default:
+		return nil, fmt.Errorf("%s: ambiguous path matching several notes", path)
+	}
+}
+
+func (q *notebookQuerier) FindTags() ([]core.Collection, error) {
+	return q.notebook.FindCollections(core.CollectionKindTag, nil)
+}
 
-				return notebook, nil
-			},
-		}),
-	}, nil
+// templateDirs returns the directories holding the user templates of the
+// notebook at the given path, by order of precedence.
+func templateDirs(notebookPath string) []string {
+	return []string{
+		filepath.Join(globalConfigDir(), "templates"),
+		filepath.Join(notebookPath, ".zk/templates"),
+	}
 }
 
 // locateGlobalConfig looks for the global zk config file following the
@@ -220,11 +359,45 @@ func (c *Container) setWorkingDir(path string) {
 	c.FS.SetWorkingDir(path)
 }
 
+// TemplateDirs returns the directories holding the user templates of the
+// given notebook, by order of precedence.
+func (c *Container) TemplateDirs(notebook *core.Notebook) []string {
+	return templateDirs(notebook.Path)
+}
+
 // CurrentNotebook returns the current default notebook.
 func (c *Container) CurrentNotebook() (*core.Notebook, error) {
 	return c.currentNotebook, c.currentNotebookErr
 }
 
+// AllNotebooks returns the notebooks declared in the global config, indexed by
+// their alias. The current notebook is included under the name of its
+// directory, if it is not already declared.
+func (c *Container) AllNotebooks() (map[string]*core.Notebook, error) {
+	notebooks, err := c.Notebooks.OpenAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.currentNotebook != nil {
+		found := false
+		for _, notebook := range notebooks {
+			if notebook.Path == c.currentNotebook.Path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			notebooks[filepath.Base(c.currentNotebook.Path)] = c.currentNotebook
+		}
+	}
+
+	if len(notebooks) == 0 {
+		return nil, errors.New("no notebooks declared in the [notebooks] section of the global config")
+	}
+	return notebooks, nil
+}
+
 func (c *Container) NewNoteFilter(opts fzf.NoteFilterOpts) *fzf.NoteFilter {
 	opts.PreviewCmd = c.Config.Tool.FzfPreview
 	opts.LineTemplate = c.Config.Tool.FzfLine
@@ -233,8 +406,155 @@ func (c *Container) NewNoteFilter(opts fzf.NoteFilterOpts) *fzf.NoteFilter {
 	return fzf.NewNoteFilter(opts, c.FS, c.Terminal, c.TemplateLoader)
 }
 
-func (c *Container) NewNoteEditor(notebook *core.Notebook) (*editor.Editor, error) {
-	return editor.NewEditor(notebook.Config.Tool.Editor)
+func (c *Container) NewCollectionFilter(opts fzf.CollectionFilterOpts) *fzf.CollectionFilter {
+	opts.FzfOptions = c.Config.Tool.FzfOptions
+	return fzf.NewCollectionFilter(opts, c.Terminal)
+}
+
+func (c *Container) NewNoteEditor(notebook *core.Notebook) (*NoteEditor, error) {
+	editor, err := editor.NewEditor(notebook.Config.Tool.Editor)
+	if err != nil {
+		return nil, err
+	}
+	return &NoteEditor{editor: editor, notebooks: c.Notebooks, logger: c.Logger}, nil
+}
+
+// NoteEditor opens notes in the user's editor, running the pre-edit and
+// post-edit hooks of their notebook around it.
+type NoteEditor struct {
+	editor    *editor.Editor
+	notebooks *core.NotebookStore
+	logger    util.Logger
+}
+
+// Open launches the editor with the notes at given absolute paths.
+//
+// Encrypted notes are decrypted into private temporary files, which are
+// encrypted back into the notes when the editor is closed. The temporary
+// files are kept if the editor fails or a note cannot be saved, to not lose
+// the user's changes.
+func (e *NoteEditor) Open(paths ...string) error {
+	err := e.runHooks(core.HookPreEdit, paths)
+	if err != nil {
+		return err
+	}
+
+	tmpDir := ""
+	keepTmpDir := false
+	defer func() {
+		if tmpDir != "" && !keepTmpDir {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+	editPaths := make([]string, len(paths))
+	plaintexts := map[string][]byte{}
+	for i, path := range paths {
+		editPaths[i] = path
+		notebook, err := e.notebooks.Open(path)
+		if err != nil {
+			return err
+		}
+		if !notebook.IsEncrypted(path) {
+			continue
+		}
+		content, err := notebook.ReadNote(path)
+		if err != nil {
+			return err
+		}
+		if tmpDir == "" {
+			// Prefer the user runtime directory, which is usually not
+			// persisted on disk.
+			tmpDir, err = os.MkdirTemp(os.Getenv("XDG_RUNTIME_DIR"), "zk-")
+			if err != nil {
+				return err
+			}
+		}
+		editPaths[i] = filepath.Join(tmpDir, strconv.Itoa(i), filepath.Base(path))
+		err = os.Mkdir(filepath.Dir(editPaths[i]), 0700)
+		if err == nil {
+			err = os.WriteFile(editPaths[i], content, 0600)
+		}
+		if err != nil {
+			return err
+		}
+		plaintexts[path] = content
+	}
+
+	err = e.editor.Open(editPaths...)
+	if err != nil {
+		if len(plaintexts) > 0 {
+			keepTmpDir = true
+			return errors.Wrapf(err, "the decrypted notes are kept in %s", tmpDir)
+		}
+		return err
+	}
+
+	// Save all the changed notes before reporting the first error.
+	var firstErr error
+	for i, path := range paths {
+		plaintext, ok := plaintexts[path]
+		if !ok {
+			continue
+		}
+		err := e.saveDecryptedNote(path, editPaths[i], plaintext)
+		if err == nil {
+			continue
+		}
+		keepTmpDir = true
+		err = errors.Wrapf(err, "%s: the decrypted note is kept in %s", path, editPaths[i])
+		if firstErr == nil {
+			firstErr = err
+		} else {
+			e.logger.Err(err)
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return e.runHooks(core.HookPostEdit, paths)
+}
+
+// saveDecryptedNote encrypts back the content of the temporary file at
+// editPath into the note at path, if it was changed.
+func (e *NoteEditor) saveDecryptedNote(path string, editPath string, plaintext []byte) error {
+	content, err := os.ReadFile(editPath)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, plaintext) {
+		return nil
+	}
+	notebook, err := e.notebooks.Open(path)
+	if err != nil {
+		return err
+	}
+	return notebook.WriteNote(path, content)
+}
+
+func (e *NoteEditor) runHooks(event core.HookEvent, paths []string) error {
+	for _, path := range paths {
+		notebook, err := e.notebooks.Open(path)
+		if err != nil {
+			return err
+		}
+		err = notebook.RunNoteHook(event, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runHook runs a hook command from the given directory with the given
+// standard input, and returns its standard output. The standard error of the
+// hook is forwarded to the user.
+func runHook(command string, dir string, input []byte) ([]byte, error) {
+	cmd := executil.CommandFromString(command)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	return cmd.Output()
 }
 
 // Paginate creates an auto-closing io.Writer which will be automatically
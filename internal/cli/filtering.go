@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
@@ -9,7 +11,7 @@ import (
 	"github.com/zk-org/zk/internal/core"
 	dateutil "github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/errors"
-	"github.com/zk-org/zk/internal/util/strings"
+	strutil "github.com/zk-org/zk/internal/util/strings"
 )
 
 // Filtering holds filtering options to select notes.
@@ -32,6 +34,7 @@ type Filtering struct {
 	Tagless         bool     `kong:"group='filter',help='Find notes which have no tags.'" json:"tagless"`
 	MissingBacklink bool     `kong:"group='filter',help='Find notes with at least one missing backlink.'" json:"missingBacklink"`
 	Related         []string `kong:"group='filter',placeholder='PATH',help='Find notes which might be related to the given ones.'" json:"related"`
+	SimilarTo       []string `kong:"group='filter',placeholder='PATH',help='Find notes with a content similar to the given ones.'" json:"similarTo"`
 	MaxDistance     int      `kong:"group='filter',placeholder='COUNT',help='Maximum distance between two linked notes.'" json:"maxDistance"`
 	Recursive       bool     `kong:"group='filter',short='r',help='Follow links recursively.'" json:"recursive"`
 	Created         string   `kong:"group='filter',placeholder='DATE',help:'Find notes created on the given date.'" json:"created"`
@@ -40,9 +43,14 @@ type Filtering struct {
 	Modified        string   `kong:"group='filter',placeholder='DATE',help='Find notes modified on the given date.'" json:"modified"`
 	ModifiedBefore  string   `kong:"group='filter',placeholder='DATE',help='Find notes modified before the given date.'" json:"modifiedBefore"`
 	ModifiedAfter   string   `kong:"group='filter',placeholder='DATE',help='Find notes modified after the given date.'" json:"modifiedAfter"`
+	ModifiedBy      []string `kong:"group='filter',placeholder='AUTHOR',help='Find notes modified by the given git authors.'" json:"modifiedBy"`
+	ChangedSince    string   `kong:"group='filter',placeholder='REV',help='Find notes changed since the given git revision.'" json:"changedSince"`
 
 	Sort []string `kong:"group='sort',short='s',placeholder='TERM',help='Order the notes by the given criterion.'" json:"sort"`
 
+	// Tag names selected by the user, e.g. with `zk tag list --interactive`.
+	AnyTag []string `kong:"-" json:"anyTags"`
+
 	// Deprecated
 	ExactMatch bool `kong:"hidden,short='e'" json:"exactMatch"`
 }
@@ -52,19 +60,14 @@ func (f Filtering) ExpandNamedFilters(filters map[string]string, expandedFilters
 	actualPaths := []string{}
 
 	for _, path := range f.Path {
-		if filter, ok := filters[path]; ok && !strings.Contains(expandedFilters, path) {
+		if filter, ok := filters[path]; ok && !strutil.Contains(expandedFilters, path) {
 			wrap := errors.Wrapperf("failed to expand named filter `%v`", path)
 
-			var parsedFilter Filtering
-			parser, err := kong.New(&parsedFilter)
-			if err != nil {
-				return f, wrap(err)
-			}
 			args, err := shellquote.Split(filter)
 			if err != nil {
 				return f, wrap(err)
 			}
-			_, err = parser.Parse(args)
+			parsedFilter, err := parseFiltering(args, false)
 			if err != nil {
 				return f, wrap(err)
 			}
@@ -86,6 +89,8 @@ func (f Filtering) ExpandNamedFilters(filters map[string]string, expandedFilters
 			f.LinkedBy = append(f.LinkedBy, parsedFilter.LinkedBy...)
 			f.NoLinkedBy = append(f.NoLinkedBy, parsedFilter.NoLinkedBy...)
 			f.Related = append(f.Related, parsedFilter.Related...)
+			f.SimilarTo = append(f.SimilarTo, parsedFilter.SimilarTo...)
+			f.ModifiedBy = append(f.ModifiedBy, parsedFilter.ModifiedBy...)
 			f.Sort = append(f.Sort, parsedFilter.Sort...)
 
 			f.ExactMatch = f.ExactMatch || parsedFilter.ExactMatch
@@ -119,6 +124,9 @@ func (f Filtering) ExpandNamedFilters(filters map[string]string, expandedFilters
 			if f.ModifiedAfter == "" {
 				f.ModifiedAfter = parsedFilter.ModifiedAfter
 			}
+			if f.ChangedSince == "" {
+				f.ChangedSince = parsedFilter.ChangedSince
+			}
 
 			f.Match = append(f.Match, parsedFilter.Match...)
 			if f.MatchStrategy == "" {
@@ -134,6 +142,79 @@ func (f Filtering) ExpandNamedFilters(filters map[string]string, expandedFilters
 	return f, nil
 }
 
+// IsEmpty returns whether none of the options restricting the selected notes
+// is set.
+func (f Filtering) IsEmpty() bool {
+	// These options don't restrict the selection by themselves.
+	f.Interactive = false
+	f.MatchStrategy = ""
+	f.ExactMatch = false
+	f.Sort = nil
+	return reflect.DeepEqual(f, Filtering{})
+}
+
+// ParseFiltering parses a query made of `zk list` filtering arguments, e.g.
+// `--tag project --sort modified-`.
+//
+// The long flags can be written with a shorter `name:value` syntax, for
+// example `tag:project sort:modified-`.
+func ParseFiltering(query string) (Filtering, error) {
+	wrap := errors.Wrapperf("invalid query `%s`", query)
+
+	args, err := shellquote.Split(query)
+	if err != nil {
+		return Filtering{}, wrap(err)
+	}
+	filtering, err := parseFiltering(args, true)
+	if err != nil {
+		return filtering, wrap(err)
+	}
+	return filtering, nil
+}
+
+// parseFiltering parses the given command line arguments into a Filtering.
+// When expandShorthands is true, `name:value` arguments are converted into
+// `--name=value` flags, if name is a known long flag. The values of the
+// previous flags are left untouched.
+func parseFiltering(args []string, expandShorthands bool) (Filtering, error) {
+	var filtering Filtering
+	parser, err := kong.New(&filtering)
+	if err != nil {
+		return filtering, err
+	}
+
+	if expandShorthands {
+		// Whether each flag expects a value, by long and short name.
+		flags := map[string]bool{}
+		for _, flag := range parser.Model.Flags {
+			flags["--"+flag.Name] = !flag.IsBool()
+			if flag.Short != 0 {
+				flags["-"+string(flag.Short)] = !flag.IsBool()
+			}
+		}
+
+		expectsValue := false
+		for i, arg := range args {
+			if expectsValue {
+				expectsValue = false
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				expectsValue = !strings.Contains(arg, "=") && flags[arg]
+				continue
+			}
+			if name, value, ok := strings.Cut(arg, ":"); ok {
+				if _, isFlag := flags["--"+name]; isFlag {
+					args[i] = "--" + name + "=" + value
+				}
+			}
+		}
+	}
+
+	_, err = parser.Parse(args)
+	return filtering, err
+}
+
 // NewNoteFindOpts creates an instance of core.NoteFindOpts from a set of user flags.
 func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts, error) {
 	opts := core.NoteFindOpts{}
@@ -165,6 +246,9 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 	if len(f.Tag) > 0 {
 		opts.Tags = f.Tag
 	}
+	if len(f.AnyTag) > 0 {
+		opts.AnyTags = f.AnyTag
+	}
 
 	if len(f.Mention) > 0 {
 		opts.Mention = f.Mention
@@ -206,6 +290,10 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 		opts.Related = paths
 	}
 
+	if paths, ok := relPaths(notebook, f.SimilarTo); ok {
+		opts.SimilarTo = paths
+	}
+
 	opts.Orphan = f.Orphan
 	opts.Tagless = f.Tagless
 	opts.MissingBacklink = f.MissingBacklink
@@ -258,6 +346,18 @@ func (f Filtering) NewNoteFindOpts(notebook *core.Notebook) (core.NoteFindOpts,
 		}
 	}
 
+	historyFilter := core.NoteHistoryFilter{
+		ChangedSince: f.ChangedSince,
+		ModifiedBy:   f.ModifiedBy,
+	}
+	if !historyFilter.IsEmpty() {
+		ids, err := notebook.FindNoteIDsInHistory(historyFilter)
+		if err != nil {
+			return opts, err
+		}
+		opts.HistoryIDs = ids
+	}
+
 	sorters, err := core.NoteSortersFromStrings(f.Sort)
 	if err != nil {
 		return opts, err
@@ -203,3 +203,36 @@ func TestExpandNamedFiltersReportsParsingError(t *testing.T) {
 
 	assert.Err(t, err, "failed to expand named filter `f1`: unknown flag --test")
 }
+
+func TestFilteringIsEmpty(t *testing.T) {
+	assert.True(t, Filtering{}.IsEmpty())
+	assert.True(t, Filtering{Interactive: true, MatchStrategy: "fts", Sort: []string{"title"}}.IsEmpty())
+	assert.False(t, Filtering{Tag: []string{"a"}}.IsEmpty())
+	assert.False(t, Filtering{Limit: 3}.IsEmpty())
+	assert.False(t, Filtering{Orphan: true}.IsEmpty())
+
+	f, err := ParseFiltering("")
+	assert.Nil(t, err)
+	assert.True(t, f.IsEmpty())
+}
+
+func TestParseFiltering(t *testing.T) {
+	f, err := ParseFiltering(`tag:project --sort modified- "dir/a note.md" work:note.md -n 3 link-to:"a b"`)
+	assert.Nil(t, err)
+	assert.Equal(t, f.Tag, []string{"project"})
+	assert.Equal(t, f.Sort, []string{"modified-"})
+	assert.Equal(t, f.Path, []string{"dir/a note.md", "work:note.md"})
+	assert.Equal(t, f.LinkTo, []string{"a b"})
+	assert.Equal(t, f.Limit, 3)
+	assert.Equal(t, f.MatchStrategy, "fts")
+
+	// The values of the flags are not expanded.
+	f, err = ParseFiltering(`--match tag:project -x link-to:a sort:title`)
+	assert.Nil(t, err)
+	assert.Equal(t, f.Match, []string{"tag:project"})
+	assert.Equal(t, f.Exclude, []string{"link-to:a"})
+	assert.Equal(t, f.Sort, []string{"title"})
+
+	_, err = ParseFiltering("--test")
+	assert.Err(t, err, "invalid query `--test`: unknown flag --test")
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/zk-org/zk/internal/core"
+	"github.com/zk-org/zk/internal/util/errors"
+	yamlutil "github.com/zk-org/zk/internal/util/yaml"
+	"gopkg.in/yaml.v2"
+)
+
+// RecordFormats lists the output formats printing a list of records made of
+// the same columns.
+var RecordFormats = []string{"table", "csv", "tsv", "yaml"}
+
+// IsRecordFormat returns whether the given output format prints records.
+func IsRecordFormat(format string) bool {
+	for _, f := range RecordFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveRecordColumns checks the --format and --columns options of a listing
+// command, and returns the actual format and columns to print.
+//
+// The table format is used by default when columns are given, and the
+// defaultColumns are printed when none are given with a record format.
+// hasLayout tells whether the --header, --footer or --delimiter options are
+// set, which are incompatible with the record formats.
+func ResolveRecordColumns(format string, columns []string, hasLayout bool, defaultColumns []string, validate func(columns []string) error) (string, []string, error) {
+	if len(columns) > 0 && format == "" {
+		format = "table"
+	}
+	if !IsRecordFormat(format) {
+		if len(columns) > 0 {
+			return format, columns, errors.New("--columns requires one of the table, csv, tsv or yaml formats")
+		}
+		return format, columns, nil
+	}
+
+	if hasLayout {
+		return format, columns, fmt.Errorf("--header, --footer and --delimiter can't be used with the %s format", format)
+	}
+	if len(columns) == 0 {
+		columns = defaultColumns
+	}
+	return format, columns, validate(columns)
+}
+
+// RecordsOpts holds the options used to print records.
+type RecordsOpts struct {
+	// Maximum width of the lines of a table, 0 to disable the truncation.
+	Width int
+	// Styler used to style the header of a table.
+	Styler core.Styler
+}
+
+// WriteRecords prints the records, made of the values of the given columns,
+// in one of the RecordFormats.
+func WriteRecords(w io.Writer, format string, columns []string, records [][]interface{}, opts RecordsOpts) error {
+	switch format {
+	case "table":
+		return writeTable(w, columns, records, opts)
+	case "csv":
+		return writeCSV(w, columns, records)
+	case "tsv":
+		return writeTSV(w, columns, records)
+	case "yaml":
+		return writeYAML(w, columns, records)
+	default:
+		return fmt.Errorf("%s: unknown records format", format)
+	}
+}
+
+// tableGap is the space between two columns of a table.
+const tableGap = "  "
+
+// minColumnWidth is the minimum width of a column truncated to fit the
+// terminal.
+const minColumnWidth = 5
+
+func writeTable(w io.Writer, columns []string, records [][]interface{}, opts RecordsOpts) error {
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	rows := [][]string{header}
+	for _, record := range records {
+		row := make([]string, 0, len(record))
+		for _, value := range record {
+			// Multi-line values would break the alignment.
+			cell := recordString(value, "2006-01-02 15:04")
+			row = append(row, strings.Join(strings.Fields(cell), " "))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := runewidth.StringWidth(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	if opts.Width > 0 {
+		shrinkColumns(widths, opts.Width-len(tableGap)*(len(columns)-1))
+	}
+
+	for r, row := range rows {
+		line := ""
+		for i, cell := range row {
+			if i > 0 {
+				line += tableGap
+			}
+			cell = runewidth.Truncate(cell, widths[i], "…")
+			padding := strings.Repeat(" ", widths[i]-runewidth.StringWidth(cell))
+			if r == 0 && opts.Styler != nil {
+				styled, err := opts.Styler.Style(cell, core.StyleEmphasis)
+				if err != nil {
+					return err
+				}
+				cell = styled
+			}
+			line += cell + padding
+		}
+		_, err := fmt.Fprintln(w, strings.TrimRight(line, " "))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shrinkColumns reduces the widest columns until the sum of the widths fits
+// in the available width, if possible.
+func shrinkColumns(widths []int, available int) {
+	for {
+		total := 0
+		widest := 0
+		for i, width := range widths {
+			total += width
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if total <= available || widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func writeCSV(w io.Writer, columns []string, records [][]interface{}) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(columns)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, 0, len(record))
+		for _, value := range record {
+			row = append(row, recordString(value, time.RFC3339))
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// tsvEscaper escapes the characters which are not allowed in the fields of
+// a TSV file.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func writeTSV(w io.Writer, columns []string, records [][]interface{}) error {
+	_, err := fmt.Fprintln(w, strings.Join(columns, "\t"))
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, 0, len(record))
+		for _, value := range record {
+			row = append(row, tsvEscaper.Replace(recordString(value, time.RFC3339)))
+		}
+		_, err = fmt.Fprintln(w, strings.Join(row, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeYAML(w io.Writer, columns []string, records [][]interface{}) error {
+	items := make([]yaml.MapSlice, 0, len(records))
+	for _, record := range records {
+		item := yaml.MapSlice{}
+		for i, value := range record {
+			item = append(item, yaml.MapItem{Key: columns[i], Value: value})
+		}
+		items = append(items, item)
+	}
+
+	out, err := yaml.Marshal(items)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// recordString converts a value of a record to a single line of text, using
+// the given layout for dates.
+func recordString(value interface{}, dateLayout string) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format(dateLayout)
+	case []string:
+		return strings.Join(value, ", ")
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, recordString(item, dateLayout))
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}, map[interface{}]interface{}:
+		out, err := json.Marshal(yamlutil.ConvertToJSONCompatible(value))
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(out)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+var testRecordColumns = []string{"title", "tags", "created", "meta.status"}
+
+var testRecords = [][]interface{}{
+	{"First note", []string{"a", "b"}, time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC), "draft"},
+	{"Second, \"quoted\"\tnote", []string{}, time.Time{}, nil},
+}
+
+func testWriteRecords(t *testing.T, format string, opts RecordsOpts, expected string) {
+	t.Helper()
+	var out strings.Builder
+	err := WriteRecords(&out, format, testRecordColumns, testRecords, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, out.String(), expected)
+}
+
+func TestWriteRecordsTable(t *testing.T) {
+	testWriteRecords(t, "table", RecordsOpts{}, ""+
+		"TITLE                  TAGS  CREATED           META.STATUS\n"+
+		"First note             a, b  2009-11-17 20:34  draft\n"+
+		"Second, \"quoted\" note\n",
+	)
+}
+
+func TestWriteRecordsTableTruncated(t *testing.T) {
+	testWriteRecords(t, "table", RecordsOpts{Width: 40}, ""+
+		"TITLE       TAGS  CREATED     META.STAT…\n"+
+		"First note  a, b  2009-11-1…  draft\n"+
+		"Second, \"…\n",
+	)
+}
+
+func TestWriteRecordsCSV(t *testing.T) {
+	testWriteRecords(t, "csv", RecordsOpts{}, ""+
+		"title,tags,created,meta.status\n"+
+		"First note,\"a, b\",2009-11-17T20:34:58Z,draft\n"+
+		"\"Second, \"\"quoted\"\"\tnote\",,,\n",
+	)
+}
+
+func TestWriteRecordsTSV(t *testing.T) {
+	testWriteRecords(t, "tsv", RecordsOpts{}, ""+
+		"title\ttags\tcreated\tmeta.status\n"+
+		"First note\ta, b\t2009-11-17T20:34:58Z\tdraft\n"+
+		"Second, \"quoted\"\\tnote\t\t\t\n",
+	)
+}
+
+func TestWriteRecordsYAML(t *testing.T) {
+	testWriteRecords(t, "yaml", RecordsOpts{}, ""+
+		"- title: First note\n"+
+		"  tags:\n"+
+		"  - a\n"+
+		"  - b\n"+
+		"  created: 2009-11-17T20:34:58Z\n"+
+		"  meta.status: draft\n"+
+		"- title: \"Second, \\\"quoted\\\"\\tnote\"\n"+
+		"  tags: []\n"+
+		"  created: 0001-01-01T00:00:00Z\n"+
+		"  meta.status: null\n",
+	)
+}
+
+func TestWriteRecordsUnknownFormat(t *testing.T) {
+	err := WriteRecords(&strings.Builder{}, "xml", testRecordColumns, testRecords, RecordsOpts{})
+	assert.Err(t, err, "xml: unknown records format")
+}
+
+func TestResolveRecordColumns(t *testing.T) {
+	defaults := []string{"name"}
+	validate := func(columns []string) error {
+		for _, column := range columns {
+			if column == "unknown" {
+				return errors.New("unknown: unknown column")
+			}
+		}
+		return nil
+	}
+	test := func(format string, columns []string, expectedFormat string, expectedColumns []string) {
+		actualFormat, actualColumns, err := ResolveRecordColumns(format, columns, false, defaults, validate)
+		assert.Nil(t, err)
+		assert.Equal(t, actualFormat, expectedFormat)
+		assert.Equal(t, actualColumns, expectedColumns)
+	}
+
+	test("", nil, "", nil)
+	test("json", nil, "json", nil)
+	test("", []string{"id"}, "table", []string{"id"})
+	test("csv", nil, "csv", []string{"name"})
+	test("yaml", []string{"id", "name"}, "yaml", []string{"id", "name"})
+
+	_, _, err := ResolveRecordColumns("json", []string{"id"}, false, defaults, validate)
+	assert.Err(t, err, "--columns requires one of the table, csv, tsv or yaml formats")
+	_, _, err = ResolveRecordColumns("table", nil, true, defaults, validate)
+	assert.Err(t, err, "--header, --footer and --delimiter can't be used with the table format")
+	_, _, err = ResolveRecordColumns("", []string{"unknown"}, false, defaults, validate)
+	assert.Err(t, err, "unknown: unknown column")
+}
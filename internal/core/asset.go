@@ -0,0 +1,273 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/paths"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// AssetLink is a link from a note to a local file which is not a note, e.g.
+// an image or a PDF.
+type AssetLink struct {
+	// Path of the asset, relative to the notebook root.
+	Path string
+	// Path of the note containing the link.
+	SourcePath string
+}
+
+// AssetStatus indicates whether an asset is referenced by notes and exists in
+// the notebook.
+type AssetStatus string
+
+const (
+	// AssetUsed is an existing asset referenced by at least one note.
+	AssetUsed AssetStatus = "used"
+	// AssetMissing is an asset referenced by notes, which doesn't exist.
+	AssetMissing AssetStatus = "missing"
+	// AssetUnused is a file of the notebook which is not referenced by any
+	// note.
+	AssetUnused AssetStatus = "unused"
+)
+
+// Asset is a local file of the notebook which is not a note, e.g. an image.
+type Asset struct {
+	// Path of the asset, relative to the notebook root.
+	Path string
+	// Status of the asset.
+	Status AssetStatus
+	// Paths of the notes referencing this asset.
+	Sources []string
+}
+
+// assetPathFromHref returns the path of the local file which is not a note,
+// targeted by the given link href relative to the notebook root.
+func assetPathFromHref(config Config, href string) (string, bool) {
+	if href == "" || strutil.IsURL(href) {
+		return "", false
+	}
+	// Remove any anchor or query, e.g. document.pdf#page=2
+	path := strings.SplitN(href, "#", 2)[0]
+	path = strings.SplitN(path, "?", 2)[0]
+	path = filepath.Clean(path)
+	if strings.HasPrefix(path, "..") || filepath.IsAbs(path) {
+		return "", false
+	}
+	if !isAssetPath(config, path) {
+		return "", false
+	}
+	return path, true
+}
+
+// isAssetPath returns whether the given path, relative to the notebook root,
+// is not a note file.
+func isAssetPath(config Config, path string) bool {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		// Links without extension target notes, e.g. [[an-id]].
+		return false
+	}
+	group, err := config.GroupConfigForPath(path)
+	if err != nil {
+		return false
+	}
+	return ext != "."+group.Note.Extension
+}
+
+// IsAssetHref returns whether the given link href, relative to the absolute
+// directory dir, targets a local file which is not a note.
+func (n *Notebook) IsAssetHref(href string, dir string) bool {
+	if href == "" || strutil.IsURL(href) {
+		return false
+	}
+	path, err := n.RelPath(filepath.Join(dir, href))
+	if err != nil {
+		return false
+	}
+	_, ok := assetPathFromHref(n.Config, path)
+	return ok
+}
+
+// FindAssets returns the assets referenced by the notes, and the files of the
+// notebook which are neither notes nor referenced, sorted by path.
+func (n *Notebook) FindAssets() ([]Asset, error) {
+	wrap := errors.Wrapper("failed to find assets")
+
+	links, err := n.index.FindAssetLinks()
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	assets := map[string]*Asset{}
+	for _, link := range links {
+		asset, ok := assets[link.Path]
+		if !ok {
+			asset = &Asset{Path: link.Path, Sources: []string{}}
+			assets[link.Path] = asset
+		}
+		if !strutil.Contains(asset.Sources, link.SourcePath) {
+			asset.Sources = append(asset.Sources, link.SourcePath)
+		}
+	}
+
+	for _, asset := range assets {
+		exists, err := n.fs.FileExists(filepath.Join(n.Path, asset.Path))
+		if err != nil {
+			return nil, wrap(err)
+		}
+		if exists {
+			asset.Status = AssetUsed
+		} else {
+			asset.Status = AssetMissing
+		}
+	}
+
+	notebookPath := &NotebookPath{Path: n.Path}
+	files := paths.Walk(n.Path, n.logger, notebookPath.Filename(), func(path string) (bool, error) {
+		return isIgnoredAssetPath(n.Config, path)
+	})
+	for file := range files {
+		if _, ok := assets[file.Path]; !ok {
+			assets[file.Path] = &Asset{Path: file.Path, Status: AssetUnused, Sources: []string{}}
+		}
+	}
+
+	res := make([]Asset, 0, len(assets))
+	for _, asset := range assets {
+		res = append(res, *asset)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res, nil
+}
+
+// isIgnoredAssetPath returns whether the file at the given path, relative to
+// the notebook root, is a note or excluded by the configuration.
+func isIgnoredAssetPath(config Config, path string) (bool, error) {
+	if !isAssetPath(config, path) {
+		return true, nil
+	}
+
+	group, err := config.GroupConfigForPath(path)
+	if err != nil {
+		return true, err
+	}
+	for _, ignoreGlob := range group.ExcludeGlobs() {
+		matches, err := doublestar.PathMatch(ignoreGlob, path)
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to match exclude glob %s to %s", ignoreGlob, path)
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MoveAsset moves the asset at the path src to dst, both relative to the
+// notebook root, and rewrites the links of the notes referencing it. It
+// returns the paths of the updated notes.
+func (n *Notebook) MoveAsset(src string, dst string) ([]string, error) {
+	wrap := errors.Wrapperf("failed to move %s", src)
+
+	if !isAssetPath(n.Config, src) {
+		return nil, wrap(fmt.Errorf("%s is not an asset", src))
+	}
+	if !isAssetPath(n.Config, dst) {
+		return nil, wrap(fmt.Errorf("%s is not a valid asset path", dst))
+	}
+	absSrc := filepath.Join(n.Path, src)
+	absDst := filepath.Join(n.Path, dst)
+	if exists, err := n.fs.FileExists(absSrc); err != nil || !exists {
+		return nil, wrap(fmt.Errorf("%s: file not found", src))
+	}
+	if exists, err := n.fs.FileExists(absDst); err != nil || exists {
+		return nil, wrap(fmt.Errorf("%s: file already exists", dst))
+	}
+
+	links, err := n.index.FindAssetLinks()
+	if err != nil {
+		return nil, wrap(err)
+	}
+	sources := []string{}
+	for _, link := range links {
+		if link.Path == src && !strutil.Contains(sources, link.SourcePath) {
+			sources = append(sources, link.SourcePath)
+		}
+	}
+
+	err = n.fs.Rename(absSrc, absDst)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	updated := []string{}
+	for _, source := range sources {
+		absSource := filepath.Join(n.Path, source)
+		content, err := n.ReadNote(absSource)
+		if err != nil {
+			return updated, wrap(err)
+		}
+		newContent, changed := rewriteAssetHrefs(string(content), filepath.Dir(source), src, dst)
+		if !changed {
+			continue
+		}
+		err = n.WriteNote(absSource, []byte(newContent))
+		if err != nil {
+			return updated, wrap(err)
+		}
+		updated = append(updated, source)
+	}
+
+	return updated, nil
+}
+
+// assetHrefRegex matches the href of inline Markdown links and images, and of
+// link reference definitions.
+var assetHrefRegex = regexp.MustCompile(`(\]\(\s*<?|(?m:^\s*\[[^\]]+\]:\s*<?))([^\s)>]+)`)
+
+// rewriteAssetHrefs replaces the hrefs of the links targeting the asset src
+// with dst, in the content of a note located in dir. All paths are relative
+// to the notebook root.
+func rewriteAssetHrefs(content string, dir string, src string, dst string) (string, bool) {
+	changed := false
+	newContent := assetHrefRegex.ReplaceAllStringFunc(content, func(match string) string {
+		groups := assetHrefRegex.FindStringSubmatch(match)
+		prefix, href := groups[1], groups[2]
+		if strutil.IsURL(href) {
+			return match
+		}
+
+		path, suffix := href, ""
+		if i := strings.IndexAny(href, "#?"); i >= 0 {
+			path, suffix = href[:i], href[i:]
+		}
+		unescaped, err := url.PathUnescape(path)
+		if err != nil {
+			unescaped = path
+		}
+		if filepath.Clean(filepath.Join(dir, unescaped)) != src {
+			return match
+		}
+
+		newHref, err := filepath.Rel(dir, dst)
+		if err != nil {
+			return match
+		}
+		newHref = filepath.ToSlash(newHref)
+		if unescaped != path || strings.Contains(newHref, " ") {
+			newHref = strings.ReplaceAll(newHref, " ", "%20")
+		}
+		changed = true
+		return prefix + newHref + suffix
+	})
+	return newContent, changed
+}
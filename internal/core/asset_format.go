@@ -0,0 +1,55 @@
+package core
+
+import (
+	"path/filepath"
+)
+
+// AssetFormatter formats assets to be printed on the screen.
+type AssetFormatter func(asset Asset) (string, error)
+
+func newAssetFormatter(basePath string, template Template, fs FileStorage) (AssetFormatter, error) {
+	relPath := func(path string) (string, error) {
+		return NotebookPath{
+			Path:       path,
+			BasePath:   basePath,
+			WorkingDir: fs.WorkingDir(),
+		}.PathRelToWorkingDir()
+	}
+
+	return func(asset Asset) (string, error) {
+		path, err := relPath(asset.Path)
+		if err != nil {
+			return "", err
+		}
+
+		sources := make([]string, 0, len(asset.Sources))
+		for _, source := range asset.Sources {
+			source, err := relPath(source)
+			if err != nil {
+				return "", err
+			}
+			sources = append(sources, source)
+		}
+
+		return template.Render(assetFormatRenderContext{
+			Path:    path,
+			AbsPath: filepath.Join(basePath, asset.Path),
+			Status:  string(asset.Status),
+			Sources: sources,
+		})
+	}, nil
+}
+
+// assetFormatRenderContext holds the variables available to the asset
+// formatting templates.
+type assetFormatRenderContext struct {
+	// Path of the asset, relative to the working directory.
+	Path string `json:"path"`
+	// Absolute path of the asset.
+	AbsPath string `json:"absPath" handlebars:"abs-path"`
+	// Status of the asset: used, missing or unused.
+	Status string `json:"status"`
+	// Paths of the notes referencing the asset, relative to the working
+	// directory.
+	Sources []string `json:"sources"`
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestAssetPathFromHref(t *testing.T) {
+	config := NewDefaultConfig()
+
+	test := func(href string, expectedPath string, expectedOk bool) {
+		path, ok := assetPathFromHref(config, href)
+		assert.Equal(t, path, expectedPath)
+		assert.Equal(t, ok, expectedOk)
+	}
+
+	test("", "", false)
+	test("note", "", false)
+	test("note.md", "", false)
+	test("dir/note.md#anchor", "", false)
+	test("https://example.com/cat.png", "", false)
+	test("../outside.png", "", false)
+	test("/absolute/cat.png", "", false)
+	test("cat.png", "cat.png", true)
+	test("img/../assets/cat.png", "assets/cat.png", true)
+	test("doc.pdf#page=2", "doc.pdf", true)
+	test("doc.pdf?q=1", "doc.pdf", true)
+}
+
+func TestRewriteAssetHrefs(t *testing.T) {
+	test := func(content string, dir string, expectedContent string, expectedChanged bool) {
+		actual, changed := rewriteAssetHrefs(content, dir, "img/cat.png", "assets/cat photo.png")
+		assert.Equal(t, actual, expectedContent)
+		assert.Equal(t, changed, expectedChanged)
+	}
+
+	test("No link", ".", "No link", false)
+	test("![cat](img/dog.png)", ".", "![cat](img/dog.png)", false)
+	test("![cat](https://example.com/img/cat.png)", ".", "![cat](https://example.com/img/cat.png)", false)
+	test("![cat](img/cat.png)", ".", "![cat](assets/cat%20photo.png)", true)
+	test("![cat](<img/cat.png> \"Title\")", ".", "![cat](<assets/cat%20photo.png> \"Title\")", true)
+	test("[cat](../img/cat.png#top)", "log", "[cat](../assets/cat%20photo.png#top)", true)
+	test("A [cat][1]\n\n[1]: img/cat.png\n", ".", "A [cat][1]\n\n[1]: assets/cat%20photo.png\n", true)
+}
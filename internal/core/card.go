@@ -0,0 +1,166 @@
+package core
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Card is a question/answer flashcard found in a note, either written on a
+// single line as `Question::Answer`, or as a section with a heading tagged
+// with #card.
+type Card struct {
+	// Stable identifier of the card, used to keep its review history when the
+	// note is edited.
+	Hash string `json:"hash"`
+	// Question on the front of the card.
+	Question string `json:"question"`
+	// Answer on the back of the card.
+	Answer string `json:"answer"`
+	// Line number of the card in the note content, starting from 1.
+	Line int `json:"line"`
+}
+
+// NewCard creates a new Card with its stable hash.
+//
+// The hash is computed from the question only, so that editing the answer or
+// moving the card keeps its review history.
+func NewCard(question string, answer string, line int) Card {
+	question = strings.TrimSpace(question)
+	normalized := strings.ToLower(strings.Join(strings.Fields(question), " "))
+	return Card{
+		Hash:     fmt.Sprintf("%x", sha256.Sum256([]byte(normalized)))[:16],
+		Question: question,
+		Answer:   strings.TrimSpace(answer),
+		Line:     line,
+	}
+}
+
+// NoteCard is a card with the note containing it and its review schedule.
+type NoteCard struct {
+	Card
+	// Path of the note relative to the root of the notebook.
+	Path string `json:"path"`
+	// Title of the note.
+	Title string `json:"title"`
+	// Review schedule of the card, or nil if it was never reviewed.
+	Schedule *CardSchedule `json:"schedule"`
+}
+
+// CardFindOpts holds a set of filtering options used to find cards.
+type CardFindOpts struct {
+	// Filter the cards never reviewed or due before the given date, or return
+	// all of them when null.
+	DueBefore *time.Time
+	// Filter cards found in the given notes, or in all the notes when nil.
+	NoteIDs []NoteID
+}
+
+// CardGrade is the rating given to the answer recalled during a review.
+type CardGrade int
+
+const (
+	// CardAgain is a wrong answer, the card is learned again from scratch.
+	CardAgain CardGrade = iota + 1
+	// CardHard is a correct answer recalled with serious difficulty.
+	CardHard
+	// CardGood is a correct answer recalled after a hesitation.
+	CardGood
+	// CardEasy is a correct answer recalled perfectly.
+	CardEasy
+)
+
+// CardSchedule is the review state of a card, following the SM-2 algorithm.
+type CardSchedule struct {
+	// Number of consecutive successful reviews.
+	Repetitions int `json:"repetitions"`
+	// Number of days until the next review.
+	Interval int `json:"interval"`
+	// Ease factor multiplying the interval after a successful review.
+	Ease float64 `json:"ease"`
+	// Number of times the card was forgotten.
+	Lapses int `json:"lapses"`
+	// Date of the next review.
+	Due time.Time `json:"due"`
+	// Date of the last review.
+	Reviewed time.Time `json:"reviewed"`
+}
+
+// cardInitialEase is the SM-2 ease factor of a new card.
+const cardInitialEase = 2.5
+
+// cardMinimumEase is the lowest ease factor allowed by SM-2.
+const cardMinimumEase = 1.3
+
+// NewCardSchedule returns the schedule of a card never reviewed.
+func NewCardSchedule() CardSchedule {
+	return CardSchedule{
+		Ease: cardInitialEase,
+	}
+}
+
+// Review returns the next schedule of a card answered with the given grade.
+func (s CardSchedule) Review(grade CardGrade, now time.Time) CardSchedule {
+	if s.Ease == 0 {
+		s.Ease = cardInitialEase
+	}
+
+	// Map the grade to the SM-2 quality of the response, from 0 to 5.
+	var quality float64
+	switch grade {
+	case CardAgain:
+		quality = 0
+	case CardHard:
+		quality = 3
+	case CardGood:
+		quality = 4
+	default:
+		quality = 5
+	}
+
+	if quality < 3 {
+		s.Repetitions = 0
+		s.Interval = 1
+		s.Lapses++
+	} else {
+		switch s.Repetitions {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.Ease))
+		}
+		s.Repetitions++
+	}
+
+	s.Ease += 0.1 - (5-quality)*(0.08+(5-quality)*0.02)
+	// Prevent floating point errors from accumulating across reviews.
+	s.Ease = math.Round(s.Ease*100) / 100
+	if s.Ease < cardMinimumEase {
+		s.Ease = cardMinimumEase
+	}
+
+	s.Reviewed = now
+	s.Due = now.AddDate(0, 0, s.Interval)
+	return s
+}
+
+// FindCards retrieves the cards matching the given filtering options.
+func (n *Notebook) FindCards(opts CardFindOpts) ([]NoteCard, error) {
+	return n.index.FindCards(opts)
+}
+
+// ReviewCard updates the schedule of the given card after answering it with
+// the given grade, and returns the new schedule.
+func (n *Notebook) ReviewCard(card NoteCard, grade CardGrade, now time.Time) (CardSchedule, error) {
+	schedule := NewCardSchedule()
+	if card.Schedule != nil {
+		schedule = *card.Schedule
+	}
+	schedule = schedule.Review(grade, now)
+	err := n.index.SaveCardSchedule(card.Hash, schedule)
+	return schedule, err
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNewCardHashIgnoresAnswerAndWhitespaces(t *testing.T) {
+	card := NewCard("  What is  the capital of France? ", " Paris ", 3)
+	assert.Equal(t, card.Question, "What is  the capital of France?")
+	assert.Equal(t, card.Answer, "Paris")
+	assert.Equal(t, card.Line, 3)
+	assert.Equal(t, len(card.Hash), 16)
+
+	assert.Equal(t, NewCard("what is the capital of france?", "Lyon", 10).Hash, card.Hash)
+	assert.Equal(t, NewCard("What is the capital of Italy?", "Paris", 3).Hash == card.Hash, false)
+}
+
+func TestCardScheduleReview(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	day := func(days int) time.Time {
+		return now.AddDate(0, 0, days)
+	}
+
+	s := NewCardSchedule()
+	assert.Equal(t, s.Ease, 2.5)
+
+	s = s.Review(CardGood, now)
+	assert.Equal(t, s.Repetitions, 1)
+	assert.Equal(t, s.Interval, 1)
+	assert.Equal(t, s.Ease, 2.5)
+	assert.Equal(t, s.Due, day(1))
+	assert.Equal(t, s.Reviewed, now)
+
+	s = s.Review(CardEasy, day(1))
+	assert.Equal(t, s.Repetitions, 2)
+	assert.Equal(t, s.Interval, 6)
+	assert.Equal(t, s.Ease, 2.6)
+	assert.Equal(t, s.Due, day(7))
+
+	s = s.Review(CardGood, day(7))
+	assert.Equal(t, s.Repetitions, 3)
+	assert.Equal(t, s.Interval, 16)
+	assert.Equal(t, s.Due, day(23))
+
+	s = s.Review(CardAgain, day(23))
+	assert.Equal(t, s.Repetitions, 0)
+	assert.Equal(t, s.Interval, 1)
+	assert.Equal(t, s.Lapses, 1)
+	assert.Equal(t, s.Ease, 1.8)
+	assert.Equal(t, s.Due, day(24))
+}
+
+func TestCardScheduleEaseHasAMinimum(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	s := NewCardSchedule()
+	for i := 0; i < 10; i++ {
+		s = s.Review(CardHard, now)
+	}
+	assert.Equal(t, s.Ease, 1.3)
+}
@@ -2,6 +2,10 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+
+	strutil "github.com/zk-org/zk/internal/util/strings"
 )
 
 // CollectionFormatter formats collections to be printed on the screen.
@@ -18,6 +22,53 @@ func newCollectionFormatter(template Template) (CollectionFormatter, error) {
 	}, nil
 }
 
+// CollectionColumns lists the columns available to print collections as
+// records, e.g. in a table.
+var CollectionColumns = []string{"id", "kind", "name", "note-count"}
+
+// DefaultCollectionColumns are the columns printed when none are given.
+var DefaultCollectionColumns = []string{"name", "note-count"}
+
+// CollectionColumnsFormatter returns the values of a set of columns for a
+// collection, to print it as a record.
+type CollectionColumnsFormatter func(collection Collection) []interface{}
+
+// NewCollectionColumnsFormatter returns a CollectionColumnsFormatter used to
+// print the given columns of collections.
+func NewCollectionColumnsFormatter(columns []string) (CollectionColumnsFormatter, error) {
+	if err := ValidateCollectionColumns(columns); err != nil {
+		return nil, err
+	}
+
+	return func(collection Collection) []interface{} {
+		values := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			switch column {
+			case "id":
+				values = append(values, collection.ID)
+			case "kind":
+				values = append(values, collection.Kind)
+			case "name":
+				values = append(values, collection.Name)
+			case "note-count":
+				values = append(values, collection.NoteCount)
+			}
+		}
+		return values
+	}, nil
+}
+
+// ValidateCollectionColumns returns an error if one of the given columns is
+// unknown.
+func ValidateCollectionColumns(columns []string) error {
+	for _, column := range columns {
+		if !strutil.Contains(CollectionColumns, column) {
+			return fmt.Errorf("%s: unknown column, expected one of: %s", column, strings.Join(CollectionColumns, ", "))
+		}
+	}
+	return nil
+}
+
 // collectionFormatRenderContext holds the variables available to the
 // collection formatting templates.
 type collectionFormatRenderContext struct {
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
+	"github.com/lestrrat-go/strftime"
 	toml "github.com/pelletier/go-toml"
+	dateutil "github.com/zk-org/zk/internal/util/date"
 	"github.com/zk-org/zk/internal/util/errors"
 	"github.com/zk-org/zk/internal/util/opt"
 	"github.com/zk-org/zk/internal/util/paths"
@@ -14,15 +17,21 @@ import (
 
 // Config holds the user configuration.
 type Config struct {
-	Notebook NotebookConfig
-	Note     NoteConfig
-	Groups   map[string]GroupConfig
-	Format   FormatConfig
-	Tool     ToolConfig
-	LSP      LSPConfig
-	Filters  map[string]string
-	Aliases  map[string]string
-	Extra    map[string]string
+	Notebook   NotebookConfig
+	Notebooks  map[string]string
+	Note       NoteConfig
+	Groups     map[string]GroupConfig
+	Format     FormatConfig
+	Tool       ToolConfig
+	Hooks      HooksConfig
+	LSP        LSPConfig
+	Git        GitConfig
+	Lint       LintConfig
+	Encryption EncryptionConfig
+	Filters    map[string]string
+	Aliases    map[string]string
+	Plugins    map[string]string
+	Extra      map[string]string
 }
 
 // NOTE: config generation occurs in internal/core/notebook_store.go. The below function is used
@@ -35,16 +44,19 @@ func NewDefaultConfig() Config {
 		Notebook: NotebookConfig{
 			Dir: opt.NullString,
 		},
+		Notebooks: map[string]string{},
 		Note: NoteConfig{
 			FilenameTemplate: "{{id}}",
 			Extension:        "md",
 			BodyTemplatePath: opt.NullString,
 			Lang:             "en",
 			DefaultTitle:     "Untitled",
+			WeekStart:        time.Monday,
 			IDOptions: IDOptions{
-				Charset: CharsetAlphanum,
-				Length:  4,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Charset:  CharsetAlphanum,
+				Length:   4,
+				Case:     CaseLower,
 			},
 			Exclude: []string{},
 		},
@@ -73,8 +85,17 @@ func NewDefaultConfig() Config {
 				MissingBacklink: MissingBacklinkConfig{}, // Disabled by default (Level = LSPDiagnosticNone)
 			},
 		},
+		Lint: LintConfig{
+			MissingTitle:       LSPDiagnosticWarning,
+			HeadingHierarchy:   LSPDiagnosticWarning,
+			TrailingWhitespace: LSPDiagnosticHint,
+		},
+		Encryption: EncryptionConfig{
+			Index: []EncryptedNoteField{EncryptedNoteTitle, EncryptedNoteTags},
+		},
 		Filters: map[string]string{},
 		Aliases: map[string]string{},
+		Plugins: map[string]string{},
 		Extra:   map[string]string{},
 	}
 }
@@ -173,6 +194,63 @@ type ToolConfig struct {
 	FzfLine    opt.String
 	FzfOptions opt.String
 	FzfBindNew opt.String
+	// Key bindings of the fzf actions, indexed by the name of the action.
+	FzfActions map[string]string
+}
+
+// HooksConfig holds the shell commands run on the lifecycle events of the
+// notes.
+type HooksConfig struct {
+	PreNew    string
+	PostNew   string
+	PostIndex string
+	PreEdit   string
+	PostEdit  string
+}
+
+// Command returns the shell command registered for the given event, or an
+// empty string.
+func (c HooksConfig) Command(event HookEvent) string {
+	switch event {
+	case HookPreNew:
+		return c.PreNew
+	case HookPostNew:
+		return c.PostNew
+	case HookPostIndex:
+		return c.PostIndex
+	case HookPreEdit:
+		return c.PreEdit
+	case HookPostEdit:
+		return c.PostEdit
+	default:
+		return ""
+	}
+}
+
+// GitConfig holds the configuration of the git integration.
+type GitConfig struct {
+	// Dates indicates whether the creation and modification dates of the
+	// notes are derived from the git history.
+	Dates bool
+}
+
+// LintConfig holds the severity of the rules checked by `zk lint`. A rule is
+// disabled with LSPDiagnosticNone.
+type LintConfig struct {
+	MissingTitle       LSPDiagnosticSeverity
+	HeadingHierarchy   LSPDiagnosticSeverity
+	TrailingWhitespace LSPDiagnosticSeverity
+}
+
+// EncryptionConfig holds the commands used to encrypt the notes of the
+// groups with `encrypt` enabled, e.g. with age or GnuPG.
+type EncryptionConfig struct {
+	// Shell command encrypting its standard input to its standard output.
+	Encrypt string
+	// Shell command decrypting its standard input to its standard output.
+	Decrypt string
+	// Properties of the encrypted notes saved in the index.
+	Index []EncryptedNoteField
 }
 
 // LSPConfig holds the Language Server Protocol configuration.
@@ -221,6 +299,21 @@ const (
 	LSPDiagnosticHint    LSPDiagnosticSeverity = 4
 )
 
+func (s LSPDiagnosticSeverity) String() string {
+	switch s {
+	case LSPDiagnosticError:
+		return "error"
+	case LSPDiagnosticWarning:
+		return "warning"
+	case LSPDiagnosticInfo:
+		return "info"
+	case LSPDiagnosticHint:
+		return "hint"
+	default:
+		return "none"
+	}
+}
+
 type LSPDiagnosticPosition int
 
 const (
@@ -240,6 +333,13 @@ type NotebookConfig struct {
 	Dir opt.String
 }
 
+// NotebookDir returns the path of the notebook registered with the given
+// alias in the [notebooks] section of the global config.
+func (c Config) NotebookDir(alias string) (string, bool) {
+	dir, ok := c.Notebooks[alias]
+	return dir, ok && dir != ""
+}
+
 // NoteConfig holds the user configuration used when generating new notes.
 type NoteConfig struct {
 	// Handlebars template used when generating a new filename.
@@ -252,10 +352,15 @@ type NoteConfig struct {
 	Lang string
 	// Default title to use when none is provided.
 	DefaultTitle string
+	// First day of the week, used by the calendar template helpers.
+	WeekStart time.Weekday
 	// Settings used when generating a random ID.
 	IDOptions IDOptions
 	// Path globs to ignore when indexing notes.
 	Exclude []string
+	// Indicates whether the notes are encrypted with the commands set in
+	// EncryptionConfig.
+	Encrypt bool
 }
 
 // GroupConfig holds the user configuration for a given group of notes.
@@ -338,6 +443,20 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 		}
 	}
 
+	// Notebooks
+	if len(tomlConf.Notebooks) > 0 {
+		if !isGlobal {
+			return config, wrap(errors.New("notebooks should not be set on local configuration"))
+		}
+		for alias, dir := range tomlConf.Notebooks {
+			expanded, err := paths.ExpandPath(dir)
+			if err != nil {
+				return config, wrap(err)
+			}
+			config.Notebooks[alias] = expanded
+		}
+	}
+
 	// Note
 	note := tomlConf.Note
 	if note.Filename != "" {
@@ -353,6 +472,18 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 		}
 		config.Note.BodyTemplatePath = opt.NewNotEmptyString(expanded)
 	}
+	if note.IDStrategy != "" {
+		config.Note.IDOptions.Strategy, err = IDStrategyFromString(note.IDStrategy)
+		if err != nil {
+			return config, wrap(err)
+		}
+	}
+	if note.IDFormat != "" {
+		if err := validateIDFormat(note.IDFormat); err != nil {
+			return config, wrap(err)
+		}
+		config.Note.IDOptions.Format = note.IDFormat
+	}
 	if note.IDLength != 0 {
 		config.Note.IDOptions.Length = note.IDLength
 	}
@@ -368,12 +499,21 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 	if note.DefaultTitle != "" {
 		config.Note.DefaultTitle = note.DefaultTitle
 	}
+	if note.WeekStart != "" {
+		config.Note.WeekStart, err = dateutil.WeekdayFromString(note.WeekStart)
+		if err != nil {
+			return config, wrap(err)
+		}
+	}
 	for _, v := range note.Exclude {
 		config.Note.Exclude = append(config.Note.Exclude, v)
 	}
 	for _, v := range note.Ignore {
 		config.Note.Exclude = append(config.Note.Exclude, v)
 	}
+	if note.Encrypt != nil {
+		config.Note.Encrypt = *note.Encrypt
+	}
 	if tomlConf.Extra != nil {
 		for k, v := range tomlConf.Extra {
 			config.Extra[k] = v
@@ -387,7 +527,10 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 			parent = config.RootGroupConfig()
 		}
 
-		config.Groups[name] = parent.merge(dirTOML, name)
+		config.Groups[name], err = parent.merge(dirTOML, name)
+		if err != nil {
+			return config, wrap(err)
+		}
 	}
 
 	// Format
@@ -439,6 +582,80 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 	if tool.FzfBindNew != nil {
 		config.Tool.FzfBindNew = opt.NewStringWithPtr(tool.FzfBindNew)
 	}
+	if tool.FzfActions != nil {
+		// The map might be shared with the parent config.
+		actions := map[string]string{}
+		for action, keys := range config.Tool.FzfActions {
+			actions[action] = keys
+		}
+		for action, keys := range tool.FzfActions {
+			actions[action] = keys
+		}
+		config.Tool.FzfActions = actions
+	}
+
+	// Hooks
+	hooks := tomlConf.Hooks
+	if hooks.PreNew != nil {
+		config.Hooks.PreNew = *hooks.PreNew
+	}
+	if hooks.PostNew != nil {
+		config.Hooks.PostNew = *hooks.PostNew
+	}
+	if hooks.PostIndex != nil {
+		config.Hooks.PostIndex = *hooks.PostIndex
+	}
+	if hooks.PreEdit != nil {
+		config.Hooks.PreEdit = *hooks.PreEdit
+	}
+	if hooks.PostEdit != nil {
+		config.Hooks.PostEdit = *hooks.PostEdit
+	}
+
+	// Git
+	if tomlConf.Git.Dates != nil {
+		config.Git.Dates = *tomlConf.Git.Dates
+	}
+
+	// Lint
+	lint := tomlConf.Lint
+	if lint.MissingTitle != nil {
+		config.Lint.MissingTitle, err = lspDiagnosticSeverityFromString(*lint.MissingTitle)
+		if err != nil {
+			return config, wrap(err)
+		}
+	}
+	if lint.HeadingHierarchy != nil {
+		config.Lint.HeadingHierarchy, err = lspDiagnosticSeverityFromString(*lint.HeadingHierarchy)
+		if err != nil {
+			return config, wrap(err)
+		}
+	}
+	if lint.TrailingWhitespace != nil {
+		config.Lint.TrailingWhitespace, err = lspDiagnosticSeverityFromString(*lint.TrailingWhitespace)
+		if err != nil {
+			return config, wrap(err)
+		}
+	}
+
+	// Encryption
+	encryption := tomlConf.Encryption
+	if encryption.Encrypt != nil {
+		config.Encryption.Encrypt = *encryption.Encrypt
+	}
+	if encryption.Decrypt != nil {
+		config.Encryption.Decrypt = *encryption.Decrypt
+	}
+	if encryption.Index != nil {
+		config.Encryption.Index = []EncryptedNoteField{}
+		for _, field := range encryption.Index {
+			f, err := EncryptedNoteFieldFromString(field)
+			if err != nil {
+				return config, wrap(err)
+			}
+			config.Encryption.Index = append(config.Encryption.Index, f)
+		}
+	}
 
 	// LSP completion
 	lspCompl := tomlConf.LSP.Completion
@@ -498,10 +715,19 @@ func ParseConfig(content []byte, path string, parentConfig Config, isGlobal bool
 		}
 	}
 
+	// Plugins
+	for name, path := range tomlConf.Plugins {
+		expanded, err := paths.ExpandPath(path)
+		if err != nil {
+			return config, wrap(err)
+		}
+		config.Plugins[name] = expanded
+	}
+
 	return config, nil
 }
 
-func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
+func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) (GroupConfig, error) {
 	res := c.Clone()
 
 	if tomlConf.Paths != nil {
@@ -524,6 +750,19 @@ func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
 	if note.Template != "" {
 		res.Note.BodyTemplatePath = opt.NewNotEmptyString(note.Template)
 	}
+	if note.IDStrategy != "" {
+		strategy, err := IDStrategyFromString(note.IDStrategy)
+		if err != nil {
+			return res, errors.Wrapf(err, "group %s", name)
+		}
+		res.Note.IDOptions.Strategy = strategy
+	}
+	if note.IDFormat != "" {
+		if err := validateIDFormat(note.IDFormat); err != nil {
+			return res, errors.Wrapf(err, "group %s", name)
+		}
+		res.Note.IDOptions.Format = note.IDFormat
+	}
 	if note.IDLength != 0 {
 		res.Note.IDOptions.Length = note.IDLength
 	}
@@ -539,32 +778,48 @@ func (c GroupConfig) merge(tomlConf tomlGroupConfig, name string) GroupConfig {
 	if note.DefaultTitle != "" {
 		res.Note.DefaultTitle = note.DefaultTitle
 	}
+	if note.WeekStart != "" {
+		weekStart, err := dateutil.WeekdayFromString(note.WeekStart)
+		if err != nil {
+			return res, errors.Wrapf(err, "group %s", name)
+		}
+		res.Note.WeekStart = weekStart
+	}
 	for _, v := range note.Exclude {
 		res.Note.Exclude = append(res.Note.Exclude, v)
 	}
 	for _, v := range note.Ignore {
 		res.Note.Exclude = append(res.Note.Exclude, v)
 	}
+	if note.Encrypt != nil {
+		res.Note.Encrypt = *note.Encrypt
+	}
 	if tomlConf.Extra != nil {
 		for k, v := range tomlConf.Extra {
 			res.Extra[k] = v
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 // tomlConfig holds the TOML representation of Config
 type tomlConfig struct {
-	Notebook tomlNotebookConfig
-	Note     tomlNoteConfig
-	Groups   map[string]tomlGroupConfig `toml:"group"`
-	Format   tomlFormatConfig
-	Tool     tomlToolConfig
-	LSP      tomlLSPConfig
-	Extra    map[string]string
-	Filters  map[string]string `toml:"filter"`
-	Aliases  map[string]string `toml:"alias"`
+	Notebook   tomlNotebookConfig
+	Notebooks  map[string]string
+	Note       tomlNoteConfig
+	Groups     map[string]tomlGroupConfig `toml:"group"`
+	Format     tomlFormatConfig
+	Tool       tomlToolConfig
+	Hooks      tomlHooksConfig
+	LSP        tomlLSPConfig
+	Git        tomlGitConfig
+	Lint       tomlLintConfig
+	Encryption tomlEncryptionConfig
+	Extra      map[string]string
+	Filters    map[string]string `toml:"filter"`
+	Aliases    map[string]string `toml:"alias"`
+	Plugins    map[string]string
 }
 
 type tomlNotebookConfig struct {
@@ -577,11 +832,15 @@ type tomlNoteConfig struct {
 	Template     string
 	Lang         string   `toml:"language"`
 	DefaultTitle string   `toml:"default-title"`
+	WeekStart    string   `toml:"week-start"`
+	IDStrategy   string   `toml:"id-strategy"`
+	IDFormat     string   `toml:"id-format"`
 	IDCharset    string   `toml:"id-charset"`
 	IDLength     int      `toml:"id-length"`
 	IDCase       string   `toml:"id-case"`
 	Exclude      []string `toml:"exclude"`
 	Ignore       []string `toml:"ignore"` // Legacy alias to `exclude`
+	Encrypt      *bool    `toml:"encrypt"`
 }
 
 type tomlGroupConfig struct {
@@ -607,10 +866,35 @@ type tomlToolConfig struct {
 	Editor     *string
 	Shell      *string
 	Pager      *string
-	FzfPreview *string `toml:"fzf-preview"`
-	FzfLine    *string `toml:"fzf-line"`
-	FzfOptions *string `toml:"fzf-options"`
-	FzfBindNew *string `toml:"fzf-bind-new"`
+	FzfPreview *string           `toml:"fzf-preview"`
+	FzfLine    *string           `toml:"fzf-line"`
+	FzfOptions *string           `toml:"fzf-options"`
+	FzfBindNew *string           `toml:"fzf-bind-new"`
+	FzfActions map[string]string `toml:"fzf-actions"`
+}
+
+type tomlHooksConfig struct {
+	PreNew    *string `toml:"pre-new"`
+	PostNew   *string `toml:"post-new"`
+	PostIndex *string `toml:"post-index"`
+	PreEdit   *string `toml:"pre-edit"`
+	PostEdit  *string `toml:"post-edit"`
+}
+
+type tomlGitConfig struct {
+	Dates *bool
+}
+
+type tomlLintConfig struct {
+	MissingTitle       *string `toml:"missing-title"`
+	HeadingHierarchy   *string `toml:"heading-hierarchy"`
+	TrailingWhitespace *string `toml:"trailing-whitespace"`
+}
+
+type tomlEncryptionConfig struct {
+	Encrypt *string
+	Decrypt *string
+	Index   []string
 }
 
 type tomlLSPConfig struct {
@@ -648,6 +932,13 @@ func charsetFromString(charset string) Charset {
 	}
 }
 
+// validateIDFormat checks that the given strftime format used to generate
+// timestamp IDs is valid.
+func validateIDFormat(format string) error {
+	_, err := strftime.New(format, strftime.WithUnixSeconds('s'))
+	return errors.Wrapf(err, "invalid id-format %s", format)
+}
+
 func caseFromString(c string) Case {
 	switch c {
 	case "lower":
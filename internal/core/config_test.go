@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/zk-org/zk/internal/util/opt"
@@ -17,16 +18,19 @@ func TestParseDefaultConfig(t *testing.T) {
 		Notebook: NotebookConfig{
 			Dir: opt.NullString,
 		},
+		Notebooks: make(map[string]string),
 		Note: NoteConfig{
 			FilenameTemplate: "{{id}}",
 			Extension:        "md",
 			BodyTemplatePath: opt.NullString,
 			IDOptions: IDOptions{
-				Length:  4,
-				Charset: CharsetAlphanum,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Length:   4,
+				Charset:  CharsetAlphanum,
+				Case:     CaseLower,
 			},
 			DefaultTitle: "Untitled",
+			WeekStart:    time.Monday,
 			Lang:         "en",
 			Exclude:      []string{},
 		},
@@ -55,8 +59,17 @@ func TestParseDefaultConfig(t *testing.T) {
 				MissingBacklink: MissingBacklinkConfig{},
 			},
 		},
+		Lint: LintConfig{
+			MissingTitle:       LSPDiagnosticWarning,
+			HeadingHierarchy:   LSPDiagnosticWarning,
+			TrailingWhitespace: LSPDiagnosticHint,
+		},
+		Encryption: EncryptionConfig{
+			Index: []EncryptedNoteField{EncryptedNoteTitle, EncryptedNoteTags},
+		},
 		Filters: make(map[string]string),
 		Aliases: make(map[string]string),
+		Plugins: make(map[string]string),
 		Extra:   make(map[string]string),
 	})
 }
@@ -73,12 +86,16 @@ func TestParseComplete(t *testing.T) {
 		[notebook]
 		dir = "~/notebook"
 
+		[notebooks]
+		work = "/notes/work"
+
 		[note]
 		filename = "{{id}}.note"
 		extension = "txt"
 		template = "default.note"
 		language = "fr"
 		default-title = "Sans titre"
+		week-start = "sunday"
 		id-charset = "alphanum"
 		id-length = 4
 		id-case = "lower"
@@ -101,6 +118,10 @@ func TestParseComplete(t *testing.T) {
 		fzf-options = "--border --height 40%"
 		fzf-bind-new = "Ctrl-C"
 
+		[tool.fzf-actions]
+		link = "alt-l"
+		delete = "alt-d"
+
 		[extra]
 		hello = "world"
 		salut = "le monde"
@@ -113,6 +134,9 @@ func TestParseComplete(t *testing.T) {
 		ls = "zk list $@"
 		ed = "zk edit $@"
 
+		[plugins]
+		git = "/usr/local/bin/zk-git"
+
 		[group.log]
 		paths = ["journal/daily", "journal/weekly"]
 
@@ -122,10 +146,12 @@ func TestParseComplete(t *testing.T) {
 		template = "log.md"
 		language = "de"
 		default-title = "Ohne Titel"
+		week-start = "Saturday"
 		id-charset = "letters"
 		id-length = 8
 		id-case = "mixed"
 		exclude = ["new-ignored"]
+		encrypt = true
 		
 		[group.log.extra]
 		log-ext = "value"
@@ -145,6 +171,18 @@ func TestParseComplete(t *testing.T) {
 		[lsp.diagnostics]
 		wiki-title = "hint"
 		dead-link = "none"
+
+		[git]
+		dates = true
+
+		[lint]
+		missing-title = "error"
+		trailing-whitespace = "none"
+
+		[encryption]
+		encrypt = "age -r age1xyz"
+		decrypt = "age -d -i key.txt"
+		index = ["title", "metadata"]
 	`), ".zk/config.toml", NewDefaultConfig(), true)
 
 	assert.Nil(t, err)
@@ -152,17 +190,22 @@ func TestParseComplete(t *testing.T) {
 		Notebook: NotebookConfig{
 			Dir: opt.NewString("~/notebook"),
 		},
+		Notebooks: map[string]string{
+			"work": "/notes/work",
+		},
 		Note: NoteConfig{
 			FilenameTemplate: "{{id}}.note",
 			Extension:        "txt",
 			BodyTemplatePath: opt.NewString("default.note"),
 			IDOptions: IDOptions{
-				Length:  4,
-				Charset: CharsetAlphanum,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Length:   4,
+				Charset:  CharsetAlphanum,
+				Case:     CaseLower,
 			},
 			Lang:         "fr",
 			DefaultTitle: "Sans titre",
+			WeekStart:    time.Sunday,
 			Exclude:      []string{"ignored", ".git"},
 		},
 		Groups: map[string]GroupConfig{
@@ -173,13 +216,16 @@ func TestParseComplete(t *testing.T) {
 					Extension:        "note",
 					BodyTemplatePath: opt.NewString("log.md"),
 					IDOptions: IDOptions{
-						Length:  8,
-						Charset: CharsetLetters,
-						Case:    CaseMixed,
+						Strategy: IDStrategyRandom,
+						Length:   8,
+						Charset:  CharsetLetters,
+						Case:     CaseMixed,
 					},
 					Lang:         "de",
 					DefaultTitle: "Ohne Titel",
+					WeekStart:    time.Saturday,
 					Exclude:      []string{"ignored", ".git", "new-ignored"},
+					Encrypt:      true,
 				},
 				Extra: map[string]string{
 					"hello":   "world",
@@ -194,12 +240,14 @@ func TestParseComplete(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("default.note"),
 					IDOptions: IDOptions{
-						Length:  4,
-						Charset: CharsetAlphanum,
-						Case:    CaseLower,
+						Strategy: IDStrategyRandom,
+						Length:   4,
+						Charset:  CharsetAlphanum,
+						Case:     CaseLower,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
+					WeekStart:    time.Sunday,
 					Exclude:      []string{"ignored", ".git"},
 				},
 				Extra: map[string]string{
@@ -214,12 +262,14 @@ func TestParseComplete(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("default.note"),
 					IDOptions: IDOptions{
-						Length:  4,
-						Charset: CharsetAlphanum,
-						Case:    CaseLower,
+						Strategy: IDStrategyRandom,
+						Length:   4,
+						Charset:  CharsetAlphanum,
+						Case:     CaseLower,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
+					WeekStart:    time.Sunday,
 					Exclude:      []string{"ignored", ".git"},
 				},
 				Extra: map[string]string{
@@ -246,6 +296,10 @@ func TestParseComplete(t *testing.T) {
 			FzfLine:    opt.NewString("{{title}}"),
 			FzfOptions: opt.NewString("--border --height 40%"),
 			FzfBindNew: opt.NewString("Ctrl-C"),
+			FzfActions: map[string]string{
+				"link":   "alt-l",
+				"delete": "alt-d",
+			},
 		},
 		LSP: LSPConfig{
 			Completion: LSPCompletionConfig{
@@ -262,6 +316,19 @@ func TestParseComplete(t *testing.T) {
 				MissingBacklink: MissingBacklinkConfig{},
 			},
 		},
+		Git: GitConfig{
+			Dates: true,
+		},
+		Lint: LintConfig{
+			MissingTitle:       LSPDiagnosticError,
+			HeadingHierarchy:   LSPDiagnosticWarning,
+			TrailingWhitespace: LSPDiagnosticNone,
+		},
+		Encryption: EncryptionConfig{
+			Encrypt: "age -r age1xyz",
+			Decrypt: "age -d -i key.txt",
+			Index:   []EncryptedNoteField{EncryptedNoteTitle, EncryptedNoteMetadata},
+		},
 		Filters: map[string]string{
 			"recents": "--created-after '2 weeks ago'",
 			"journal": "journal --sort created",
@@ -270,6 +337,9 @@ func TestParseComplete(t *testing.T) {
 			"ls": "zk list $@",
 			"ed": "zk edit $@",
 		},
+		Plugins: map[string]string{
+			"git": "/usr/local/bin/zk-git",
+		},
 		Extra: map[string]string{
 			"hello": "world",
 			"salut": "le monde",
@@ -359,17 +429,20 @@ func TestParseMergesGroupConfig(t *testing.T) {
 
 	assert.Nil(t, err)
 	assert.Equal(t, conf, Config{
+		Notebooks: make(map[string]string),
 		Note: NoteConfig{
 			FilenameTemplate: "root-filename",
 			Extension:        "txt",
 			BodyTemplatePath: opt.NewString("root-template"),
 			IDOptions: IDOptions{
-				Length:  42,
-				Charset: CharsetLetters,
-				Case:    CaseUpper,
+				Strategy: IDStrategyRandom,
+				Length:   42,
+				Charset:  CharsetLetters,
+				Case:     CaseUpper,
 			},
 			Lang:         "fr",
 			DefaultTitle: "Sans titre",
+			WeekStart:    time.Monday,
 			Exclude:      []string{"ignored", ".git"},
 		},
 		Groups: map[string]GroupConfig{
@@ -380,12 +453,14 @@ func TestParseMergesGroupConfig(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("log-template"),
 					IDOptions: IDOptions{
-						Length:  8,
-						Charset: CharsetNumbers,
-						Case:    CaseMixed,
+						Strategy: IDStrategyRandom,
+						Length:   8,
+						Charset:  CharsetNumbers,
+						Case:     CaseMixed,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
+					WeekStart:    time.Monday,
 					Exclude:      []string{"ignored", ".git"},
 				},
 				Extra: map[string]string{
@@ -401,12 +476,14 @@ func TestParseMergesGroupConfig(t *testing.T) {
 					Extension:        "txt",
 					BodyTemplatePath: opt.NewString("root-template"),
 					IDOptions: IDOptions{
-						Length:  42,
-						Charset: CharsetLetters,
-						Case:    CaseUpper,
+						Strategy: IDStrategyRandom,
+						Length:   42,
+						Charset:  CharsetLetters,
+						Case:     CaseUpper,
 					},
 					Lang:         "fr",
 					DefaultTitle: "Sans titre",
+					WeekStart:    time.Monday,
 					Exclude:      []string{"ignored", ".git"},
 				},
 				Extra: map[string]string{
@@ -439,8 +516,17 @@ func TestParseMergesGroupConfig(t *testing.T) {
 				MissingBacklink: MissingBacklinkConfig{},
 			},
 		},
+		Lint: LintConfig{
+			MissingTitle:       LSPDiagnosticWarning,
+			HeadingHierarchy:   LSPDiagnosticWarning,
+			TrailingWhitespace: LSPDiagnosticHint,
+		},
+		Encryption: EncryptionConfig{
+			Index: []EncryptedNoteField{EncryptedNoteTitle, EncryptedNoteTags},
+		},
 		Filters: make(map[string]string),
 		Aliases: make(map[string]string),
+		Plugins: make(map[string]string),
 		Extra: map[string]string{
 			"hello": "world",
 			"salut": "le monde",
@@ -480,6 +566,76 @@ func TestParseNotebook(t *testing.T) {
 	assert.Err(t, err, "notebook.dir should not be set on local configuration")
 }
 
+func TestParseNotebooks(t *testing.T) {
+	toml := `
+			[notebooks]
+			work = "/home/user/work"
+			personal = "/home/user/personal"
+		`
+	// Should parse notebooks if isGlobal == true
+	conf, err := ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Notebooks, map[string]string{
+		"work":     "/home/user/work",
+		"personal": "/home/user/personal",
+	})
+
+	dir, ok := conf.NotebookDir("work")
+	assert.True(t, ok)
+	assert.Equal(t, dir, "/home/user/work")
+	_, ok = conf.NotebookDir("unknown")
+	assert.False(t, ok)
+
+	// Should not parse notebooks if isGlobal == false
+	_, err = ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "notebooks should not be set on local configuration")
+}
+
+func TestParseHooks(t *testing.T) {
+	global, err := ParseConfig([]byte(`
+			[hooks]
+			pre-new = "stamp-metadata"
+			post-new = "git add . && git commit -m 'New note'"
+			post-index = "sync-notes"
+		`), ".zk/config.toml", NewDefaultConfig(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, global.Hooks, HooksConfig{
+		PreNew:    "stamp-metadata",
+		PostNew:   "git add . && git commit -m 'New note'",
+		PostIndex: "sync-notes",
+	})
+	assert.Equal(t, global.Hooks.Command(HookPostIndex), "sync-notes")
+	assert.Equal(t, global.Hooks.Command(HookPreEdit), "")
+
+	// A local config can override or disable the global hooks.
+	local, err := ParseConfig([]byte(`
+			[hooks]
+			post-new = ""
+			post-edit = "git commit -am 'Edit note'"
+		`), ".zk/config.toml", global, false)
+	assert.Nil(t, err)
+	assert.Equal(t, local.Hooks, HooksConfig{
+		PreNew:    "stamp-metadata",
+		PostIndex: "sync-notes",
+		PostEdit:  "git commit -am 'Edit note'",
+	})
+}
+
+func TestParseEncryptionIndex(t *testing.T) {
+	conf, err := ParseConfig([]byte(`
+			[encryption]
+			index = []
+		`), ".zk/config.toml", NewDefaultConfig(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Encryption.Index, []EncryptedNoteField{})
+
+	_, err = ParseConfig([]byte(`
+			[encryption]
+			index = ["title", "content"]
+		`), ".zk/config.toml", NewDefaultConfig(), true)
+	assert.Err(t, err, "content: unknown encrypted note property, expected one of: title, tags, links, metadata, body")
+}
+
 func TestParseIDCharset(t *testing.T) {
 	test := func(charset string, expected Charset) {
 		toml := fmt.Sprintf(`
@@ -520,6 +676,52 @@ func TestParseIDCase(t *testing.T) {
 	test("unknown", CaseLower)
 }
 
+func TestParseIDStrategy(t *testing.T) {
+	test := func(strategy string, expected IDStrategy) {
+		toml := fmt.Sprintf(`
+			[note]
+			id-strategy = "%v"
+		`, strategy)
+		conf, err := ParseConfig([]byte(toml), ".zk/config.toml", NewDefaultConfig(), false)
+		assert.Nil(t, err)
+		assert.Equal(t, conf.Note.IDOptions.Strategy, expected)
+	}
+
+	test("random", IDStrategyRandom)
+	test("ulid", IDStrategyULID)
+	test("uuid", IDStrategyUUID)
+	test("timestamp", IDStrategyTimestamp)
+	test("sequential", IDStrategySequential)
+	test("folgezettel", IDStrategyFolgezettel)
+
+	_, err := ParseConfig([]byte(`
+		[group.log.note]
+		id-strategy = "unknown"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "unknown: unknown ID strategy")
+
+	_, err = ParseConfig([]byte(`
+		[note]
+		id-format = "%Y%Q"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "invalid id-format %Y%Q")
+}
+
+func TestParseWeekStart(t *testing.T) {
+	conf, err := ParseConfig([]byte(`
+		[note]
+		week-start = "sunday"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, conf.Note.WeekStart, time.Sunday)
+
+	_, err = ParseConfig([]byte(`
+		[note]
+		week-start = "someday"
+	`), ".zk/config.toml", NewDefaultConfig(), false)
+	assert.Err(t, err, "someday: unknown day of the week")
+}
+
 // If link-encode-path is not set explicitly, it defaults to true for
 // "markdown" format and false for anything else.
 func TestParseMarkdownLinkEncodePath(t *testing.T) {
@@ -638,12 +840,14 @@ func TestGroupConfigClone(t *testing.T) {
 			Extension:        "md",
 			BodyTemplatePath: opt.NewString("default.note"),
 			IDOptions: IDOptions{
-				Length:  4,
-				Charset: CharsetAlphanum,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Length:   4,
+				Charset:  CharsetAlphanum,
+				Case:     CaseLower,
 			},
 			Lang:         "fr",
 			DefaultTitle: "Sans titre",
+			WeekStart:    time.Monday,
 			Exclude:      []string{"ignored", ".git"},
 		},
 		Extra: map[string]string{
@@ -675,12 +879,14 @@ func TestGroupConfigClone(t *testing.T) {
 			Extension:        "md",
 			BodyTemplatePath: opt.NewString("default.note"),
 			IDOptions: IDOptions{
-				Length:  4,
-				Charset: CharsetAlphanum,
-				Case:    CaseLower,
+				Strategy: IDStrategyRandom,
+				Length:   4,
+				Charset:  CharsetAlphanum,
+				Case:     CaseLower,
 			},
 			Lang:         "fr",
 			DefaultTitle: "Sans titre",
+			WeekStart:    time.Monday,
 			Exclude:      []string{"ignored", ".git"},
 		},
 		Extra: map[string]string{
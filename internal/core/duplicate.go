@@ -0,0 +1,692 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/paths"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+	"gopkg.in/yaml.v2"
+)
+
+// DuplicateKind is the criterion used to detect duplicate notes.
+type DuplicateKind string
+
+const (
+	// DuplicateExact are notes with the same file content.
+	DuplicateExact DuplicateKind = "exact"
+	// DuplicateSimilar are notes with nearly the same body.
+	DuplicateSimilar DuplicateKind = "similar"
+	// DuplicateTitle are notes with the same title.
+	DuplicateTitle DuplicateKind = "title"
+)
+
+// DuplicateKinds lists the available duplicate kinds, in the order they are
+// reported.
+var DuplicateKinds = []DuplicateKind{DuplicateExact, DuplicateSimilar, DuplicateTitle}
+
+// DuplicateGroup is a set of notes considered duplicates of each other.
+type DuplicateGroup struct {
+	Kind DuplicateKind
+	// Lowest similarity between two notes of the group, from 0 to 1. It is
+	// only computed for exact and similar duplicates.
+	Similarity float64
+	// Notes of the group, sorted by creation date.
+	Notes []ContextualNote
+}
+
+// DuplicateFindOpts holds the options used to find duplicate notes.
+type DuplicateFindOpts struct {
+	// Kinds of duplicates to find, all of them when empty.
+	Kinds []DuplicateKind
+	// Minimum similarity between the bodies of two notes to consider them
+	// near-duplicates, from 0 to 1.
+	Threshold float64
+}
+
+// DefaultDuplicateThreshold is the default minimum similarity of
+// near-duplicate notes.
+const DefaultDuplicateThreshold = 0.8
+
+// FindDuplicates returns the groups of duplicate notes in the notebook.
+func (n *Notebook) FindDuplicates(opts DuplicateFindOpts) ([]DuplicateGroup, error) {
+	notes, err := n.index.Find(NoteFindOpts{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find duplicates")
+	}
+
+	kinds := opts.Kinds
+	if len(kinds) == 0 {
+		kinds = DuplicateKinds
+	}
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+
+	groups := []DuplicateGroup{}
+	for _, kind := range kinds {
+		switch kind {
+		case DuplicateExact:
+			groups = append(groups, groupNotesBy(kind, notes, func(note ContextualNote) string {
+				return note.Checksum
+			})...)
+		case DuplicateTitle:
+			groups = append(groups, groupNotesBy(kind, notes, func(note ContextualNote) string {
+				return strings.ToLower(strings.TrimSpace(note.Title))
+			})...)
+		case DuplicateSimilar:
+			groups = append(groups, findSimilarNotes(notes, threshold)...)
+		default:
+			return nil, fmt.Errorf("%s: unknown duplicate kind, expected one of: exact, similar or title", kind)
+		}
+	}
+	return groups, nil
+}
+
+// groupNotesBy groups the notes sharing the same non-empty key.
+func groupNotesBy(kind DuplicateKind, notes []ContextualNote, key func(note ContextualNote) string) []DuplicateGroup {
+	byKey := map[string][]ContextualNote{}
+	keys := []string{}
+	for _, note := range notes {
+		k := key(note)
+		if k == "" {
+			continue
+		}
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], note)
+	}
+
+	groups := []DuplicateGroup{}
+	for _, k := range keys {
+		if len(byKey[k]) < 2 {
+			continue
+		}
+		group := DuplicateGroup{Kind: kind, Notes: byKey[k]}
+		if kind == DuplicateExact {
+			group.Similarity = 1
+		}
+		groups = append(groups, group)
+	}
+	return sortDuplicateGroups(groups)
+}
+
+const (
+	// Number of words in a shingle.
+	shingleSize = 3
+	// Number of hash functions of the MinHash signatures.
+	minHashSize = 64
+	// Number of rows in each band of the MinHash signatures, used to find
+	// candidate pairs of similar notes.
+	minHashBandRows = 4
+)
+
+// findSimilarNotes groups the notes whose bodies have a Jaccard similarity
+// above the threshold, estimated from their shingles.
+//
+// The candidate pairs are found with locality-sensitive hashing of the
+// MinHash signatures of the notes, to avoid comparing every pair.
+func findSimilarNotes(notes []ContextualNote, threshold float64) []DuplicateGroup {
+	shingles := make([]map[uint64]bool, len(notes))
+	buckets := map[uint64][]int{}
+	for i, note := range notes {
+		shingles[i] = shingle(note.Body)
+		if len(shingles[i]) == 0 {
+			continue
+		}
+		signature := minHash(shingles[i])
+		for band := 0; band < minHashSize/minHashBandRows; band++ {
+			h := fnv.New64a()
+			binary.Write(h, binary.LittleEndian, uint64(band))
+			binary.Write(h, binary.LittleEndian, signature[band*minHashBandRows:(band+1)*minHashBandRows])
+			key := h.Sum64()
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	// Union-find of the similar notes, keeping the lowest similarity of each
+	// group.
+	parents := make([]int, len(notes))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	similarities := map[int]float64{}
+	compared := map[[2]int]bool{}
+
+	for _, bucket := range buckets {
+		for a := 0; a < len(bucket); a++ {
+			for b := a + 1; b < len(bucket); b++ {
+				i, j := bucket[a], bucket[b]
+				if compared[[2]int{i, j}] {
+					continue
+				}
+				compared[[2]int{i, j}] = true
+
+				// Exact duplicates are reported separately.
+				if notes[i].Checksum == notes[j].Checksum {
+					continue
+				}
+				similarity := jaccard(shingles[i], shingles[j])
+				if similarity < threshold {
+					continue
+				}
+
+				ri, rj := find(i), find(j)
+				lowest := similarity
+				for _, root := range []int{ri, rj} {
+					if s, ok := similarities[root]; ok && s < lowest {
+						lowest = s
+					}
+				}
+				delete(similarities, ri)
+				delete(similarities, rj)
+				parents[ri] = rj
+				similarities[rj] = lowest
+			}
+		}
+	}
+
+	members := map[int][]ContextualNote{}
+	for i, note := range notes {
+		root := find(i)
+		if _, ok := similarities[root]; ok {
+			members[root] = append(members[root], note)
+		}
+	}
+	groups := []DuplicateGroup{}
+	for root, notes := range members {
+		groups = append(groups, DuplicateGroup{
+			Kind:       DuplicateSimilar,
+			Similarity: math.Floor(similarities[root]*100) / 100,
+			Notes:      notes,
+		})
+	}
+	return sortDuplicateGroups(groups)
+}
+
+// sortDuplicateGroups sorts the notes of each group by creation date, and the
+// groups by the path of their first note.
+func sortDuplicateGroups(groups []DuplicateGroup) []DuplicateGroup {
+	for _, group := range groups {
+		sort.SliceStable(group.Notes, func(i, j int) bool {
+			a, b := group.Notes[i], group.Notes[j]
+			if !a.Created.Equal(b.Created) {
+				return a.Created.Before(b.Created)
+			}
+			return a.Path < b.Path
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Notes[0].Path < groups[j].Notes[0].Path
+	})
+	return groups
+}
+
+// shingle returns the hashes of the sequences of shingleSize consecutive
+// words in the given text.
+func shingle(text string) map[uint64]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	shingles := map[uint64]bool{}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+shingleSize], " ")))
+		shingles[h.Sum64()] = true
+	}
+	return shingles
+}
+
+// minHash computes the MinHash signature of a set of shingles.
+func minHash(shingles map[uint64]bool) []uint64 {
+	signature := make([]uint64, minHashSize)
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+	for shingle := range shingles {
+		for i := range signature {
+			if h := mixHash(shingle ^ mixHash(uint64(i+1))); h < signature[i] {
+				signature[i] = h
+			}
+		}
+	}
+	return signature
+}
+
+// mixHash is the SplitMix64 finalizer, used to derive independent hash
+// functions.
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// jaccard returns the Jaccard similarity of two sets of shingles.
+func jaccard(a, b map[uint64]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for shingle := range a {
+		if b[shingle] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// MergeNotes merges the notes at the given paths, relative to the notebook
+// root, into the survivor note. The bodies of the other notes are appended
+// to the survivor with their tags and frontmatter keys, the links targeting
+// them are redirected to the survivor and their files are removed. The links
+// between the merged notes are unwrapped, to avoid self-links. The merge
+// is refused when the frontmatter values of the notes conflict.
+//
+// It returns the paths of the updated notes, including the survivor.
+func (n *Notebook) MergeNotes(survivorPath string, paths []string) ([]string, error) {
+	wrap := errors.Wrapperf("failed to merge notes into %s", survivorPath)
+
+	hrefs := append([]string{survivorPath}, paths...)
+	for _, href := range hrefs {
+		// The content of encrypted notes is not indexed.
+		if n.IsEncrypted(filepath.Join(n.Path, href)) {
+			return nil, wrap(fmt.Errorf("%s: cannot merge encrypted notes", href))
+		}
+	}
+	found, err := n.index.Find(NoteFindOpts{IncludeHrefs: hrefs})
+	if err != nil {
+		return nil, wrap(err)
+	}
+	notes := map[string]ContextualNote{}
+	for _, note := range found {
+		notes[note.Path] = note
+	}
+	survivor, ok := notes[survivorPath]
+	if !ok {
+		return nil, wrap(fmt.Errorf("%s: note not found", survivorPath))
+	}
+	merged := []ContextualNote{}
+	for _, path := range paths {
+		note, ok := notes[path]
+		if !ok {
+			return nil, wrap(fmt.Errorf("%s: note not found", path))
+		}
+		if path != survivorPath {
+			merged = append(merged, note)
+		}
+	}
+	if len(merged) == 0 {
+		return []string{}, nil
+	}
+
+	metadata, err := mergedMetadata(survivor, merged)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	// Find the links targeting the merged notes.
+	mergedPaths := []string{}
+	ids := []NoteID{survivor.ID}
+	for _, note := range merged {
+		mergedPaths = append(mergedPaths, note.Path)
+		ids = append(ids, note.ID)
+	}
+	sources, err := n.index.Find(NoteFindOpts{
+		LinkTo: &LinkFilter{Hrefs: mergedPaths},
+	})
+	if err != nil {
+		return nil, wrap(err)
+	}
+	for _, source := range sources {
+		ids = append(ids, source.ID)
+	}
+	links, err := n.index.FindLinksBetweenNotes(ids)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	// The links between the merged notes and the survivor become links of the
+	// survivor to itself, so they are unwrapped.
+	targets := append([]string{survivor.Path}, mergedPaths...)
+	isSelfLink := func(link ResolvedLink) bool {
+		return strutil.Contains(targets, link.SourcePath) && strutil.Contains(targets, link.TargetPath) &&
+			(link.SourcePath != survivor.Path || link.TargetPath != survivor.Path)
+	}
+
+	// Concatenate the bodies of the merged notes, skipping the identical ones.
+	survivorDir := filepath.Dir(survivor.Path)
+	content := strings.TrimRight(survivor.RawContent, "\n") + "\n"
+	bodies := []string{strings.TrimSpace(survivor.Body)}
+	tags := []string{}
+	for _, note := range merged {
+		tags = append(tags, note.Tags...)
+		body := strings.TrimSpace(note.Body)
+		if body == "" || strutil.Contains(bodies, body) {
+			continue
+		}
+		bodies = append(bodies, body)
+		if note.Title != "" {
+			content += "\n## " + note.Title + "\n"
+		}
+		body = rebaseHrefs(body, filepath.Dir(note.Path), survivorDir)
+		for _, link := range links {
+			if link.SourcePath == note.Path && isSelfLink(link) {
+				body, _ = unwrapNoteLinks(body, link.Link, survivorDir, noteHrefFor(link.Link, survivorDir, survivor.Path))
+			}
+		}
+		content += "\n" + body + "\n"
+	}
+
+	content, err = addFrontmatterKeys(content, metadata)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	if tags = newItems(survivor.Tags, tags); len(tags) > 0 {
+		content, err = addFrontmatterTags(content, tags)
+		if err != nil {
+			return nil, wrap(err)
+		}
+	}
+
+	contents := map[string]string{survivor.Path: content}
+	for _, source := range sources {
+		if _, ok := contents[source.Path]; !ok {
+			content := source.RawContent
+			if absPath := filepath.Join(n.Path, source.Path); n.IsEncrypted(absPath) {
+				raw, err := n.ReadNote(absPath)
+				if err != nil {
+					return nil, wrap(err)
+				}
+				content = string(raw)
+			}
+			contents[source.Path] = content
+		}
+	}
+
+	updated := []string{survivor.Path}
+	for _, link := range links {
+		if !strutil.Contains(mergedPaths, link.TargetPath) || strutil.Contains(mergedPaths, link.SourcePath) {
+			continue
+		}
+		dir := filepath.Dir(link.SourcePath)
+		newHref := noteHrefFor(link.Link, dir, survivor.Path)
+		rewrite := rewriteNoteHrefs
+		if isSelfLink(link) {
+			rewrite = unwrapNoteLinks
+		}
+		newContent, changed := rewrite(contents[link.SourcePath], link.Link, dir, newHref)
+		if changed {
+			contents[link.SourcePath] = newContent
+			if !strutil.Contains(updated, link.SourcePath) {
+				updated = append(updated, link.SourcePath)
+			}
+		}
+	}
+
+	for _, path := range updated {
+		err = n.WriteNote(filepath.Join(n.Path, path), []byte(contents[path]))
+		if err != nil {
+			return updated, wrap(err)
+		}
+	}
+	for _, path := range mergedPaths {
+		err = n.fs.Remove(filepath.Join(n.Path, path))
+		if err != nil {
+			return updated, wrap(err)
+		}
+	}
+
+	return updated, nil
+}
+
+// noteHrefRegex matches the href of wiki links, inline Markdown links and
+// link reference definitions.
+var noteHrefRegex = regexp.MustCompile(`(\[\[\s*|\]\(\s*<?|(?m:^\s*\[[^\]]+\]:\s*<?))([^\s)>\]|#]+)`)
+
+// rewriteNoteHrefs replaces the href of the given link to a note with
+// newHref, keeping any anchor, in the content of the source note located in
+// dir. The Markdown hrefs are indexed relative to the notebook root, so they
+// are resolved against dir.
+func rewriteNoteHrefs(content string, link Link, dir string, newHref string) (string, bool) {
+	isWikiLink := link.Type == LinkTypeWikiLink
+
+	changed := false
+	newContent := noteHrefRegex.ReplaceAllStringFunc(content, func(match string) string {
+		groups := noteHrefRegex.FindStringSubmatch(match)
+		prefix, oldHref := groups[1], groups[2]
+		if strings.HasPrefix(prefix, "[[") != isWikiLink {
+			return match
+		}
+		matches, escaped := matchesNoteHref(link, dir, oldHref)
+		if !matches {
+			return match
+		}
+
+		replacement := newHref
+		if escaped {
+			replacement = strings.ReplaceAll(replacement, " ", "%20")
+		}
+		if replacement == oldHref {
+			return match
+		}
+		changed = true
+		return prefix + replacement
+	})
+	return newContent, changed
+}
+
+// matchesNoteHref returns whether oldHref, found in the content of a note
+// located in dir, targets the same note as the given link. escaped is true
+// when oldHref is percent-encoded.
+func matchesNoteHref(link Link, dir string, oldHref string) (matches bool, escaped bool) {
+	href := strings.SplitN(link.Href, "#", 2)[0]
+	oldHref = strings.SplitN(oldHref, "#", 2)[0]
+	if href == "" {
+		return false, false
+	}
+	if link.Type == LinkTypeWikiLink {
+		return oldHref == href, false
+	}
+
+	unescaped, err := url.PathUnescape(oldHref)
+	if err != nil {
+		unescaped = oldHref
+	}
+	resolves := func(h string) bool {
+		return filepath.Clean(filepath.Join(dir, h)) == filepath.Clean(href)
+	}
+	if strutil.IsURL(unescaped) || !(resolves(oldHref) || resolves(unescaped)) {
+		return false, false
+	}
+	return true, unescaped != oldHref
+}
+
+// wikiLinkRegex matches a wiki link with its href and optional label.
+var wikiLinkRegex = regexp.MustCompile(`\[\[\s*([^\]|]+?)\s*(?:\|\s*([^\]]*?)\s*)?\]\]`)
+
+// inlineLinkRegex matches an inline Markdown link or image with its label and
+// href.
+var inlineLinkRegex = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*<?([^\s)>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+// unwrapNoteLinks replaces the given link to a note by its label, in the
+// content of the source note located in dir. It is used for the links which
+// would target their own note. The link reference definitions are redirected
+// to newHref instead.
+func unwrapNoteLinks(content string, link Link, dir string, newHref string) (string, bool) {
+	changed := false
+	unwrap := func(regex *regexp.Regexp, label func(groups []string) (string, bool)) {
+		content = regex.ReplaceAllStringFunc(content, func(match string) string {
+			text, ok := label(regex.FindStringSubmatch(match))
+			if !ok {
+				return match
+			}
+			changed = true
+			return text
+		})
+	}
+
+	if link.Type == LinkTypeWikiLink {
+		unwrap(wikiLinkRegex, func(groups []string) (string, bool) {
+			if matches, _ := matchesNoteHref(link, dir, groups[1]); !matches {
+				return "", false
+			}
+			if groups[2] != "" {
+				return groups[2], true
+			}
+			return strings.SplitN(groups[1], "#", 2)[0], true
+		})
+	} else {
+		unwrap(inlineLinkRegex, func(groups []string) (string, bool) {
+			if groups[1] == "!" {
+				return "", false
+			}
+			matches, _ := matchesNoteHref(link, dir, groups[3])
+			return groups[2], matches
+		})
+	}
+
+	content, rewritten := rewriteNoteHrefs(content, link, dir, newHref)
+	return content, changed || rewritten
+}
+
+// rebaseHrefs rewrites the relative hrefs of the Markdown links and images
+// found in the body of a note located in dir, to be relative to newDir
+// instead. All paths are relative to the notebook root.
+func rebaseHrefs(body string, dir string, newDir string) string {
+	if dir == newDir {
+		return body
+	}
+	return assetHrefRegex.ReplaceAllStringFunc(body, func(match string) string {
+		groups := assetHrefRegex.FindStringSubmatch(match)
+		prefix, href := groups[1], groups[2]
+		if strutil.IsURL(href) || strings.HasPrefix(href, "/") || strings.HasPrefix(href, "#") {
+			return match
+		}
+
+		path, suffix := href, ""
+		if i := strings.IndexAny(href, "#?"); i >= 0 {
+			path, suffix = href[:i], href[i:]
+		}
+		unescaped, err := url.PathUnescape(path)
+		if err != nil {
+			unescaped = path
+		}
+		newHref, err := filepath.Rel(newDir, filepath.Join(dir, unescaped))
+		if err != nil {
+			return match
+		}
+		newHref = filepath.ToSlash(newHref)
+		if unescaped != path {
+			newHref = strings.ReplaceAll(newHref, " ", "%20")
+		}
+		return prefix + newHref + suffix
+	})
+}
+
+// mergedMetadataIgnoredKeys are the frontmatter keys which are not carried
+// over when merging notes: the survivor keeps its own title and date, and the
+// tags are merged separately.
+var mergedMetadataIgnoredKeys = []string{"title", "date", "tag", "tags", "keyword", "keywords"}
+
+// mergedMetadata returns the frontmatter keys of the merged notes which are
+// missing from the survivor. An error is returned when the notes have
+// different values for the same key.
+func mergedMetadata(survivor ContextualNote, merged []ContextualNote) (map[string]interface{}, error) {
+	metadata := map[string]interface{}{}
+	for _, note := range merged {
+		for key, value := range note.Metadata {
+			if strutil.Contains(mergedMetadataIgnoredKeys, key) {
+				continue
+			}
+			existing, ok := survivor.Metadata[key]
+			if !ok {
+				existing, ok = metadata[key]
+			}
+			if ok && !reflect.DeepEqual(existing, value) {
+				return nil, fmt.Errorf("%s: conflicting value for the `%s` frontmatter key", note.Path, key)
+			}
+			if _, ok := survivor.Metadata[key]; !ok {
+				metadata[key] = value
+			}
+		}
+	}
+	return metadata, nil
+}
+
+// addFrontmatterKeys adds the given keys to the frontmatter of content,
+// creating it if needed.
+func addFrontmatterKeys(content string, metadata map[string]interface{}) (string, error) {
+	if len(metadata) == 0 {
+		return content, nil
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := ""
+	for _, key := range keys {
+		data, err := yaml.Marshal(map[string]interface{}{key: metadata[key]})
+		if err != nil {
+			return content, err
+		}
+		lines += string(data)
+	}
+
+	_, end, ok := frontmatterYAML(content)
+	if !ok {
+		return "---\n" + lines + "---\n" + content, nil
+	}
+	return content[:end] + lines + content[end:], nil
+}
+
+// noteHrefFor returns an href targeting the note at path, written in the same
+// style as the given link found in a note located in dir. All paths are
+// relative to the notebook root.
+func noteHrefFor(link Link, dir string, path string) string {
+	href := strings.SplitN(link.Href, "#", 2)[0]
+	format := func(path string) string {
+		path = filepath.ToSlash(path)
+		if filepath.Ext(href) == "" {
+			path = paths.DropExt(path)
+		}
+		return path
+	}
+
+	if link.Type == LinkTypeWikiLink {
+		// Wiki links are relative to the notebook root, or only the filename.
+		if strings.Contains(href, "/") {
+			return format(path)
+		}
+		return format(filepath.Base(path))
+	}
+
+	// Markdown links are relative to the source note.
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return href
+	}
+	return format(rel)
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"path/filepath"
+)
+
+// DuplicateGroupFormatter formats groups of duplicate notes to be printed on
+// the screen.
+type DuplicateGroupFormatter func(group DuplicateGroup) (string, error)
+
+func newDuplicateGroupFormatter(basePath string, template Template, fs FileStorage) (DuplicateGroupFormatter, error) {
+	return func(group DuplicateGroup) (string, error) {
+		notes := make([]duplicateNoteRenderContext, 0, len(group.Notes))
+		for _, note := range group.Notes {
+			path, err := NotebookPath{
+				Path:       note.Path,
+				BasePath:   basePath,
+				WorkingDir: fs.WorkingDir(),
+			}.PathRelToWorkingDir()
+			if err != nil {
+				return "", err
+			}
+			notes = append(notes, duplicateNoteRenderContext{
+				Path:    path,
+				AbsPath: filepath.Join(basePath, note.Path),
+				Title:   note.Title,
+			})
+		}
+
+		return template.Render(duplicateGroupFormatRenderContext{
+			Kind:       string(group.Kind),
+			Similarity: group.Similarity,
+			Notes:      notes,
+		})
+	}, nil
+}
+
+// duplicateGroupFormatRenderContext holds the variables available to the
+// duplicate groups formatting templates.
+type duplicateGroupFormatRenderContext struct {
+	// Kind of duplicates: exact, similar or title.
+	Kind string `json:"kind"`
+	// Lowest similarity between two notes of the group, from 0 to 1.
+	Similarity float64 `json:"similarity"`
+	// Duplicate notes, from the oldest one.
+	Notes []duplicateNoteRenderContext `json:"notes"`
+}
+
+type duplicateNoteRenderContext struct {
+	// Path of the note, relative to the working directory.
+	Path string `json:"path"`
+	// Absolute path of the note.
+	AbsPath string `json:"absPath" handlebars:"abs-path"`
+	// Title of the note.
+	Title string `json:"title"`
+}
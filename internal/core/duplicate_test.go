@@ -0,0 +1,158 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestGroupNotesBy(t *testing.T) {
+	notes := []ContextualNote{
+		{Note: Note{Path: "b.md", Checksum: "1", Created: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		{Note: Note{Path: "c.md", Checksum: "2"}},
+		{Note: Note{Path: "a.md", Checksum: "1", Created: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{Note: Note{Path: "d.md", Checksum: ""}},
+		{Note: Note{Path: "e.md", Checksum: ""}},
+	}
+
+	groups := groupNotesBy(DuplicateExact, notes, func(note ContextualNote) string {
+		return note.Checksum
+	})
+	assert.Equal(t, len(groups), 1)
+	assert.Equal(t, groups[0].Kind, DuplicateExact)
+	assert.Equal(t, groups[0].Similarity, 1.0)
+	assert.Equal(t, groups[0].Notes[0].Path, "a.md")
+	assert.Equal(t, groups[0].Notes[1].Path, "b.md")
+}
+
+func TestFindSimilarNotes(t *testing.T) {
+	notes := []ContextualNote{
+		{Note: Note{Path: "a.md", Checksum: "a", Body: "Apples are red fruits growing on trees in the orchard all year long."}},
+		{Note: Note{Path: "b.md", Checksum: "b", Body: "Apples are red fruits growing on trees in the orchard all year long, every year."}},
+		{Note: Note{Path: "c.md", Checksum: "a", Body: "Apples are red fruits growing on trees in the orchard all year long."}},
+		{Note: Note{Path: "d.md", Checksum: "d", Body: "Bananas are yellow fruits growing in tropical countries."}},
+		{Note: Note{Path: "e.md", Checksum: "e", Body: "Too short"}},
+		{Note: Note{Path: "f.md", Checksum: "f", Body: "Too short"}},
+	}
+
+	groups := findSimilarNotes(notes, 0.8)
+	assert.Equal(t, len(groups), 1)
+	assert.Equal(t, groups[0].Kind, DuplicateSimilar)
+	assert.Equal(t, groups[0].Similarity, 0.84)
+	paths := []string{}
+	for _, note := range groups[0].Notes {
+		paths = append(paths, note.Path)
+	}
+	assert.Equal(t, paths, []string{"a.md", "b.md", "c.md"})
+
+	groups = findSimilarNotes(notes, 0.95)
+	assert.Equal(t, len(groups), 0)
+}
+
+func TestJaccard(t *testing.T) {
+	assert.Equal(t, jaccard(shingle(""), shingle("")), 0.0)
+	assert.Equal(t, jaccard(shingle("one two three"), shingle("One, two... three!")), 1.0)
+	assert.Equal(t, jaccard(shingle("one two three four"), shingle("one two three five")), 1.0/3.0)
+}
+
+func TestNoteHrefFor(t *testing.T) {
+	test := func(linkType LinkType, href string, dir string, expected string) {
+		actual := noteHrefFor(Link{Type: linkType, Href: href}, dir, "dir/survivor.md")
+		assert.Equal(t, actual, expected)
+	}
+
+	test(LinkTypeWikiLink, "old", ".", "survivor")
+	test(LinkTypeWikiLink, "other/old", "log", "dir/survivor")
+	test(LinkTypeWikiLink, "old.md", ".", "survivor.md")
+	test(LinkTypeMarkdown, "old.md", ".", "dir/survivor.md")
+	test(LinkTypeMarkdown, "../old", "log", "../dir/survivor")
+	test(LinkTypeMarkdown, "old.md#anchor", "dir", "survivor.md")
+}
+
+func TestRewriteNoteHrefs(t *testing.T) {
+	test := func(content string, linkType LinkType, href string, dir string, expectedContent string, expectedChanged bool) {
+		actual, changed := rewriteNoteHrefs(content, Link{Type: linkType, Href: href}, dir, "new")
+		assert.Equal(t, actual, expectedContent)
+		assert.Equal(t, changed, expectedChanged)
+	}
+
+	test("No link", LinkTypeWikiLink, "old", ".", "No link", false)
+	test("[[other]]", LinkTypeWikiLink, "old", ".", "[[other]]", false)
+	test("[[old]] and [[old|Label]]", LinkTypeWikiLink, "old", ".", "[[new]] and [[new|Label]]", true)
+	test("[[old#section]]", LinkTypeWikiLink, "old#section", ".", "[[new#section]]", true)
+	test("[[old]] and [Old](old)", LinkTypeWikiLink, "old", ".", "[[new]] and [Old](old)", true)
+	test("[Old](old) and [Old](<old> \"Title\")", LinkTypeMarkdown, "old", ".", "[Old](new) and [Old](<new> \"Title\")", true)
+	test("A [note][1]\n\n[1]: old\n", LinkTypeMarkdown, "old", ".", "A [note][1]\n\n[1]: new\n", true)
+	test("[Old](new)", LinkTypeMarkdown, "new", ".", "[Old](new)", false)
+	// Markdown hrefs are indexed relative to the notebook root.
+	test("[Old](../old.md) and [Other](old.md)", LinkTypeMarkdown, "old.md", "sub", "[Old](new) and [Other](old.md)", true)
+	test("[Old](old.md)", LinkTypeMarkdown, "sub/old.md", "sub", "[Old](new)", true)
+	test("[Old](old%20note.md)", LinkTypeMarkdown, "old note.md", ".", "[Old](new)", true)
+	test("[[old]]", LinkTypeMarkdown, "old", ".", "[[old]]", false)
+}
+
+func TestUnwrapNoteLinks(t *testing.T) {
+	test := func(content string, linkType LinkType, href string, dir string, expectedContent string, expectedChanged bool) {
+		actual, changed := unwrapNoteLinks(content, Link{Type: linkType, Href: href}, dir, "new")
+		assert.Equal(t, actual, expectedContent)
+		assert.Equal(t, changed, expectedChanged)
+	}
+
+	test("No link", LinkTypeWikiLink, "old", ".", "No link", false)
+	test("[[other]]", LinkTypeWikiLink, "old", ".", "[[other]]", false)
+	test("[[old]] and [[ old | Label ]]", LinkTypeWikiLink, "old", ".", "old and Label", true)
+	test("[[old#section]]", LinkTypeWikiLink, "old#section", ".", "old", true)
+	test("[Old](old) and ![Old](old.png)", LinkTypeMarkdown, "old", ".", "Old and ![Old](old.png)", true)
+	test("[Old](<old> \"Title\") and [Other](other)", LinkTypeMarkdown, "old", ".", "Old and [Other](other)", true)
+	// The link reference definitions are redirected.
+	test("A [note][1]\n\n[1]: old\n", LinkTypeMarkdown, "old", ".", "A [note][1]\n\n[1]: new\n", true)
+	// Markdown hrefs are indexed relative to the notebook root.
+	test("[Old](../old.md) and [Other](old.md)", LinkTypeMarkdown, "old.md", "sub", "Old and [Other](old.md)", true)
+}
+
+func TestRebaseHrefs(t *testing.T) {
+	test := func(body string, dir string, newDir string, expected string) {
+		assert.Equal(t, rebaseHrefs(body, dir, newDir), expected)
+	}
+
+	test("[Note](note.md)", "dir", "dir", "[Note](note.md)")
+	test("[Note](note.md)", "dir", ".", "[Note](dir/note.md)")
+	test("[Note](../note.md#section)", "dir", "other", "[Note](../note.md#section)")
+	test("![Image](img/a%20b.png)", "dir", ".", "![Image](dir/img/a%20b.png)")
+	test("[Site](https://example.com) [Top](#top) [Abs](/abs.md)", "dir", ".", "[Site](https://example.com) [Top](#top) [Abs](/abs.md)")
+}
+
+func TestMergedMetadata(t *testing.T) {
+	survivor := ContextualNote{Note: Note{Path: "survivor.md", Metadata: map[string]interface{}{"title": "Survivor", "author": "Jo"}}}
+
+	metadata, err := mergedMetadata(survivor, []ContextualNote{
+		{Note: Note{Path: "a.md", Metadata: map[string]interface{}{"title": "A", "tags": []interface{}{"a"}, "author": "Jo", "season": "autumn"}}},
+		{Note: Note{Path: "b.md", Metadata: map[string]interface{}{"season": "autumn"}}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, metadata, map[string]interface{}{"season": "autumn"})
+
+	_, err = mergedMetadata(survivor, []ContextualNote{
+		{Note: Note{Path: "a.md", Metadata: map[string]interface{}{"author": "Max"}}},
+	})
+	assert.Err(t, err, "a.md: conflicting value for the `author` frontmatter key")
+
+	_, err = mergedMetadata(survivor, []ContextualNote{
+		{Note: Note{Path: "a.md", Metadata: map[string]interface{}{"season": "autumn"}}},
+		{Note: Note{Path: "b.md", Metadata: map[string]interface{}{"season": "winter"}}},
+	})
+	assert.Err(t, err, "b.md: conflicting value for the `season` frontmatter key")
+}
+
+func TestAddFrontmatterKeys(t *testing.T) {
+	test := func(content string, metadata map[string]interface{}, expected string) {
+		actual, err := addFrontmatterKeys(content, metadata)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("Body\n", map[string]interface{}{}, "Body\n")
+	test("Body\n", map[string]interface{}{"season": "autumn"}, "---\nseason: autumn\n---\nBody\n")
+	test("---\ntitle: Note\n---\nBody\n", map[string]interface{}{"season": "autumn", "author": "Jo"}, "---\ntitle: Note\nauthor: Jo\nseason: autumn\n---\nBody\n")
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// EncryptedNoteField is a property of an encrypted note which can be saved in
+// the index.
+type EncryptedNoteField string
+
+const (
+	EncryptedNoteTitle    EncryptedNoteField = "title"
+	EncryptedNoteTags     EncryptedNoteField = "tags"
+	EncryptedNoteLinks    EncryptedNoteField = "links"
+	EncryptedNoteMetadata EncryptedNoteField = "metadata"
+	EncryptedNoteBody     EncryptedNoteField = "body"
+)
+
+// EncryptedNoteFieldFromString returns the EncryptedNoteField matching the
+// given name.
+func EncryptedNoteFieldFromString(name string) (EncryptedNoteField, error) {
+	switch field := EncryptedNoteField(name); field {
+	case EncryptedNoteTitle, EncryptedNoteTags, EncryptedNoteLinks, EncryptedNoteMetadata, EncryptedNoteBody:
+		return field, nil
+	default:
+		return "", fmt.Errorf("%s: unknown encrypted note property, expected one of: title, tags, links, metadata, body", name)
+	}
+}
+
+// IsEncrypted returns whether the note at the given absolute path belongs to
+// a group with encryption enabled.
+func (n *Notebook) IsEncrypted(absPath string) bool {
+	relPath, err := n.RelPath(absPath)
+	if err != nil {
+		return false
+	}
+	config, err := n.Config.GroupConfigForPath(relPath)
+	if err != nil {
+		n.logger.Err(err)
+		return false
+	}
+	return config.Note.Encrypt
+}
+
+// ReadNote returns the content of the note at the given absolute path,
+// decrypted if needed.
+func (n *Notebook) ReadNote(absPath string) ([]byte, error) {
+	content, err := n.fs.Read(absPath)
+	if err != nil || !n.IsEncrypted(absPath) {
+		return content, err
+	}
+	return n.decrypt(content)
+}
+
+// WriteNote saves the given content of the note at the absolute path,
+// encrypting it if needed.
+func (n *Notebook) WriteNote(absPath string, content []byte) error {
+	if n.IsEncrypted(absPath) {
+		var err error
+		content, err = n.encrypt(content)
+		if err != nil {
+			return err
+		}
+	}
+	return n.fs.Write(absPath, content)
+}
+
+// encrypt pipes the plaintext into the configured encryption command.
+func (n *Notebook) encrypt(plaintext []byte) ([]byte, error) {
+	return n.runEncryptionCommand("encrypt", n.Config.Encryption.Encrypt, plaintext)
+}
+
+// decrypt pipes the ciphertext into the configured decryption command.
+func (n *Notebook) decrypt(ciphertext []byte) ([]byte, error) {
+	return n.runEncryptionCommand("decrypt", n.Config.Encryption.Decrypt, ciphertext)
+}
+
+func (n *Notebook) runEncryptionCommand(name string, command string, input []byte) ([]byte, error) {
+	wrap := errors.Wrapperf("failed to %s the note", name)
+	if command == "" {
+		return nil, wrap(fmt.Errorf("no %s command set in the [encryption] config section", name))
+	}
+	if n.hookRunner == nil {
+		return nil, wrap(errors.New("cannot run external commands"))
+	}
+	output, err := n.hookRunner(command, n.Path, input)
+	return output, wrap(err)
+}
+
+// redactNote removes from an encrypted note the properties which must not be
+// saved in the index, according to the encryption config.
+func redactNote(note Note, fields []EncryptedNoteField) Note {
+	has := func(field EncryptedNoteField) bool {
+		for _, f := range fields {
+			if f == field {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !has(EncryptedNoteTitle) {
+		note.Title = ""
+	}
+	if !has(EncryptedNoteTags) {
+		note.Tags = []string{}
+	}
+	if !has(EncryptedNoteLinks) {
+		note.Links = []Link{}
+	}
+	if !has(EncryptedNoteMetadata) {
+		note.Metadata = map[string]interface{}{}
+	}
+	if !has(EncryptedNoteBody) {
+		note.Lead = ""
+		note.Body = ""
+		note.RawContent = ""
+		note.WordCount = 0
+		note.Tasks = []Task{}
+		note.Cards = []Card{}
+		// The checksum would allow guessing short contents.
+		note.Checksum = ""
+	}
+	return note
+}
+
+// indexedNote returns the version of the note saved in the index.
+func (n *Notebook) indexedNote(note Note) Note {
+	if !n.IsEncrypted(filepath.Join(n.Path, note.Path)) {
+		return note
+	}
+	return redactNote(note, n.Config.Encryption.Index)
+}
+
+// indexParser returns the NoteParser used to index the notes at the given
+// paths, relative to the notebook root. Encrypted notes are redacted.
+func (n *Notebook) indexParser(paths []string) NoteParser {
+	return encryptedNoteParser{parser: n.historyParser(paths), notebook: n}
+}
+
+// encryptedNoteParser redacts the encrypted notes parsed by another
+// NoteParser before they are indexed.
+type encryptedNoteParser struct {
+	parser   NoteParser
+	notebook *Notebook
+}
+
+// ParseNoteAt implements NoteParser.
+func (p encryptedNoteParser) ParseNoteAt(absPath string) (*Note, error) {
+	note, err := p.parser.ParseNoteAt(absPath)
+	if note == nil || err != nil {
+		return note, err
+	}
+	redacted := p.notebook.indexedNote(*note)
+	return &redacted, nil
+}
@@ -33,4 +33,11 @@ type FileStorage interface {
 	// Write creates or overwrite the content at the given file path, creating
 	// any intermediate directories if needed.
 	Write(path string, content []byte) error
+
+	// Rename moves the file at oldPath to newPath, creating any intermediate
+	// directories if needed.
+	Rename(oldPath string, newPath string) error
+
+	// Remove deletes the file at the given file path.
+	Remove(path string) error
 }
@@ -79,3 +79,14 @@ func (fs *fileStorageMock) Write(path string, content []byte) error {
 	fs.files[path] = string(content)
 	return nil
 }
+
+func (fs *fileStorageMock) Rename(oldPath string, newPath string) error {
+	fs.files[newPath] = fs.files[oldPath]
+	delete(fs.files, oldPath)
+	return nil
+}
+
+func (fs *fileStorageMock) Remove(path string) error {
+	delete(fs.files, path)
+	return nil
+}
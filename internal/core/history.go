@@ -0,0 +1,184 @@
+package core
+
+import (
+	"time"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// NoteHistory retrieves the revisions of the notebook files from a version
+// control system, such as git.
+type NoteHistory interface {
+	// Dates returns the creation and last modification dates of the given
+	// files, indexed by their path relative to the notebook root. All the
+	// tracked files are returned when paths is nil.
+	Dates(paths []string) (map[string]NoteDates, error)
+	// Revisions returns the revisions of the file at path, relative to the
+	// notebook root, from the most recent one.
+	Revisions(path string) ([]NoteRevision, error)
+	// PathsChangedSince returns the files changed since the given revision,
+	// including the uncommitted changes.
+	PathsChangedSince(rev string) ([]string, error)
+	// PathsModifiedBy returns the files modified in the revisions of the
+	// given author.
+	PathsModifiedBy(author string) ([]string, error)
+}
+
+// NoteDates holds the dates of a file derived from its history.
+type NoteDates struct {
+	// Date of the first revision of the file.
+	Created time.Time
+	// Date of the last revision of the file, or zero if the file has
+	// uncommitted changes.
+	Modified time.Time
+}
+
+// NoteRevision is a revision of a note recorded in the version control
+// system.
+type NoteRevision struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+}
+
+// NoteHistoryFilter selects the notes from their history.
+type NoteHistoryFilter struct {
+	// Revision since which the notes were changed.
+	ChangedSince string
+	// Authors of the revisions modifying the notes.
+	ModifiedBy []string
+}
+
+// IsEmpty returns whether the filter doesn't select anything.
+func (f NoteHistoryFilter) IsEmpty() bool {
+	return f.ChangedSince == "" && len(f.ModifiedBy) == 0
+}
+
+// NoteRevisions returns the revisions of the note at the given path, relative
+// to the notebook root.
+func (n *Notebook) NoteRevisions(path string) ([]NoteRevision, error) {
+	if n.history == nil {
+		return nil, errors.New("note history is not available")
+	}
+	revisions, err := n.history.Revisions(path)
+	return revisions, errors.Wrapf(err, "%s: failed to read the history", path)
+}
+
+// FindNoteIDsInHistory returns the IDs of the indexed notes matching the
+// given history filter.
+func (n *Notebook) FindNoteIDsInHistory(filter NoteHistoryFilter) ([]NoteID, error) {
+	wrap := errors.Wrapper("failed to read the notebook history")
+
+	ids := []NoteID{}
+	if filter.IsEmpty() {
+		return ids, nil
+	}
+	if n.history == nil {
+		return nil, wrap(errors.New("note history is not available"))
+	}
+
+	var paths map[string]bool
+	intersect := func(found []string) {
+		set := map[string]bool{}
+		for _, path := range found {
+			if paths == nil || paths[path] {
+				set[path] = true
+			}
+		}
+		paths = set
+	}
+
+	if filter.ChangedSince != "" {
+		changed, err := n.history.PathsChangedSince(filter.ChangedSince)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		intersect(changed)
+	}
+	if len(filter.ModifiedBy) > 0 {
+		modified := []string{}
+		for _, author := range filter.ModifiedBy {
+			found, err := n.history.PathsModifiedBy(author)
+			if err != nil {
+				return nil, wrap(err)
+			}
+			modified = append(modified, found...)
+		}
+		intersect(modified)
+	}
+
+	if len(paths) == 0 {
+		return ids, nil
+	}
+	hrefs := make([]string, 0, len(paths))
+	for path := range paths {
+		hrefs = append(hrefs, path)
+	}
+	notes, err := n.index.FindMinimal(NoteFindOpts{IncludeHrefs: hrefs})
+	if err != nil {
+		return nil, wrap(err)
+	}
+	for _, note := range notes {
+		// Hrefs also match the descendants of a directory.
+		if paths[note.Path] {
+			ids = append(ids, note.ID)
+		}
+	}
+	return ids, nil
+}
+
+// historyNoteParser is a NoteParser setting the dates of the parsed notes
+// derived from their history.
+type historyNoteParser struct {
+	parser NoteParser
+	dates  map[string]NoteDates
+}
+
+// ParseNoteAt implements NoteParser.
+func (p historyNoteParser) ParseNoteAt(absPath string) (*Note, error) {
+	note, err := p.parser.ParseNoteAt(absPath)
+	if err != nil || note == nil {
+		return note, err
+	}
+	if dates, ok := p.dates[note.Path]; ok {
+		applyHistoryDates(note, dates)
+	}
+	return note, nil
+}
+
+// applyHistoryDates sets the dates of the note from the given ones. A creation
+// date set in the frontmatter takes precedence. The modification date of the
+// file is kept to detect the changes of the note.
+func applyHistoryDates(note *Note, dates NoteDates) {
+	if _, ok := creationDateFromMetadata(note.Metadata); !ok && !dates.Created.IsZero() {
+		note.Created = dates.Created
+	}
+	if !dates.Modified.IsZero() {
+		modified := dates.Modified
+		note.HistoryModified = &modified
+	}
+}
+
+// maxHistoryPaths is the number of paths above which the whole history is
+// read, instead of passing the paths on the git command line.
+const maxHistoryPaths = 100
+
+// historyParser returns the NoteParser used to index the notes at the given
+// paths, relative to the notebook root. The dates are taken from the history
+// when enabled in the configuration.
+func (n *Notebook) historyParser(paths []string) NoteParser {
+	if !n.Config.Git.Dates || n.history == nil || (paths != nil && len(paths) == 0) {
+		return n
+	}
+	if len(paths) > maxHistoryPaths {
+		paths = nil
+	}
+	dates, err := n.history.Dates(paths)
+	if err != nil {
+		n.logger.Err(errors.Wrap(err, "failed to read the dates from the notebook history"))
+		return n
+	}
+	return historyNoteParser{parser: n, dates: dates}
+}
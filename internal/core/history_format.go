@@ -0,0 +1,39 @@
+package core
+
+// NoteRevisionFormatter formats note revisions to be printed on the screen.
+type NoteRevisionFormatter func(revision NoteRevision) (string, error)
+
+func newNoteRevisionFormatter(template Template) (NoteRevisionFormatter, error) {
+	return func(revision NoteRevision) (string, error) {
+		shortHash := revision.Hash
+		if len(shortHash) > 7 {
+			shortHash = shortHash[:7]
+		}
+
+		return template.Render(noteRevisionFormatRenderContext{
+			Hash:      revision.Hash,
+			ShortHash: shortHash,
+			Author:    revision.Author,
+			Email:     revision.Email,
+			Date:      revision.Date,
+			Message:   revision.Message,
+		})
+	}, nil
+}
+
+// noteRevisionFormatRenderContext holds the variables available to the note
+// revision formatting templates.
+type noteRevisionFormatRenderContext struct {
+	// Full hash of the commit.
+	Hash string `json:"hash"`
+	// Abbreviated hash of the commit.
+	ShortHash string `json:"shortHash" handlebars:"short-hash"`
+	// Name of the author of the commit.
+	Author string `json:"author"`
+	// Email of the author of the commit.
+	Email string `json:"email"`
+	// Date of the commit.
+	Date interface{} `json:"date"`
+	// First line of the commit message.
+	Message string `json:"message"`
+}
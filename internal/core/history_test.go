@@ -0,0 +1,31 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestApplyHistoryDates(t *testing.T) {
+	fsCreated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsModified := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	gitCreated := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	gitModified := time.Date(2022, 5, 6, 0, 0, 0, 0, time.UTC)
+
+	test := func(metadata map[string]interface{}, dates NoteDates, expectedCreated time.Time, expectedHistoryModified *time.Time) {
+		note := Note{Created: fsCreated, Modified: fsModified, Metadata: metadata}
+		applyHistoryDates(&note, dates)
+		assert.Equal(t, note.Created, expectedCreated)
+		assert.Equal(t, note.HistoryModified, expectedHistoryModified)
+		// The file date is kept to detect the changes.
+		assert.Equal(t, note.Modified, fsModified)
+	}
+
+	test(map[string]interface{}{}, NoteDates{}, fsCreated, nil)
+	test(map[string]interface{}{}, NoteDates{Created: gitCreated, Modified: gitModified}, gitCreated, &gitModified)
+	// Uncommitted changes keep the file modification date.
+	test(map[string]interface{}{}, NoteDates{Created: gitCreated}, gitCreated, nil)
+	// The frontmatter date takes precedence.
+	test(map[string]interface{}{"date": "2020-01-01 10:00"}, NoteDates{Created: gitCreated, Modified: gitModified}, fsCreated, &gitModified)
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// HookEvent is a step of the lifecycle of the notes on which the user can run
+// a shell command.
+type HookEvent string
+
+const (
+	// HookPreNew runs before writing a new note. It can veto the creation or
+	// replace the content of the note.
+	HookPreNew HookEvent = "pre-new"
+	// HookPostNew runs after a new note is written and indexed.
+	HookPostNew HookEvent = "post-new"
+	// HookPostIndex runs after the notebook is indexed.
+	HookPostIndex HookEvent = "post-index"
+	// HookPreEdit runs before opening a note in the editor. It can veto the
+	// edition or replace the content of the note.
+	HookPreEdit HookEvent = "pre-edit"
+	// HookPostEdit runs after the editor is closed.
+	HookPostEdit HookEvent = "post-edit"
+)
+
+// HookRunner runs the given shell command from the directory dir, with input
+// as its standard input.
+//
+// Returns the standard output of the command, or an error if it exited with
+// a non-zero status.
+type HookRunner func(command string, dir string, input []byte) ([]byte, error)
+
+// isPre returns whether the hook runs before an action, in which case it can
+// veto it or modify the note.
+func (e HookEvent) isPre() bool {
+	return e == HookPreNew || e == HookPreEdit
+}
+
+// RunNoteHook runs the hook registered for the given event with the note
+// found at absPath.
+//
+// When a pre hook prints something on its standard output, the note is
+// rewritten with it. A pre hook exiting with a non-zero status returns an
+// error, which vetoes the pending action. Failures of post hooks are only
+// logged.
+func (n *Notebook) RunNoteHook(event HookEvent, absPath string) error {
+	if n.Config.Hooks.Command(event) == "" {
+		return nil
+	}
+	wrap := errors.Wrapperf("%s hook", event)
+
+	note, err := n.ParseNoteAt(absPath)
+	if err == nil {
+		var content []byte
+		content, err = n.runNoteHook(event, *note)
+		if err == nil && content != nil && string(content) != note.RawContent {
+			err = n.WriteNote(absPath, content)
+		}
+	}
+
+	if err != nil && !event.isPre() {
+		n.logger.Err(wrap(err))
+		return nil
+	}
+	return wrap(err)
+}
+
+// runNoteHook runs the hook registered for the given event with the note
+// serialized as JSON, in the same shape as `zk list --format json`.
+//
+// Returns the new content of the note printed by the hook, or nil.
+func (n *Notebook) runNoteHook(event HookEvent, note Note) ([]byte, error) {
+	linkFormatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return nil, err
+	}
+	context, err := newNoteFormatRenderContext(ContextualNote{Note: note}, n.Path, "", linkFormatter, n.osEnv(), n.fs)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := n.runHook(event, context)
+	if err != nil || !event.isPre() || len(bytes.TrimSpace(output)) == 0 {
+		return nil, err
+	}
+	return output, nil
+}
+
+// runHook runs the hook registered for the given event, with the given input
+// serialized as JSON.
+func (n *Notebook) runHook(event HookEvent, input interface{}) ([]byte, error) {
+	command := n.Config.Hooks.Command(event)
+	if command == "" || n.hookRunner == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	return n.hookRunner(command, n.Path, data)
+}
@@ -1,10 +1,53 @@
 package core
 
+import "fmt"
+
 // IDOptions holds the options used to generate an ID.
 type IDOptions struct {
-	Length  int
-	Charset Charset
-	Case    Case
+	Strategy IDStrategy
+	Length   int
+	Charset  Charset
+	Case     Case
+	// strftime format used by the timestamp strategy.
+	Format string
+}
+
+// IDStrategy represents an algorithm used to generate new note IDs.
+type IDStrategy int
+
+const (
+	// Random string, using the Length, Charset and Case options.
+	IDStrategyRandom IDStrategy = iota + 1
+	// Lexicographically sortable ULID.
+	IDStrategyULID
+	// Time-ordered UUID version 7.
+	IDStrategyUUID
+	// Current date and time, using a strftime Format.
+	IDStrategyTimestamp
+	// Notebook-wide counter, zero-padded to Length digits.
+	IDStrategySequential
+	// Luhmann-style IDs derived from a parent note, e.g. 1a2.
+	IDStrategyFolgezettel
+)
+
+// IDStrategyFromString returns an IDStrategy from its string representation.
+func IDStrategyFromString(str string) (IDStrategy, error) {
+	switch str {
+	case "random", "":
+		return IDStrategyRandom, nil
+	case "ulid":
+		return IDStrategyULID, nil
+	case "uuid":
+		return IDStrategyUUID, nil
+	case "timestamp":
+		return IDStrategyTimestamp, nil
+	case "sequential":
+		return IDStrategySequential, nil
+	case "folgezettel":
+		return IDStrategyFolgezettel, nil
+	default:
+		return 0, fmt.Errorf("%s: unknown ID strategy\ntry random, ulid, uuid, timestamp, sequential or folgezettel", str)
+	}
 }
 
 // Charset is a set of characters.
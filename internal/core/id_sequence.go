@@ -0,0 +1,161 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// idSequenceKey is the notebook metadata key holding the last sequential ID.
+const idSequenceKey = "zk.id.sequence"
+
+// idSequence generates IDs from a notebook-wide counter persisted in the
+// note index.
+type idSequence struct {
+	value   int
+	padding int
+}
+
+// newIDSequence returns an idSequence starting after the last persisted
+// counter. When the counter was never persisted, it starts after the highest
+// number found in the filenames of the indexed notes matching idRegex.
+func (n *Notebook) newIDSequence(padding int, idRegex *regexp.Regexp) (*idSequence, error) {
+	seq := &idSequence{padding: padding}
+
+	value, err := n.index.Metadata(idSequenceKey)
+	if err != nil {
+		return nil, err
+	}
+	if value != "" {
+		seq.value, err = strconv.Atoi(value)
+		return seq, err
+	}
+
+	notes, err := n.index.FindMinimal(NoteFindOpts{})
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		stem := paths.FilenameStem(note.Path)
+		if !idRegex.MatchString(stem) {
+			continue
+		}
+		digits := leadingDigitsRegex.FindString(stem)
+		if i, err := strconv.Atoi(digits); err == nil && i > seq.value {
+			seq.value = i
+		}
+	}
+	return seq, nil
+}
+
+var leadingDigitsRegex = regexp.MustCompile(`^\d+`)
+
+// hasNoteWithID returns whether the filename of an indexed note is the
+// given ID.
+func (n *Notebook) hasNoteWithID(id string) (bool, error) {
+	notes, err := n.index.FindMinimal(NoteFindOpts{})
+	if err != nil {
+		return false, err
+	}
+	for _, note := range notes {
+		if strings.EqualFold(paths.FilenameStem(note.Path), id) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sequentialIDRegex returns a regex matching the sequential IDs padded to the
+// given number of digits.
+func sequentialIDRegex(padding int) *regexp.Regexp {
+	if padding < 1 {
+		padding = 1
+	}
+	return regexp.MustCompile(fmt.Sprintf(`^\d{%d,}$`, padding))
+}
+
+// next increments the counter and returns it as a new ID.
+func (s *idSequence) next() string {
+	s.value++
+	return fmt.Sprintf("%0*d", s.padding, s.value)
+}
+
+// save persists the current value of the counter.
+func (n *Notebook) saveIDSequence(seq *idSequence) error {
+	return n.index.SetMetadata(idSequenceKey, strconv.Itoa(seq.value))
+}
+
+// folgezettelRegex matches Luhmann-style IDs alternating numbers and lowercase
+// letters, e.g. 1a2b.
+var folgezettelRegex = regexp.MustCompile(`^\d+([a-z]+\d+)*[a-z]*$`)
+
+// folgezettelSegmentRegex splits a Folgezettel ID into its segments.
+var folgezettelSegmentRegex = regexp.MustCompile(`\d+|[a-z]+`)
+
+// newFolgezettelIDGenerator returns a generator of candidate IDs for a note
+// following the given one in a Folgezettel.
+//
+// The first candidate continues the sequence of the parent (1a2 -> 1a3). If it
+// is already taken, the following candidates branch out of the parent (1a2a,
+// 1a2b, …).
+func newFolgezettelIDGenerator(parent string) (IDGenerator, error) {
+	parent = strings.ToLower(parent)
+	if !folgezettelRegex.MatchString(parent) {
+		return nil, fmt.Errorf("%s: not a valid Folgezettel ID", parent)
+	}
+
+	sibling := nextFolgezettelSibling(parent)
+	child := ""
+	return func() string {
+		if sibling != "" {
+			id := sibling
+			sibling = ""
+			return id
+		}
+		if child == "" {
+			child = firstFolgezettelChild(parent)
+		} else {
+			child = nextFolgezettelSibling(child)
+		}
+		return child
+	}, nil
+}
+
+// nextFolgezettelSibling returns the ID following the given one at the same
+// level, e.g. 1a2 -> 1a3.
+func nextFolgezettelSibling(id string) string {
+	segments := folgezettelSegmentRegex.FindAllString(id, -1)
+	last := segments[len(segments)-1]
+	return strings.TrimSuffix(id, last) + nextFolgezettelSegment(last)
+}
+
+// firstFolgezettelChild returns the first ID branching out of the given one,
+// e.g. 1a2 -> 1a2a and 1a -> 1a1.
+func firstFolgezettelChild(id string) string {
+	last := id[len(id)-1]
+	if last >= '0' && last <= '9' {
+		return id + "a"
+	}
+	return id + "1"
+}
+
+// nextFolgezettelSegment increments a numeric (1 -> 2) or alphabetic
+// (a -> b, z -> aa) segment of a Folgezettel ID.
+func nextFolgezettelSegment(segment string) string {
+	if i, err := strconv.Atoi(segment); err == nil {
+		return strconv.Itoa(i + 1)
+	}
+
+	letters := []byte(segment)
+	for i := len(letters) - 1; i >= 0; i-- {
+		if letters[i] < 'z' {
+			letters[i]++
+			return string(letters)
+		}
+		letters[i] = 'a'
+	}
+	return "a" + string(letters)
+}
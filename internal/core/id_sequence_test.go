@@ -0,0 +1,97 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestFolgezettelIDGenerator(t *testing.T) {
+	test := func(parent string, expected []string) {
+		gen, err := newFolgezettelIDGenerator(parent)
+		assert.Nil(t, err)
+		actual := []string{}
+		for range expected {
+			actual = append(actual, gen())
+		}
+		assert.Equal(t, actual, expected)
+	}
+
+	test("1", []string{"2", "1a", "1b", "1c"})
+	test("1a2", []string{"1a3", "1a2a", "1a2b"})
+	test("1A", []string{"1b", "1a1", "1a2"})
+	test("9z", []string{"9aa", "9z1", "9z2"})
+	test("21az", []string{"21ba", "21az1"})
+}
+
+func TestFolgezettelIDGeneratorRejectsInvalidIDs(t *testing.T) {
+	test := func(parent string) {
+		_, err := newFolgezettelIDGenerator(parent)
+		assert.Err(t, err, "not a valid Folgezettel ID")
+	}
+
+	test("")
+	test("a1")
+	test("1-a")
+	test("note")
+}
+
+func TestIDSequence(t *testing.T) {
+	seq := &idSequence{value: 8, padding: 3}
+	assert.Equal(t, seq.next(), "009")
+	assert.Equal(t, seq.next(), "010")
+
+	seq = &idSequence{value: 99}
+	assert.Equal(t, seq.next(), "100")
+}
+
+func TestIDSequenceStartsAfterHighestID(t *testing.T) {
+	test := func(padding int, expected string) {
+		notebook := &Notebook{index: &idSequenceIndexMock{paths: []string{
+			"0007.md",
+			"dir/0012.md",
+			"2024-notes.md",
+			"99.md",
+			"1a3.md",
+		}}}
+		seq, err := notebook.newIDSequence(padding, sequentialIDRegex(padding))
+		assert.Nil(t, err)
+		assert.Equal(t, seq.next(), expected)
+	}
+
+	test(4, "0013")
+	test(0, "100")
+}
+
+func TestNotebookHasNoteWithID(t *testing.T) {
+	notebook := &Notebook{index: &idSequenceIndexMock{paths: []string{
+		"1.md",
+		"dir/1a2.md",
+		"1b-title.md",
+	}}}
+	test := func(id string, expected bool) {
+		found, err := notebook.hasNoteWithID(id)
+		assert.Nil(t, err)
+		assert.Equal(t, found, expected)
+	}
+
+	test("1", true)
+	test("1a2", true)
+	test("1A2", true)
+	test("1a", false)
+	test("1b", false)
+	test("typo", false)
+}
+
+type idSequenceIndexMock struct {
+	noteIndexAddMock
+	paths []string
+}
+
+func (m *idSequenceIndexMock) FindMinimal(opts NoteFindOpts) ([]MinimalNote, error) {
+	notes := []MinimalNote{}
+	for _, path := range m.paths {
+		notes = append(notes, MinimalNote{Path: path})
+	}
+	return notes, nil
+}
@@ -0,0 +1,253 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	"github.com/zk-org/zk/internal/util/opt"
+	"github.com/zk-org/zk/internal/util/paths"
+)
+
+// JournalPeriod represents the time span covered by a periodic journal note.
+type JournalPeriod int
+
+const (
+	JournalDaily JournalPeriod = iota + 1
+	JournalWeekly
+	JournalMonthly
+)
+
+// JournalPeriodFromString returns a JournalPeriod from its string
+// representation.
+func JournalPeriodFromString(str string) (JournalPeriod, error) {
+	switch str {
+	case "daily", "day", "d", "":
+		return JournalDaily, nil
+	case "weekly", "week", "w":
+		return JournalWeekly, nil
+	case "monthly", "month", "m":
+		return JournalMonthly, nil
+	default:
+		return 0, fmt.Errorf("%s: unknown journal period\ntry daily, weekly or monthly", str)
+	}
+}
+
+// String returns the name of the period, which is also the name of the config
+// group used to create its notes.
+func (p JournalPeriod) String() string {
+	switch p {
+	case JournalDaily:
+		return "daily"
+	case JournalWeekly:
+		return "weekly"
+	case JournalMonthly:
+		return "monthly"
+	default:
+		panic(fmt.Sprintf("%d: unknown core.JournalPeriod", int(p)))
+	}
+}
+
+// Start returns the beginning of the period containing the given date. Weeks
+// start on the given weekStart day.
+func (p JournalPeriod) Start(date time.Time, weekStart time.Weekday) time.Time {
+	year, month, day := date.Date()
+	switch p {
+	case JournalWeekly:
+		offset := (int(date.Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, date.Location())
+	case JournalMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	}
+}
+
+// Add returns the beginning of the n-th period following the one containing
+// the given date. A negative n moves back in time.
+func (p JournalPeriod) Add(date time.Time, n int, weekStart time.Weekday) time.Time {
+	start := p.Start(date, weekStart)
+	switch p {
+	case JournalWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case JournalMonthly:
+		return start.AddDate(0, n, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// JournalContext holds the template variables available when creating a
+// periodic journal note, under `journal`.
+type JournalContext struct {
+	// Name of the period, e.g. daily.
+	Period string
+	// Beginning of the period.
+	Start time.Time
+	// Beginning of the next period.
+	End time.Time
+	// Links to the notes of the previous and next periods.
+	Previous string
+	Next     string
+	// Paths to the notes of the previous and next periods, relative to the
+	// notebook root.
+	PreviousPath string `handlebars:"previous-path"`
+	NextPath     string `handlebars:"next-path"`
+}
+
+// JournalNoteOpts holds the options used to create a periodic journal note.
+type JournalNoteOpts struct {
+	Period JournalPeriod
+	// Any date in the period of the journal note.
+	Date time.Time
+	// Don't save the generated note on the file system.
+	DryRun bool
+}
+
+// NewJournalNote creates the journal note of the period containing the given
+// date, using the config group named after the period.
+//
+// Returns ErrNoteExists if the note was already created.
+func (n *Notebook) NewJournalNote(opts JournalNoteOpts) (*Note, error) {
+	wrap := errors.Wrapperf("%s journal", opts.Period)
+
+	dir, weekStart, err := n.journalGroup(opts.Period)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	start := opts.Period.Start(opts.Date, weekStart)
+	context := &JournalContext{
+		Period: opts.Period.String(),
+		Start:  start,
+		End:    opts.Period.Add(start, 1, weekStart),
+	}
+
+	linkFormatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return nil, wrap(err)
+	}
+	link := func(date time.Time) (string, string, error) {
+		path, _, err := n.JournalNotePath(opts.Period, date)
+		if err != nil {
+			return "", "", err
+		}
+		title := paths.FilenameStem(path)
+		if note, err := n.FindByHref(path, false); err == nil && note != nil && note.Title != "" {
+			title = note.Title
+		}
+		linkContext, err := NewLinkFormatterContext(NotebookPath{
+			Path:       path,
+			BasePath:   n.Path,
+			WorkingDir: dir,
+		}, title, map[string]interface{}{})
+		if err != nil {
+			return "", "", err
+		}
+		link, err := linkFormatter(linkContext)
+		return link, path, err
+	}
+
+	context.Previous, context.PreviousPath, err = link(opts.Period.Add(start, -1, weekStart))
+	if err != nil {
+		return nil, wrap(err)
+	}
+	context.Next, context.NextPath, err = link(context.End)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	return n.NewNote(NewNoteOpts{
+		Directory: opt.NewString(dir),
+		Group:     opt.NewString(opts.Period.String()),
+		Date:      start,
+		DryRun:    opts.DryRun,
+		Journal:   context,
+	})
+}
+
+// JournalNotePath returns the path of the journal note for the period
+// containing the given date, relative to the notebook root, and whether it
+// exists.
+//
+// The path is found by rendering the filename template of the period group,
+// which must therefore depend only on the date of the period.
+func (n *Notebook) JournalNotePath(period JournalPeriod, date time.Time) (path string, exists bool, err error) {
+	dir, weekStart, err := n.journalGroup(period)
+	if err != nil {
+		return "", false, err
+	}
+	start := period.Start(date, weekStart)
+
+	path, exists, err = n.journalNotePathWithID(period, dir, start, "journal-1")
+	if err != nil {
+		return "", false, err
+	}
+	// A filename depending on the note ID would create a new journal note
+	// every time.
+	otherPath, _, err := n.journalNotePathWithID(period, dir, start, "journal-2")
+	if err != nil {
+		return "", false, err
+	}
+	if path != otherPath {
+		return "", false, fmt.Errorf("the filename template of the `%s` group must not depend on {{id}}, use the date of the period instead", period)
+	}
+	return path, exists, nil
+}
+
+// journalNotePathWithID renders the path of a journal note with the given ID.
+func (n *Notebook) journalNotePathWithID(period JournalPeriod, dir string, start time.Time, id string) (path string, exists bool, err error) {
+	note, err := n.NewNote(NewNoteOpts{
+		Directory: opt.NewString(dir),
+		Group:     opt.NewString(period.String()),
+		Date:      start,
+		ID:        id,
+		DryRun:    true,
+	})
+	if err == nil {
+		return note.Path, false, nil
+	}
+
+	var errExists ErrNoteExists
+	if !errors.As(err, &errExists) {
+		return "", false, err
+	}
+	path, err = n.RelPath(errExists.Path)
+	return path, true, err
+}
+
+// MissingJournalPeriods returns the beginning of each period between the
+// given dates for which no journal note was created.
+func (n *Notebook) MissingJournalPeriods(period JournalPeriod, from time.Time, to time.Time) ([]time.Time, error) {
+	_, weekStart, err := n.journalGroup(period)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []time.Time{}
+	for date := period.Start(from, weekStart); !date.After(to); date = period.Add(date, 1, weekStart) {
+		_, exists, err := n.JournalNotePath(period, date)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, date)
+		}
+	}
+	return missing, nil
+}
+
+// journalGroup returns the absolute path to the directory of the journal
+// notes for the given period and the first day of their weeks, taken from the
+// config group of the same name.
+func (n *Notebook) journalGroup(period JournalPeriod) (dir string, weekStart time.Weekday, err error) {
+	group, err := n.Config.GroupConfigNamed(period.String())
+	if err != nil {
+		return "", 0, err
+	}
+	if len(group.Paths) == 0 {
+		return "", 0, fmt.Errorf("the `%s` group has no paths", period)
+	}
+	return filepath.Join(n.Path, group.Paths[0]), group.Note.WeekStart, nil
+}
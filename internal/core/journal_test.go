@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestJournalPeriodFromString(t *testing.T) {
+	test := func(str string, expected JournalPeriod) {
+		actual, err := JournalPeriodFromString(str)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("", JournalDaily)
+	test("daily", JournalDaily)
+	test("d", JournalDaily)
+	test("weekly", JournalWeekly)
+	test("week", JournalWeekly)
+	test("monthly", JournalMonthly)
+	test("m", JournalMonthly)
+
+	_, err := JournalPeriodFromString("yearly")
+	assert.Err(t, err, "yearly: unknown journal period")
+}
+
+func TestJournalPeriodStart(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	// Wednesday
+	now := time.Date(2021, 3, 17, 15, 42, 2, 0, time.UTC)
+
+	assert.Equal(t, JournalDaily.Start(now, time.Monday), date(2021, 3, 17))
+	assert.Equal(t, JournalWeekly.Start(now, time.Monday), date(2021, 3, 15))
+	assert.Equal(t, JournalWeekly.Start(date(2021, 3, 21), time.Monday), date(2021, 3, 15))
+	assert.Equal(t, JournalWeekly.Start(date(2021, 3, 1), time.Monday), date(2021, 3, 1))
+	assert.Equal(t, JournalMonthly.Start(now, time.Monday), date(2021, 3, 1))
+
+	// Custom week start.
+	assert.Equal(t, JournalWeekly.Start(now, time.Sunday), date(2021, 3, 14))
+	assert.Equal(t, JournalWeekly.Start(date(2021, 3, 14), time.Sunday), date(2021, 3, 14))
+	assert.Equal(t, JournalWeekly.Start(date(2021, 3, 19), time.Saturday), date(2021, 3, 13))
+	assert.Equal(t, JournalWeekly.Add(now, 1, time.Sunday), date(2021, 3, 21))
+}
+
+func TestJournalPeriodAdd(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	now := time.Date(2021, 3, 31, 15, 42, 2, 0, time.UTC)
+
+	assert.Equal(t, JournalDaily.Add(now, 1, time.Monday), date(2021, 4, 1))
+	assert.Equal(t, JournalDaily.Add(now, -1, time.Monday), date(2021, 3, 30))
+	assert.Equal(t, JournalWeekly.Add(now, 1, time.Monday), date(2021, 4, 5))
+	assert.Equal(t, JournalWeekly.Add(now, -1, time.Monday), date(2021, 3, 22))
+	// Months are not overflowing from the 31th.
+	assert.Equal(t, JournalMonthly.Add(now, 1, time.Monday), date(2021, 4, 1))
+	assert.Equal(t, JournalMonthly.Add(now, -1, time.Monday), date(2021, 2, 1))
+}
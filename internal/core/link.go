@@ -26,6 +26,9 @@ type Link struct {
 	SnippetStart int `json:"snippetStart"`
 	// End byte offset of the snippet in the note content.
 	SnippetEnd int `json:"snippetEnd"`
+	// Path relative to the notebook root of the local file targeted by the
+	// link, when it is not a note, e.g. an image.
+	AssetPath string `json:"-"`
 }
 
 // ResolvedLink represents a link between two indexed notes.
@@ -45,6 +48,7 @@ const (
 	LinkTypeImplicit LinkType = "implicit" // No markup, e.g. http://example.com
 	LinkTypeMarkdown LinkType = "markdown"
 	LinkTypeWikiLink LinkType = "wiki-link"
+	LinkTypeImage    LinkType = "image" // Embedded image, e.g. ![](cat.png)
 )
 
 // LinkRelation defines the relationship between a link's source and target.
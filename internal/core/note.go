@@ -47,12 +47,21 @@ type Note struct {
 	Links []Link
 	// List of tags found in the content.
 	Tags []string
+	// List of task list items found in the content.
+	Tasks []Task
+	// List of flashcards found in the content.
+	Cards []Card
 	// JSON dictionary of raw metadata extracted from the frontmatter.
 	Metadata map[string]interface{}
 	// Date of creation.
 	Created time.Time
-	// Date of last modification.
+	// Date of last modification. When indexing, this is the date of the file
+	// which is used to detect the changes.
 	Modified time.Time
+	// Date of the last revision of the note in the notebook history, when
+	// the dates are read from git. It replaces Modified in the notes found
+	// in the index.
+	HistoryModified *time.Time
 	// Checksum of the note content.
 	Checksum string
 }
@@ -86,4 +95,7 @@ type ContextualNote struct {
 	Note
 	// List of context-sensitive excerpts from the note.
 	Snippets []string
+	// Similarity score with the notes given in NoteFindOpts.SimilarTo,
+	// between 0 and 1.
+	Score float64
 }
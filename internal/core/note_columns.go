@@ -0,0 +1,133 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// NoteColumns lists the columns available to print notes as records, e.g. in
+// a table. The frontmatter metadata are available with the meta. prefix,
+// e.g. meta.status.
+var NoteColumns = []string{
+	"filename", "filename-stem", "path", "abs-path", "title", "link", "lead",
+	"body", "snippets", "raw-content", "word-count", "tags", "created",
+	"modified", "checksum", "notebook", "score",
+}
+
+// DefaultNoteColumns are the columns printed when none are given.
+var DefaultNoteColumns = []string{"title", "path", "tags", "created"}
+
+// NoteColumnsFormatter returns the values of a set of columns for a note, to
+// print it as a record.
+type NoteColumnsFormatter func(note ContextualNote) ([]interface{}, error)
+
+// NewNoteColumnsFormatter returns a NoteColumnsFormatter used to print the
+// given columns of notes.
+func (n *Notebook) NewNoteColumnsFormatter(notebookName string, columns []string) (NoteColumnsFormatter, error) {
+	err := ValidateNoteColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	linkFormatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return nil, err
+	}
+	env := n.osEnv()
+
+	return func(note ContextualNote) ([]interface{}, error) {
+		snippets := make([]string, 0, len(note.Snippets))
+		for _, snippet := range note.Snippets {
+			snippets = append(snippets, noteTermRegex.ReplaceAllString(snippet, "$1"))
+		}
+		note.Snippets = snippets
+
+		context, err := newNoteFormatRenderContext(note, n.Path, notebookName, linkFormatter, env, n.fs)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, context.column(column))
+		}
+		return values, nil
+	}, nil
+}
+
+// ValidateNoteColumns returns an error if one of the given columns is not
+// available for notes.
+func ValidateNoteColumns(columns []string) error {
+	for _, column := range columns {
+		if key, ok := metadataColumnKey(column); ok {
+			if key == "" {
+				return fmt.Errorf("%s: missing metadata key in column", column)
+			}
+			continue
+		}
+		if !strutil.Contains(NoteColumns, column) {
+			return fmt.Errorf("%s: unknown column, expected one of: %s or meta.<key>", column, strings.Join(NoteColumns, ", "))
+		}
+	}
+	return nil
+}
+
+// column returns the value of the column with the given name, which must be
+// valid.
+func (c noteFormatRenderContext) column(name string) interface{} {
+	if key, ok := metadataColumnKey(name); ok {
+		// YAML keys are normalized to lower case.
+		return c.Metadata[strings.ToLower(key)]
+	}
+
+	switch name {
+	case "filename":
+		return c.Filename
+	case "filename-stem":
+		return c.FilenameStem
+	case "path":
+		return c.Path
+	case "abs-path":
+		return c.AbsPath
+	case "title":
+		return c.Title
+	case "link":
+		return c.Link.String()
+	case "lead":
+		return c.Lead
+	case "body":
+		return c.Body
+	case "snippets":
+		return c.Snippets
+	case "raw-content":
+		return c.RawContent
+	case "word-count":
+		return c.WordCount
+	case "tags":
+		return c.Tags
+	case "created":
+		return c.Created
+	case "modified":
+		return c.Modified
+	case "checksum":
+		return c.Checksum
+	case "notebook":
+		return c.Notebook
+	case "score":
+		return c.Score
+	default:
+		return nil
+	}
+}
+
+// metadataColumnKey returns the frontmatter key of a meta.<key> column.
+func metadataColumnKey(column string) (string, bool) {
+	for _, prefix := range []string{"meta.", "metadata."} {
+		if strings.HasPrefix(column, prefix) {
+			return strings.TrimPrefix(column, prefix), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestValidateNoteColumns(t *testing.T) {
+	assert.Nil(t, ValidateNoteColumns([]string{"title", "path", "tags", "meta.status", "metadata.author"}))
+	assert.Err(t, ValidateNoteColumns([]string{"title", "foo"}), "foo: unknown column")
+	assert.Err(t, ValidateNoteColumns([]string{"meta."}), "meta.: missing metadata key in column")
+}
+
+func TestNewNoteColumnsFormatter(t *testing.T) {
+	test := formatTest{rootDir: "/notebook", workingDir: "/notebook/dir"}
+	test.setup()
+	notebook := NewNotebook(test.rootDir, test.config, NotebookPorts{
+		TemplateLoaderFactory: func(config NoteConfig) (TemplateLoader, error) {
+			return test.templateLoader, nil
+		},
+		FS: test.fs,
+		OSEnv: func() map[string]string {
+			return map[string]string{}
+		},
+	})
+
+	created := time.Date(2009, 1, 17, 20, 34, 58, 0, time.UTC)
+
+	formatter, err := notebook.NewNoteColumnsFormatter("", []string{"title", "path", "tags", "created", "word-count", "meta.status", "meta.missing", "snippets"})
+	assert.Nil(t, err)
+
+	res, err := formatter(ContextualNote{
+		Note: Note{
+			Path:      "dir/note1.md",
+			Title:     "Note 1",
+			WordCount: 12,
+			Tags:      []string{"tag1", "tag2"},
+			Metadata: map[string]interface{}{
+				"status": "draft",
+			},
+			Created: created,
+		},
+		Snippets: []string{"a <zk:match>term</zk:match>"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, res, []interface{}{
+		"Note 1", "note1.md", []string{"tag1", "tag2"}, created, 12, "draft", nil, []string{"a term"},
+	})
+
+	_, err = notebook.NewNoteColumnsFormatter("", []string{"unknown"})
+	assert.Err(t, err, "unknown: unknown column")
+}
@@ -24,8 +24,14 @@ type NoteFindOpts struct {
 	IncludeIDs []NoteID
 	// Filter excluding notes with the given IDs.
 	ExcludeIDs []NoteID
+	// Filter the notes found in the version history, e.g. changed since a
+	// revision. Unlike IncludeIDs, it is combined with the other filters.
+	HistoryIDs []NoteID
 	// Filter by tags found in the notes.
 	Tags []string
+	// Filter the notes tagged with any of the given tag names, which are
+	// matched literally.
+	AnyTags []string
 	// Filter the notes mentioning the given ones.
 	Mention []string
 	// Filter the notes mentioned by the given ones.
@@ -36,6 +42,8 @@ type NoteFindOpts struct {
 	LinkTo *LinkFilter
 	// Filter to select notes which could might be related to the given notes hrefs.
 	Related []string
+	// Filter to select notes with a content similar to the given notes hrefs.
+	SimilarTo []string
 	// Filter to select notes having no other notes linking to them.
 	Orphan bool
 	// Filter to select notes having no tags.
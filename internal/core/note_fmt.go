@@ -0,0 +1,434 @@
+package core
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	strutil "github.com/zk-org/zk/internal/util/strings"
+)
+
+// FormatNote normalizes the content of the note at the given path, relative
+// to the notebook root, according to the notebook configuration:
+//
+//   - the frontmatter keys are lowercased and sorted,
+//   - the trailing whitespaces are removed, except hard line breaks,
+//   - the internal links are converted to the configured link format,
+//   - the tags are converted to the enabled tag syntax.
+//
+// The code blocks are left untouched.
+func (n *Notebook) FormatNote(path string, content string) (string, error) {
+	wrap := errors.Wrapperf("%s: failed to format the note", path)
+
+	content = formatFrontmatter(content)
+	content = trimTrailingWhitespaces(content)
+
+	content, err := n.formatLinks(path, content)
+	if err != nil {
+		return "", wrap(err)
+	}
+	content, err = n.formatTags(content)
+	if err != nil {
+		return "", wrap(err)
+	}
+	return content, nil
+}
+
+// topLevelKeyRegex matches the line of any top-level frontmatter key.
+var topLevelKeyRegex = regexp.MustCompile(`^([^\s#:\-"'][^:\n]*?):(?:[ \t\n]|$)`)
+
+// frontmatterEntry is a top-level key of the frontmatter, with its value and
+// the comments preceding it.
+type frontmatterEntry struct {
+	key     string
+	keyLine int
+	lines   []string
+}
+
+// formatFrontmatter lowercases and sorts the top-level keys of the
+// frontmatter. The comments stay attached to the following key. The
+// frontmatter is left untouched if its structure is not understood.
+func formatFrontmatter(content string) string {
+	start, end, ok := frontmatterYAML(content)
+	if !ok || start == end {
+		return content
+	}
+	yaml := content[start:end]
+
+	entries := []*frontmatterEntry{}
+	var entry *frontmatterEntry
+	pending := []string{} // Comment lines preceding the next key.
+	blanks := []string{}  // Blank lines, kept only inside a value.
+
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(yaml, "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\n") + "\n"
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			blanks = append(blanks, line)
+
+		case strings.HasPrefix(line, "#"):
+			blanks = []string{}
+			pending = append(pending, line)
+
+		case line[0] == ' ' || line[0] == '\t' || trimmed == "-" || strings.HasPrefix(line, "- "):
+			if entry == nil {
+				return content
+			}
+			entry.lines = append(entry.lines, pending...)
+			entry.lines = append(entry.lines, blanks...)
+			entry.lines = append(entry.lines, line)
+			pending = []string{}
+			blanks = []string{}
+
+		default:
+			match := topLevelKeyRegex.FindStringSubmatch(line)
+			if match == nil {
+				return content
+			}
+			entry = &frontmatterEntry{
+				key:     match[1],
+				keyLine: len(pending),
+				lines:   append(pending, line),
+			}
+			entries = append(entries, entry)
+			pending = []string{}
+			blanks = []string{}
+		}
+	}
+
+	// Keep the original case when lowercasing would merge two keys.
+	keys := map[string]int{}
+	for _, entry := range entries {
+		keys[strings.ToLower(entry.key)]++
+	}
+	for _, entry := range entries {
+		if key := strings.ToLower(entry.key); keys[key] == 1 {
+			entry.lines[entry.keyLine] = key + entry.lines[entry.keyLine][len(key):]
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].key) < strings.ToLower(entries[j].key)
+	})
+
+	res := ""
+	for _, entry := range entries {
+		res += strings.Join(entry.lines, "")
+	}
+	res += strings.Join(pending, "")
+
+	return content[:start] + res + content[end:]
+}
+
+// trailingWhitespaceLength returns the number of trailing whitespace
+// characters to remove from the given line. Two trailing spaces or more after
+// some text are a Markdown hard line break, and are kept.
+func trailingWhitespaceLength(line string) int {
+	line = strings.TrimSuffix(line, "\r")
+	trimmed := strings.TrimRight(line, " \t")
+	trailing := line[len(trimmed):]
+	if trimmed != "" && len(trailing) >= 2 && strings.Trim(trailing, " ") == "" {
+		return 0
+	}
+	return len(trailing)
+}
+
+// trimTrailingWhitespaces removes the trailing whitespaces of the lines
+// outside code blocks.
+func trimTrailingWhitespaces(content string) string {
+	lines := strings.Split(content, "\n")
+	fenced := fencedLines(lines)
+	for i, line := range lines {
+		if fenced[i] {
+			continue
+		}
+		if length := trailingWhitespaceLength(line); length > 0 {
+			cr := ""
+			if strings.HasSuffix(line, "\r") {
+				cr = "\r"
+			}
+			line = strings.TrimSuffix(line, "\r")
+			lines[i] = line[:len(line)-length] + cr
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fencedLines returns whether each line belongs to a fenced code block,
+// including the fences.
+func fencedLines(lines []string) []bool {
+	res := make([]bool, len(lines))
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			res[i] = true
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		for _, marker := range []string{"```", "~~~"} {
+			if strings.HasPrefix(trimmed, marker) {
+				fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, marker[:1]))]
+				res[i] = true
+				break
+			}
+		}
+	}
+	return res
+}
+
+// codeSpanRegex matches the inline code spans.
+var codeSpanRegex = regexp.MustCompile("(`+)[^`\n]*?`+")
+
+// replaceInBody replaces the matches of regex in the body of the note, ignoring
+// the frontmatter and the code. The first submatch of regex is the character
+// preceding the actual match, if any.
+//
+// The replacement function receives the content and the submatch indexes of
+// the match, and returns the replacement text or ok=false to keep the match.
+func replaceInBody(content string, regex *regexp.Regexp, repl func(content string, loc []int) (string, bool, error)) (string, error) {
+	bodyStart := 0
+	if match := frontmatterRegex.FindStringIndex(content); match != nil {
+		bodyStart = match[1]
+	}
+
+	// Byte ranges of the code.
+	code := [][]int{}
+	offset := 0
+	lines := strings.SplitAfter(content, "\n")
+	fenced := fencedLines(lines)
+	for i, line := range lines {
+		if fenced[i] {
+			code = append(code, []int{offset, offset + len(line)})
+		}
+		offset += len(line)
+	}
+	code = append(code, codeSpanRegex.FindAllStringIndex(content, -1)...)
+
+	isCode := func(start int, end int) bool {
+		for _, r := range code {
+			if start < r[1] && r[0] < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	res := ""
+	last := 0
+	for _, loc := range regex.FindAllStringSubmatchIndex(content, -1) {
+		// Ignore the preceding character.
+		start := loc[3]
+		if start < bodyStart || isCode(start, loc[1]) {
+			continue
+		}
+		replacement, ok, err := repl(content, loc)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		res += content[last:loc[0]] + replacement
+		last = loc[1]
+	}
+	return res + content[last:], nil
+}
+
+// submatch returns the i-th submatch of loc, or an empty string.
+func submatch(content string, loc []int, i int) string {
+	if loc[2*i] < 0 {
+		return ""
+	}
+	return content[loc[2*i]:loc[2*i+1]]
+}
+
+var (
+	// fmtWikiLinkRegex matches wiki links [[href|label]], except Neuron's
+	// folgezettel links [[[href]]].
+	fmtWikiLinkRegex = regexp.MustCompile(`(^|[^\[])\[\[([^\[\]|#]+?)(?:\|([^\[\]]*?))?\]\]`)
+	// fmtMarkdownLinkRegex matches inline Markdown links [label](href), except
+	// images and links with a title.
+	fmtMarkdownLinkRegex = regexp.MustCompile(`(^|[^!\\])\[([^\[\]]*)\]\(\s*<?([^\s()<>]+)>?\s*\)`)
+)
+
+// formatLinks converts the internal links between notes to the configured
+// link format. Links to unknown notes and custom link formats are left
+// untouched.
+func (n *Notebook) formatLinks(path string, content string) (string, error) {
+	isWikiLink := false
+	switch n.Config.Format.Markdown.LinkFormat {
+	case "wiki":
+		isWikiLink = false
+	case "markdown", "":
+		isWikiLink = true
+	default:
+		return content, nil
+	}
+
+	regex := fmtMarkdownLinkRegex
+	if isWikiLink {
+		regex = fmtWikiLinkRegex
+	}
+
+	formatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(path)
+
+	return replaceInBody(content, regex, func(content string, loc []int) (string, bool, error) {
+		prefix := submatch(content, loc, 1)
+		var href, label string
+		var target *MinimalNote
+		var err error
+
+		if isWikiLink {
+			href = strings.TrimSpace(submatch(content, loc, 2))
+			label = strings.TrimSpace(submatch(content, loc, 3))
+			if strings.Contains(href, ":") {
+				// Link to another notebook.
+				return "", false, nil
+			}
+			target, err = n.FindByHref(href, true)
+
+		} else {
+			label = submatch(content, loc, 2)
+			href = submatch(content, loc, 3)
+			if strutil.IsURL(href) || strings.ContainsAny(href, "#?") {
+				return "", false, nil
+			}
+			href, err = url.PathUnescape(href)
+			if err != nil {
+				return "", false, nil
+			}
+			href = filepath.Join(dir, href)
+			if strings.HasPrefix(href, "..") {
+				return "", false, nil
+			}
+			target, err = n.FindByHref(href, false)
+		}
+		if err != nil || target == nil {
+			return "", false, err
+		}
+
+		title := label
+		if title == "" {
+			title = target.Title
+		}
+		context, err := NewLinkFormatterContext(NotebookPath{
+			Path:       target.Path,
+			BasePath:   n.Path,
+			WorkingDir: filepath.Join(n.Path, dir),
+		}, title, target.Metadata)
+		if err != nil {
+			return "", false, err
+		}
+		link, err := formatter(context)
+		if err != nil {
+			return "", false, err
+		}
+
+		// Wiki links don't hold the title of the note, keep the custom label.
+		if label != "" && !strings.EqualFold(label, target.Title) && strings.HasPrefix(link, "[[") && strings.HasSuffix(link, "]]") {
+			link = strings.TrimSuffix(link, "]]") + "|" + label + "]]"
+		}
+
+		return prefix + link, true, nil
+	})
+}
+
+const tagChars = `\p{L}\p{N}/@'~\-_$%&+=`
+
+var (
+	// fmtHashtagRegex matches a #hashtag.
+	fmtHashtagRegex = regexp.MustCompile(`(^|[^` + tagChars + `#:\\])#([` + tagChars + `]+)`)
+	// fmtColontagsRegex matches a group of :colon:tags:.
+	fmtColontagsRegex = regexp.MustCompile(`(^|[^` + tagChars + `:\\])((?::[` + tagChars + `]+)+:)`)
+	// tagCharRegex matches a tag character at the start of the text.
+	tagCharRegex = regexp.MustCompile(`^[` + tagChars + `:#]`)
+)
+
+// formatTags converts the tags to the enabled tag syntax, when only one of the
+// #hashtags and :colon:tags: is enabled.
+//
+// As the disabled syntax is not parsed, only the tags already known in the
+// notebook are converted, to avoid changing unrelated text such as emoji
+// shortcodes.
+func (n *Notebook) formatTags(content string) (string, error) {
+	config := n.Config.Format.Markdown
+	if config.Hashtags == config.ColonTags {
+		return content, nil
+	}
+
+	collections, err := n.FindCollections(CollectionKindTag, nil)
+	if err != nil {
+		return "", err
+	}
+	knownTags := map[string]bool{}
+	for _, collection := range collections {
+		knownTags[collection.Name] = true
+	}
+
+	// isTagEnd returns whether the tags matched by loc are not followed by
+	// another tag character.
+	isTagEnd := func(content string, loc []int) bool {
+		return !tagCharRegex.MatchString(content[loc[1]:])
+	}
+
+	if config.Hashtags {
+		return replaceInBody(content, fmtColontagsRegex, func(content string, loc []int) (string, bool, error) {
+			if !isTagEnd(content, loc) {
+				return "", false, nil
+			}
+			tags := strings.Split(strings.Trim(submatch(content, loc, 2), ":"), ":")
+			hashtags := make([]string, 0, len(tags))
+			for _, tag := range tags {
+				if !knownTags[tag] {
+					return "", false, nil
+				}
+				hashtags = append(hashtags, "#"+tag)
+			}
+			return submatch(content, loc, 1) + strings.Join(hashtags, " "), true, nil
+		})
+
+	} else {
+		return replaceInBody(content, fmtHashtagRegex, func(content string, loc []int) (string, bool, error) {
+			tag := submatch(content, loc, 2)
+			if !knownTags[tag] || !isTagEnd(content, loc) {
+				return "", false, nil
+			}
+			return submatch(content, loc, 1) + ":" + tag + ":", true, nil
+		})
+	}
+}
+
+// FormatNoteAt formats the note at the given path, relative to the notebook
+// root, and returns whether its content changed. The note is saved only when
+// write is true. The index is not updated, use IndexPaths with the modified
+// notes.
+func (n *Notebook) FormatNoteAt(path string, write bool) (bool, error) {
+	absPath := filepath.Join(n.Path, path)
+	content, err := n.ReadNote(absPath)
+	if err != nil {
+		return false, err
+	}
+	res, err := n.FormatNote(path, string(content))
+	if err != nil {
+		return false, err
+	}
+	if res == string(content) {
+		return false, nil
+	}
+	if write {
+		err = n.WriteNote(absPath, []byte(res))
+	}
+	return true, err
+}
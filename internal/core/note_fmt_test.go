@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestFormatFrontmatter(t *testing.T) {
+	test := func(content string, expected string) {
+		t.Helper()
+		assert.Equal(t, formatFrontmatter(content), expected)
+	}
+
+	test("# Title\n", "# Title\n")
+	test("---\n---\n# Title\n", "---\n---\n# Title\n")
+	// Lowercases and sorts the keys.
+	test("---\nTitle: Title\ndate: 2021\nTags: [a]\n---\nBody", "---\ndate: 2021\ntags: [a]\ntitle: Title\n---\nBody")
+	// Keeps the comments, nested values and blank lines inside values.
+	test(
+		"---\n# The title\ntitle: Title\n\nextra:\n  b: 1\n\n  a: 2\naliases:\n- a\n- b\n# Footer\n---\n",
+		"---\naliases:\n- a\n- b\nextra:\n  b: 1\n\n  a: 2\n# The title\ntitle: Title\n# Footer\n---\n",
+	)
+	// Keeps the case of keys conflicting when lowercased.
+	test("---\nb: 1\nA: 1\na: 2\n---\n", "---\nA: 1\na: 2\nb: 1\n---\n")
+	// Unsupported structure.
+	test("---\n  indented: 1\n---\n", "---\n  indented: 1\n---\n")
+	test("---\n{a: 1}\n---\n", "---\n{a: 1}\n---\n")
+}
+
+func TestTrimTrailingWhitespaces(t *testing.T) {
+	test := func(content string, expected string) {
+		t.Helper()
+		assert.Equal(t, trimTrailingWhitespaces(content), expected)
+	}
+
+	test("a \nb\t\n \n", "a\nb\n\n")
+	test("a\r\nb \r\n", "a\r\nb\r\n")
+	// Hard line breaks.
+	test("a  \nb   \n", "a  \nb   \n")
+	test("a \t \n", "a\n")
+	// Code blocks.
+	test("```\na \n```\nb \n", "```\na \n```\nb\n")
+}
+
+func TestReplaceInBody(t *testing.T) {
+	test := func(content string, expected string) {
+		t.Helper()
+		actual, err := replaceInBody(content, fmtHashtagRegex, func(content string, loc []int) (string, bool, error) {
+			tag := submatch(content, loc, 2)
+			return submatch(content, loc, 1) + ":" + tag + ":", tag != "skip", nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("#a b #c", ":a: b :c:")
+	test("#skip #a", "#skip :a:")
+	test("a#b C# #1a", "a#b C# :1a:")
+	test("---\ntags: #a\n---\n#b", "---\ntags: #a\n---\n:b:")
+	test("`#a` #b\n```\n#c\n```\n#d", "`#a` :b:\n```\n#c\n```\n:d:")
+}
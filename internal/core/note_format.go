@@ -10,54 +10,77 @@ import (
 // NoteFormatter formats notes to be printed on the screen.
 type NoteFormatter func(note ContextualNote) (string, error)
 
-func newNoteFormatter(basePath string, template Template, linkFormatter LinkFormatter, env map[string]string, fs FileStorage) (NoteFormatter, error) {
+func newNoteFormatter(basePath string, notebookName string, template Template, linkFormatter LinkFormatter, env map[string]string, fs FileStorage) (NoteFormatter, error) {
 	termRepl, err := template.Styler().Style("$1", StyleTerm)
 	if err != nil {
 		return nil, err
 	}
 
 	return func(note ContextualNote) (string, error) {
-		path := NotebookPath{
-			Path:       note.Path,
-			BasePath:   basePath,
-			WorkingDir: fs.WorkingDir(),
+		snippets := make([]string, 0)
+		for _, snippet := range note.Snippets {
+			snippets = append(snippets, noteTermRegex.ReplaceAllString(snippet, termRepl))
 		}
-		relPath, err := path.PathRelToWorkingDir()
+		note.Snippets = snippets
+
+		context, err := newNoteFormatRenderContext(note, basePath, notebookName, linkFormatter, env, fs)
 		if err != nil {
 			return "", err
 		}
+		return template.Render(context)
+	}, nil
+}
 
-		snippets := make([]string, 0)
-		for _, snippet := range note.Snippets {
-			snippets = append(snippets, noteTermRegex.ReplaceAllString(snippet, termRepl))
-		}
+// newNoteFormatRenderContext returns the variables describing the given note
+// in the note formatting templates.
+func newNoteFormatRenderContext(note ContextualNote, basePath string, notebookName string, linkFormatter LinkFormatter, env map[string]string, fs FileStorage) (noteFormatRenderContext, error) {
+	path := NotebookPath{
+		Path:       note.Path,
+		BasePath:   basePath,
+		WorkingDir: fs.WorkingDir(),
+	}
+	relPath, err := path.PathRelToWorkingDir()
+	if err != nil {
+		return noteFormatRenderContext{}, err
+	}
+	// Notes from other notebooks are prefixed with the notebook alias, the
+	// absolute path is still available with abs-path.
+	if notebookName != "" {
+		relPath = notebookName + ":" + note.Path
+	}
+
+	snippets := note.Snippets
+	if snippets == nil {
+		snippets = []string{}
+	}
 
-		return template.Render(noteFormatRenderContext{
-			Filename:     note.Filename(),
-			FilenameStem: note.FilenameStem(),
-			Path:         relPath,
-			AbsPath:      path.AbsPath(),
-			Title:        note.Title,
-			Link: newLazyStringer(func() string {
-				context, err := NewLinkFormatterContext(path, note.Title, note.Metadata)
-				if err != nil {
-					return ""
-				}
-				link, _ := linkFormatter(context)
-				return link
-			}),
-			Lead:       note.Lead,
-			Body:       note.Body,
-			Snippets:   snippets,
-			Tags:       note.Tags,
-			RawContent: note.RawContent,
-			WordCount:  note.WordCount,
-			Metadata:   note.Metadata,
-			Created:    note.Created,
-			Modified:   note.Modified,
-			Checksum:   note.Checksum,
-			Env:        env,
-		})
+	return noteFormatRenderContext{
+		Filename:     note.Filename(),
+		FilenameStem: note.FilenameStem(),
+		Path:         relPath,
+		AbsPath:      path.AbsPath(),
+		Title:        note.Title,
+		Link: newLazyStringer(func() string {
+			context, err := NewLinkFormatterContext(path, note.Title, note.Metadata)
+			if err != nil {
+				return ""
+			}
+			link, _ := linkFormatter(context)
+			return link
+		}),
+		Lead:       note.Lead,
+		Body:       note.Body,
+		Snippets:   snippets,
+		Tags:       note.Tags,
+		RawContent: note.RawContent,
+		WordCount:  note.WordCount,
+		Metadata:   note.Metadata,
+		Created:    note.Created,
+		Modified:   note.Modified,
+		Checksum:   note.Checksum,
+		Env:        env,
+		Notebook:   notebookName,
+		Score:      note.Score,
 	}, nil
 }
 
@@ -83,6 +106,8 @@ type noteFormatRenderContext struct {
 	Modified     time.Time              `json:"modified"`
 	Checksum     string                 `json:"checksum"`
 	Env          map[string]string      `json:"-"`
+	Notebook     string                 `json:"notebook,omitempty"`
+	Score        float64                `json:"score,omitempty"`
 }
 
 func (c noteFormatRenderContext) Equal(other noteFormatRenderContext) bool {
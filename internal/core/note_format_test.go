@@ -149,6 +149,32 @@ func TestNoteFormatterMakesPathRelative(t *testing.T) {
 	test("/abs/zk", "/abs", "dir/note.md", "zk/dir/note.md", "/abs/zk/dir/note.md")
 }
 
+func TestNoteFormatterPrefixesPathsFromOtherNotebooks(t *testing.T) {
+	test := formatTest{
+		rootDir:      "/abs/work",
+		workingDir:   "/abs/zk",
+		notebookName: "work",
+	}
+	test.setup()
+	formatter, err := test.run("format")
+	assert.Nil(t, err)
+	_, err = formatter(ContextualNote{
+		Note: Note{Path: "dir/note.md"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, test.template.Contexts, []interface{}{
+		noteFormatRenderContext{
+			Filename:     "note.md",
+			FilenameStem: "note",
+			Path:         "work:dir/note.md",
+			AbsPath:      "/abs/work/dir/note.md",
+			Link:         opt.NewString("[](../work/dir/note)"),
+			Snippets:     []string{},
+			Notebook:     "work",
+		},
+	})
+}
+
 func TestNoteFormatterStylesSnippetTerm(t *testing.T) {
 	test := func(snippet string, expected string) {
 		test := formatTest{}
@@ -182,6 +208,7 @@ type formatTest struct {
 	format         string
 	rootDir        string
 	workingDir     string
+	notebookName   string
 	fs             *fileStorageMock
 	config         Config
 	templateLoader *templateLoaderMock
@@ -211,8 +238,8 @@ func (t *formatTest) setup() {
 
 func (t *formatTest) run(format string) (NoteFormatter, error) {
 	notebook := NewNotebook(t.rootDir, t.config, NotebookPorts{
-		TemplateLoaderFactory: func(language string) (TemplateLoader, error) {
-			t.receivedLang = language
+		TemplateLoaderFactory: func(config NoteConfig) (TemplateLoader, error) {
+			t.receivedLang = config.Lang
 			return t.templateLoader, nil
 		},
 		FS: t.fs,
@@ -221,5 +248,5 @@ func (t *formatTest) run(format string) (NoteFormatter, error) {
 		},
 	})
 
-	return notebook.NewNoteFormatter(format)
+	return notebook.NewNamedNoteFormatter(t.notebookName, format)
 }
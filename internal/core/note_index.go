@@ -30,6 +30,24 @@ type NoteIndex interface {
 	// FindCollections retrieves all the collections of the given kind.
 	FindCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error)
 
+	// FindTasks retrieves the tasks matching the given filtering options.
+	FindTasks(opts TaskFindOpts) ([]NoteTask, error)
+
+	// FindCards retrieves the flashcards matching the given filtering options,
+	// with their review schedule.
+	FindCards(opts CardFindOpts) ([]NoteCard, error)
+
+	// SaveCardSchedule persists the review schedule of the card with the given
+	// hash.
+	SaveCardSchedule(hash string, schedule CardSchedule) error
+
+	// FindAssetLinks retrieves the links targeting local files which are not
+	// notes.
+	FindAssetLinks() ([]AssetLink, error)
+
+	// Stats computes the statistics of the notes matching the given options.
+	Stats(opts StatsOpts) (NotebookStats, error)
+
 	// Indexed returns the list of indexed note file metadata.
 	IndexedPaths() (<-chan paths.Metadata, error)
 	// Add indexes a new note.
@@ -46,6 +64,12 @@ type NoteIndex interface {
 	NeedsReindexing() (bool, error)
 	// SetNeedsReindexing indicates whether all notes should be reindexed.
 	SetNeedsReindexing(needsReindexing bool) error
+
+	// Metadata returns the value of a notebook-wide metadata key, or an empty
+	// string if it is not set.
+	Metadata(key string) (string, error)
+	// SetMetadata persists the value of a notebook-wide metadata key.
+	SetMetadata(key string, value string) error
 }
 
 // NoteIndexingStats holds statistics about a notebook indexing process.
@@ -62,6 +86,12 @@ type NoteIndexingStats struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// HasChanges returns whether notes were added, modified or removed from the
+// index.
+func (s NoteIndexingStats) HasChanges() bool {
+	return s.AddedCount > 0 || s.ModifiedCount > 0 || s.RemovedCount > 0
+}
+
 // String implements Stringer
 func (s NoteIndexingStats) String() string {
 	return fmt.Sprintf(`Indexed %d %v in %v
@@ -89,8 +119,14 @@ type indexTask struct {
 	force   bool
 	verbose bool
 	index   NoteIndex
-	parser  NoteParser
-	logger  util.Logger
+	// newParser returns the parser of the notes at the given paths, which
+	// are known once the changes are collected.
+	newParser func(paths []string) NoteParser
+	fs        FileStorage
+	logger    util.Logger
+	// Paths of the notes to update, relative to the notebook root. When nil,
+	// the whole notebook is walked.
+	paths []string
 }
 
 func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexingStats, error) {
@@ -99,9 +135,14 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 	stats := NoteIndexingStats{}
 	startTime := time.Now()
 
-	needsReindexing, err := t.index.NeedsReindexing()
-	if err != nil {
-		return stats, wrap(err)
+	// A partial indexing doesn't clear the need for a full reindexing.
+	needsReindexing := false
+	if t.paths == nil {
+		var err error
+		needsReindexing, err = t.index.NeedsReindexing()
+		if err != nil {
+			return stats, wrap(err)
+		}
 	}
 
 	print := func(message string) {
@@ -126,40 +167,51 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 			})
 		}
 
-		group, err := t.config.GroupConfigForPath(path)
-		if err != nil {
-			return true, err
+		ignored, reason, err := isIgnoredNotePath(t.config, path)
+		if ignored && reason != "" {
+			notifyIgnored(reason)
 		}
+		return ignored, err
+	}
 
-		if filepath.Ext(path) != "."+group.Note.Extension {
-			notifyIgnored("expected extension \"" + group.Note.Extension + "\"")
-			return true, nil
-		}
+	// The changes are collected before being indexed, to parse only the
+	// changed notes.
+	changes := []paths.DiffChange{}
+	onChange := func(change paths.DiffChange) error {
+		changes = append(changes, change)
+		return nil
+	}
 
-		for _, ignoreGlob := range group.ExcludeGlobs() {
-			matches, err := doublestar.PathMatch(ignoreGlob, path)
-			if err != nil {
-				return true, errors.Wrapf(err, "failed to match exclude glob %s to %s", ignoreGlob, path)
-			}
-			if matches {
-				notifyIgnored("matched exclude glob \"" + ignoreGlob + "\"")
-				return true, nil
-			}
+	var count int
+	var err error
+	if t.paths != nil {
+		count, err = t.diffPaths(shouldIgnorePath, onChange)
+	} else {
+		notebookPath := &NotebookPath{Path: t.path}
+		source := paths.Walk(t.path, t.logger, notebookPath.Filename(), shouldIgnorePath)
+
+		var target <-chan paths.Metadata
+		target, err = t.index.IndexedPaths()
+		if err != nil {
+			return stats, wrap(err)
 		}
 
-		return false, nil
+		// FIXME: Use the FS?
+		count, err = paths.Diff(source, target, force, onChange)
 	}
-
-	notebookPath := &NotebookPath{Path: t.path}
-	source := paths.Walk(t.path, t.logger, notebookPath.Filename(), shouldIgnorePath)
-
-	target, err := t.index.IndexedPaths()
 	if err != nil {
 		return stats, wrap(err)
 	}
 
-	// FIXME: Use the FS?
-	count, err := paths.Diff(source, target, force, func(change paths.DiffChange) error {
+	changedPaths := []string{}
+	for _, change := range changes {
+		if change.Kind != paths.DiffRemoved {
+			changedPaths = append(changedPaths, change.Path)
+		}
+	}
+	parser := t.newParser(changedPaths)
+
+	for _, change := range changes {
 		callback(change)
 		print("- " + change.Kind.String() + " " + change.Path)
 		absPath := filepath.Join(t.path, change.Path)
@@ -167,7 +219,7 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 		switch change.Kind {
 		case paths.DiffAdded:
 			stats.AddedCount += 1
-			note, err := t.parser.ParseNoteAt(absPath)
+			note, err := parser.ParseNoteAt(absPath)
 			if note != nil {
 				_, err = t.index.Add(*note)
 			}
@@ -175,7 +227,7 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 
 		case paths.DiffModified:
 			stats.ModifiedCount += 1
-			note, err := t.parser.ParseNoteAt(absPath)
+			note, err := parser.ParseNoteAt(absPath)
 			if note != nil {
 				err = t.index.Update(*note)
 			}
@@ -186,9 +238,7 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 			err := t.index.Remove(change.Path)
 			t.logger.Err(err)
 		}
-
-		return nil
-	})
+	}
 
 	for _, ignored := range ignoredFiles {
 		print("- ignored " + ignored.Path + ": " + ignored.Reason)
@@ -204,3 +254,84 @@ func (t *indexTask) execute(callback func(change paths.DiffChange)) (NoteIndexin
 	print("")
 	return stats, wrap(err)
 }
+
+// diffPaths reports the changes of the task paths only, without walking the
+// whole notebook. Existing notes are always considered modified.
+func (t *indexTask) diffPaths(shouldIgnorePath func(path string) (bool, error), callback func(change paths.DiffChange) error) (int, error) {
+	count := 0
+	for _, path := range t.paths {
+		if path == "" || isHiddenPath(path) {
+			continue
+		}
+		ignored, err := shouldIgnorePath(path)
+		if err != nil {
+			t.logger.Err(err)
+			continue
+		}
+		if ignored {
+			continue
+		}
+
+		indexed, err := t.index.FindMinimal(NoteFindOpts{IncludeHrefs: []string{path}})
+		if err != nil {
+			return count, err
+		}
+		isIndexed := false
+		for _, note := range indexed {
+			if note.Path == path {
+				isIndexed = true
+				break
+			}
+		}
+
+		exists, err := t.fs.FileExists(filepath.Join(t.path, path))
+		if err != nil {
+			return count, err
+		}
+
+		change := paths.DiffChange{Path: path}
+		switch {
+		case exists && isIndexed:
+			change.Kind = paths.DiffModified
+		case exists:
+			change.Kind = paths.DiffAdded
+		case isIndexed:
+			change.Kind = paths.DiffRemoved
+		default:
+			continue
+		}
+		if exists {
+			count++
+		}
+		err = callback(change)
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// isIgnoredNotePath returns whether the file at the given path, relative to
+// the notebook root, should be excluded from the index, with the reason.
+func isIgnoredNotePath(config Config, path string) (ignored bool, reason string, err error) {
+	group, err := config.GroupConfigForPath(path)
+	if err != nil {
+		return true, "", err
+	}
+
+	if filepath.Ext(path) != "."+group.Note.Extension {
+		return true, "expected extension \"" + group.Note.Extension + "\"", nil
+	}
+
+	for _, ignoreGlob := range group.ExcludeGlobs() {
+		matches, err := doublestar.PathMatch(ignoreGlob, path)
+		if err != nil {
+			return true, "", errors.Wrapf(err, "failed to match exclude glob %s to %s", ignoreGlob, path)
+		}
+		if matches {
+			return true, "matched exclude glob \"" + ignoreGlob + "\"", nil
+		}
+	}
+
+	return false, "", nil
+}
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util"
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestNotebookIndexPaths(t *testing.T) {
+	fs := newFileStorageMock("/notebook", []string{"/notebook"})
+	fs.files = map[string]string{
+		"/notebook/added.md":      "added",
+		"/notebook/modified.md":   "modified",
+		"/notebook/ignored.txt":   "ignored",
+		"/notebook/.zk/hidden.md": "hidden",
+	}
+	index := newIndexPathsMock("modified.md", "removed.md")
+
+	hooks := []string{}
+	notebook := NewNotebook("/notebook", Config{
+		Note:  NoteConfig{Extension: "md"},
+		Hooks: HooksConfig{PostIndex: "post-index"},
+	}, NotebookPorts{
+		FS:                fs,
+		NoteIndex:         index,
+		NoteContentParser: newNoteContentParserMock(map[string]*NoteContent{}),
+		HookRunner: func(command string, dir string, input []byte) ([]byte, error) {
+			hooks = append(hooks, command)
+			return nil, nil
+		},
+		Logger: &util.NullLogger,
+	})
+
+	stats, err := notebook.IndexPaths([]string{
+		"/notebook/added.md",
+		"/notebook/modified.md",
+		"/notebook/removed.md",
+		"/notebook/unknown.md",
+		"/notebook/ignored.txt",
+		"/notebook/.zk/hidden.md",
+		"/elsewhere/outside.md",
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, stats.SourceCount, 2)
+	assert.Equal(t, stats.AddedCount, 1)
+	assert.Equal(t, stats.ModifiedCount, 1)
+	assert.Equal(t, stats.RemovedCount, 1)
+
+	assert.Equal(t, index.added, []string{"added.md"})
+	assert.Equal(t, index.updated, []string{"modified.md"})
+	assert.Equal(t, index.removed, []string{"removed.md"})
+
+	// The partial indexing is committed and runs the post-index hook.
+	assert.Equal(t, index.commits, 1)
+	assert.Equal(t, hooks, []string{"post-index"})
+}
+
+func TestNotebookDeleteNotes(t *testing.T) {
+	fs := newFileStorageMock("/notebook", []string{"/notebook"})
+	fs.files = map[string]string{
+		"/notebook/deleted.md": "deleted",
+		"/notebook/kept.md":    "kept",
+	}
+	index := newIndexPathsMock("deleted.md", "kept.md")
+
+	notebook := NewNotebook("/notebook", Config{
+		Note: NoteConfig{Extension: "md"},
+	}, NotebookPorts{
+		FS:                fs,
+		NoteIndex:         index,
+		NoteContentParser: newNoteContentParserMock(map[string]*NoteContent{}),
+		Logger:            &util.NullLogger,
+	})
+
+	err := notebook.DeleteNotes([]string{"/notebook/deleted.md"})
+	assert.Nil(t, err)
+	assert.Equal(t, fs.files, map[string]string{"/notebook/kept.md": "kept"})
+	assert.Equal(t, index.removed, []string{"deleted.md"})
+}
+
+// indexPathsMock records the changes made to the index.
+type indexPathsMock struct {
+	noteIndexAddMock
+	indexed []string
+	added   []string
+	updated []string
+	removed []string
+	commits int
+}
+
+func newIndexPathsMock(indexed ...string) *indexPathsMock {
+	return &indexPathsMock{
+		indexed: indexed,
+		added:   []string{},
+		updated: []string{},
+		removed: []string{},
+	}
+}
+
+func (m *indexPathsMock) FindMinimal(opts NoteFindOpts) ([]MinimalNote, error) {
+	notes := []MinimalNote{}
+	for _, path := range m.indexed {
+		for _, href := range opts.IncludeHrefs {
+			if path == href {
+				notes = append(notes, MinimalNote{Path: path})
+			}
+		}
+	}
+	return notes, nil
+}
+
+func (m *indexPathsMock) Add(note Note) (NoteID, error) {
+	m.added = append(m.added, note.Path)
+	return 1, nil
+}
+
+func (m *indexPathsMock) Update(note Note) error {
+	m.updated = append(m.updated, note.Path)
+	return nil
+}
+
+func (m *indexPathsMock) Remove(path string) error {
+	m.removed = append(m.removed, path)
+	return nil
+}
+
+func (m *indexPathsMock) Commit(transaction func(idx NoteIndex) error) error {
+	m.commits++
+	return transaction(m)
+}
+
+func TestNotebookIndexPathsWithoutChangesSkipsPostIndexHook(t *testing.T) {
+	fs := newFileStorageMock("/notebook", []string{"/notebook"})
+	index := newIndexPathsMock()
+
+	hooks := []string{}
+	notebook := NewNotebook("/notebook", Config{
+		Note:  NoteConfig{Extension: "md"},
+		Hooks: HooksConfig{PostIndex: "post-index"},
+	}, NotebookPorts{
+		FS:                fs,
+		NoteIndex:         index,
+		NoteContentParser: newNoteContentParserMock(map[string]*NoteContent{}),
+		HookRunner: func(command string, dir string, input []byte) ([]byte, error) {
+			hooks = append(hooks, command)
+			return nil, nil
+		},
+		Logger: &util.NullLogger,
+	})
+
+	stats, err := notebook.IndexPaths([]string{"/notebook/unknown.md"})
+	assert.Nil(t, err)
+	assert.False(t, stats.HasChanges())
+	assert.Equal(t, hooks, []string{})
+}
+
+func TestNotebookIndexPathsReadsTheHistoryOfChangedNotesOnly(t *testing.T) {
+	fs := newFileStorageMock("/notebook", []string{"/notebook"})
+	fs.files = map[string]string{
+		"/notebook/added.md":    "added",
+		"/notebook/modified.md": "modified",
+	}
+	index := newIndexPathsMock("modified.md", "removed.md")
+	history := &noteHistoryMock{}
+
+	notebook := NewNotebook("/notebook", Config{
+		Note: NoteConfig{Extension: "md"},
+		Git:  GitConfig{Dates: true},
+	}, NotebookPorts{
+		FS:                fs,
+		NoteIndex:         index,
+		NoteContentParser: newNoteContentParserMock(map[string]*NoteContent{}),
+		NoteHistory:       history,
+		Logger:            &util.NullLogger,
+	})
+
+	_, err := notebook.IndexPaths([]string{
+		"/notebook/added.md",
+		"/notebook/modified.md",
+		"/notebook/removed.md",
+		"/notebook/unknown.md",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, history.datesCalls, [][]string{{"added.md", "modified.md"}})
+
+	// Without changes, the history is not read.
+	_, err = notebook.IndexPaths([]string{"/notebook/unknown.md"})
+	assert.Nil(t, err)
+	assert.Equal(t, len(history.datesCalls), 1)
+}
+
+// noteHistoryMock records the paths of which the dates are read.
+type noteHistoryMock struct {
+	datesCalls [][]string
+}
+
+func (m *noteHistoryMock) Dates(paths []string) (map[string]NoteDates, error) {
+	m.datesCalls = append(m.datesCalls, paths)
+	return map[string]NoteDates{}, nil
+}
+
+func (m *noteHistoryMock) Revisions(path string) ([]NoteRevision, error) {
+	return []NoteRevision{}, nil
+}
+
+func (m *noteHistoryMock) PathsChangedSince(rev string) ([]string, error) {
+	return []string{}, nil
+}
+
+func (m *noteHistoryMock) PathsModifiedBy(author string) ([]string, error) {
+	return []string{}, nil
+}
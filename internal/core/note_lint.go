@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LintRule is a check reported by the note linter.
+type LintRule string
+
+const (
+	// LintMissingTitle reports notes without a title in their frontmatter
+	// or a heading.
+	LintMissingTitle LintRule = "missing-title"
+	// LintHeadingHierarchy reports headings skipping a level, e.g. a level 3
+	// heading following a level 1 heading.
+	LintHeadingHierarchy LintRule = "heading-hierarchy"
+	// LintTrailingWhitespace reports lines ending with whitespaces, except
+	// Markdown hard line breaks.
+	LintTrailingWhitespace LintRule = "trailing-whitespace"
+)
+
+// LintIssue is a problem found in a note by a LintRule.
+type LintIssue struct {
+	Rule     LintRule
+	Severity LSPDiagnosticSeverity
+	// Line number of the issue, starting from 1.
+	Line    int
+	Message string
+}
+
+var (
+	atxHeadingRegex    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+|$)`)
+	setextHeadingRegex = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+)
+
+// LintNote checks the content of a note with the rules enabled in the
+// notebook configuration. The issues are sorted by line.
+func (n *Notebook) LintNote(content string) []LintIssue {
+	config := n.Config.Lint
+	issues := []LintIssue{}
+	report := func(rule LintRule, severity LSPDiagnosticSeverity, line int, message string) {
+		if severity != LSPDiagnosticNone {
+			issues = append(issues, LintIssue{
+				Rule:     rule,
+				Severity: severity,
+				Line:     line + 1,
+				Message:  message,
+			})
+		}
+	}
+
+	lines := strings.Split(content, "\n")
+	fenced := fencedLines(lines)
+
+	// Skip the frontmatter.
+	bodyLine := 0
+	hasTitle := false
+	if start, end, ok := frontmatterYAML(content); ok {
+		yaml := content[start:end]
+		hasTitle = frontmatterKeyRegex("title").MatchString(yaml)
+		bodyLine = strings.Count(content[:end], "\n") + 1
+	}
+
+	level := 0
+	for i, line := range lines {
+		if fenced[i] {
+			continue
+		}
+
+		if length := trailingWhitespaceLength(line); length > 0 {
+			report(LintTrailingWhitespace, config.TrailingWhitespace, i, "trailing whitespace")
+		}
+
+		if i < bodyLine {
+			continue
+		}
+
+		headingLevel := 0
+		if match := atxHeadingRegex.FindStringSubmatch(line); match != nil {
+			headingLevel = len(match[1])
+		} else if i > bodyLine && setextHeadingRegex.MatchString(line) && strings.TrimSpace(lines[i-1]) != "" {
+			headingLevel = 1
+		}
+		if headingLevel == 0 {
+			continue
+		}
+
+		hasTitle = true
+		if level > 0 && headingLevel > level+1 {
+			report(LintHeadingHierarchy, config.HeadingHierarchy, i, fmt.Sprintf("heading level %d follows level %d", headingLevel, level))
+		}
+		level = headingLevel
+	}
+
+	if !hasTitle {
+		report(LintMissingTitle, config.MissingTitle, 0, "missing title")
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// LintNoteAt checks the note at the given path, relative to the notebook
+// root.
+func (n *Notebook) LintNoteAt(path string) ([]LintIssue, error) {
+	content, err := n.ReadNote(filepath.Join(n.Path, path))
+	if err != nil {
+		return nil, err
+	}
+	return n.LintNote(string(content)), nil
+}
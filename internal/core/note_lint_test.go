@@ -0,0 +1,41 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestLintNote(t *testing.T) {
+	notebook := &Notebook{Config: NewDefaultConfig()}
+	test := func(content string, expected []LintIssue) {
+		t.Helper()
+		assert.Equal(t, notebook.LintNote(content), expected)
+	}
+
+	test("# Title\n\n## Section\n", []LintIssue{})
+	test("---\ntitle: Title\n---\nBody\n", []LintIssue{})
+	test("Title\n=====\n\nBody\n", []LintIssue{})
+
+	test("Body\n", []LintIssue{
+		{Rule: LintMissingTitle, Severity: LSPDiagnosticWarning, Line: 1, Message: "missing title"},
+	})
+	test("---\ndate: 2021\n---\n```\n# Code\n```\n", []LintIssue{
+		{Rule: LintMissingTitle, Severity: LSPDiagnosticWarning, Line: 1, Message: "missing title"},
+	})
+
+	test("# Title\n### Section\n## Section\n#### Sub\n", []LintIssue{
+		{Rule: LintHeadingHierarchy, Severity: LSPDiagnosticWarning, Line: 2, Message: "heading level 3 follows level 1"},
+		{Rule: LintHeadingHierarchy, Severity: LSPDiagnosticWarning, Line: 4, Message: "heading level 4 follows level 2"},
+	})
+
+	test("# Title \nHard  \nbreak\n\t\n", []LintIssue{
+		{Rule: LintTrailingWhitespace, Severity: LSPDiagnosticHint, Line: 1, Message: "trailing whitespace"},
+		{Rule: LintTrailingWhitespace, Severity: LSPDiagnosticHint, Line: 4, Message: "trailing whitespace"},
+	})
+
+	// Disabled rules.
+	notebook.Config.Lint.MissingTitle = LSPDiagnosticNone
+	notebook.Config.Lint.TrailingWhitespace = LSPDiagnosticNone
+	test("Body \n", []LintIssue{})
+}
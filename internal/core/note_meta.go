@@ -0,0 +1,211 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// FrontmatterEdit is a change of the YAML frontmatter of a note, applied to
+// its raw content.
+type FrontmatterEdit func(content string) (string, error)
+
+// SetFrontmatterValue returns a FrontmatterEdit setting the top-level key to
+// the given scalar value, replacing any existing value.
+func SetFrontmatterValue(key string, value string) FrontmatterEdit {
+	return func(content string) (string, error) {
+		return setFrontmatterValue(content, key, value)
+	}
+}
+
+// UnsetFrontmatterKey returns a FrontmatterEdit removing the top-level key
+// and its value.
+func UnsetFrontmatterKey(key string) FrontmatterEdit {
+	return func(content string) (string, error) {
+		return unsetFrontmatterKey(content, key), nil
+	}
+}
+
+// AddFrontmatterItems returns a FrontmatterEdit adding the given items to
+// the list of the top-level key, if they are not already present.
+func AddFrontmatterItems(key string, items []string) FrontmatterEdit {
+	keyRegex := frontmatterKeyRegex(key)
+	if frontmatterTagsRegex.MatchString(key + ":") {
+		// Support the aliases of the tags key.
+		keyRegex = frontmatterTagsRegex
+	}
+	return func(content string) (string, error) {
+		return addFrontmatterItems(content, keyRegex, key, items)
+	}
+}
+
+// EditNoteFrontmatter applies the given edit to the frontmatter of the note
+// at the given absolute path. It returns whether the note was modified.
+//
+// The index is not updated, use IndexPaths with the modified notes.
+func (n *Notebook) EditNoteFrontmatter(absPath string, edit FrontmatterEdit) (bool, error) {
+	wrap := errors.Wrapperf("%s: failed to edit the frontmatter", absPath)
+
+	content, err := n.ReadNote(absPath)
+	if err != nil {
+		return false, wrap(err)
+	}
+	res, err := preservingLineEndings(string(content), edit)
+	if err != nil {
+		return false, wrap(err)
+	}
+	if res == string(content) {
+		return false, nil
+	}
+	err = n.WriteNote(absPath, []byte(res))
+	if err != nil {
+		return false, wrap(err)
+	}
+	return true, nil
+}
+
+// ValidateFrontmatterKey returns an error if the given key can't be edited.
+// Only top-level keys are supported.
+func ValidateFrontmatterKey(key string) error {
+	if key == "" {
+		return errors.New("missing frontmatter key")
+	}
+	if strings.ContainsAny(key, ":#\n") || strings.TrimSpace(key) != key {
+		return fmt.Errorf("%s: invalid frontmatter key", key)
+	}
+	return nil
+}
+
+// frontmatterKeyRegex matches the line of a top-level frontmatter key, ignoring
+// the case like the note parser.
+func frontmatterKeyRegex(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?im)^(` + regexp.QuoteMeta(key) + `):[ \t]*(.*)$`)
+}
+
+// frontmatterYAML returns the boundaries of the YAML content of the
+// frontmatter, without the delimiters.
+func frontmatterYAML(content string) (start int, end int, ok bool) {
+	match := frontmatterRegex.FindStringSubmatchIndex(content)
+	if match == nil {
+		return 0, 0, false
+	}
+	return match[2], match[3], true
+}
+
+// preservingLineEndings applies the given edit to content with Unix line
+// endings, then restores the Windows line endings if the frontmatter used
+// them.
+func preservingLineEndings(content string, edit FrontmatterEdit) (string, error) {
+	match := frontmatterRegex.FindStringIndex(content)
+	if match == nil || !strings.Contains(content[:match[1]], "\r\n") {
+		return edit(content)
+	}
+	res, err := edit(strings.ReplaceAll(content, "\r\n", "\n"))
+	if err != nil {
+		return content, err
+	}
+	return strings.ReplaceAll(res, "\n", "\r\n"), nil
+}
+
+// findFrontmatterKey returns the boundaries of the given top-level key in
+// yaml, including its value spanning the following indented lines and the
+// final newline.
+func findFrontmatterKey(yaml string, key string) (name string, start int, end int, ok bool) {
+	match := frontmatterKeyRegex(key).FindStringSubmatchIndex(yaml)
+	if match == nil {
+		return "", 0, 0, false
+	}
+	name = yaml[match[2]:match[3]]
+	start = match[0]
+	end = match[1]
+	if end < len(yaml) {
+		// Include the newline.
+		end++
+	}
+
+	// Nested values, block sequences and multiline scalars.
+	offset := end
+lines:
+	for _, line := range strings.SplitAfter(yaml[end:], "\n") {
+		if line == "" {
+			break
+		}
+		offset += len(line)
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			// Blank lines belong to the value only if followed by an
+			// indented line.
+			continue
+		case line[0] == ' ' || line[0] == '\t' || trimmed == "-" || strings.HasPrefix(line, "- "):
+			end = offset
+		default:
+			break lines
+		}
+	}
+
+	return name, start, end, true
+}
+
+// setFrontmatterValue sets the top-level key of the frontmatter of content to
+// the given value, creating the frontmatter if needed. A comment following
+// a single-line value is preserved.
+func setFrontmatterValue(content string, key string, value string) (string, error) {
+	line := func(name string) string {
+		return name + ": " + quote(value)
+	}
+
+	start, end, ok := frontmatterYAML(content)
+	if !ok {
+		return "---\n" + line(key) + "\n---\n" + content, nil
+	}
+	yaml := content[start:end]
+
+	name, keyStart, keyEnd, ok := findFrontmatterKey(yaml, key)
+	if !ok {
+		yaml += line(key) + "\n"
+		return content[:start] + yaml + content[end:], nil
+	}
+
+	old := yaml[keyStart:keyEnd]
+	newLine := line(name)
+	if !strings.Contains(strings.TrimSuffix(old, "\n"), "\n") {
+		if comment := trailingComment(old); comment != "" {
+			newLine += " " + comment
+		}
+	}
+	yaml = yaml[:keyStart] + newLine + "\n" + yaml[keyEnd:]
+	return content[:start] + yaml + content[end:], nil
+}
+
+// unsetFrontmatterKey removes the top-level key of the frontmatter of
+// content, with its value.
+func unsetFrontmatterKey(content string, key string) string {
+	start, end, ok := frontmatterYAML(content)
+	if !ok {
+		return content
+	}
+	yaml := content[start:end]
+
+	_, keyStart, keyEnd, ok := findFrontmatterKey(yaml, key)
+	if !ok {
+		return content
+	}
+	yaml = yaml[:keyStart] + yaml[keyEnd:]
+	return content[:start] + yaml + content[end:]
+}
+
+// trailingCommentRegex matches a YAML comment at the end of a line with a
+// plain or quoted scalar value.
+var trailingCommentRegex = regexp.MustCompile(`^[^:]*:[ \t]*(?:"(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^"'#\s][^#]*?|)[ \t]+(#.*)$`)
+
+// trailingComment returns the comment at the end of the given key line.
+func trailingComment(line string) string {
+	match := trailingCommentRegex.FindStringSubmatch(strings.TrimRight(line, "\n"))
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestSetFrontmatterValue(t *testing.T) {
+	test := func(content string, key string, value string, expected string) {
+		t.Helper()
+		actual, err := SetFrontmatterValue(key, value)(content)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	// Without frontmatter.
+	test("# Title\n", "status", "draft", "---\nstatus: draft\n---\n# Title\n")
+	// New key.
+	test("---\ntitle: Title\n---\nBody", "status", "draft", "---\ntitle: Title\nstatus: draft\n---\nBody")
+	test("---\n---\nBody", "status", "a: b", "---\nstatus: \"a: b\"\n---\nBody")
+	// Existing key, matching the case of the original key.
+	test("---\ntitle: Title\nStatus: todo\ndate: 2021\n---\n", "status", "done", "---\ntitle: Title\nStatus: done\ndate: 2021\n---\n")
+	// Preserves the comments.
+	test("---\n# Comment\nstatus: todo # Inline\n---\n", "status", "done", "---\n# Comment\nstatus: done # Inline\n---\n")
+	test("---\nstatus: \"a # b\"\n---\n", "status", "done", "---\nstatus: done\n---\n")
+	// Replaces nested values.
+	test("---\nstatus:\n  - a\n\n  - b\n\ntitle: Title\n---\n", "status", "done", "---\nstatus: done\n\ntitle: Title\n---\n")
+	test("---\nstatus:\n- a\n- b\n---\n", "status", "done", "---\nstatus: done\n---\n")
+	// Ignores nested keys.
+	test("---\nextra:\n  status: todo\n---\n", "status", "done", "---\nextra:\n  status: todo\nstatus: done\n---\n")
+	// Frontmatter after blank lines.
+	test("\n  \n---\ntitle: Title\n---\n", "status", "done", "\n  \n---\ntitle: Title\nstatus: done\n---\n")
+	// Quotes the values which YAML would not parse as strings.
+	test("---\n---\n", "status", "no", "---\nstatus: \"no\"\n---\n")
+	test("---\n---\n", "status", "- x", "---\nstatus: \"- x\"\n---\n")
+}
+
+func TestUnsetFrontmatterKey(t *testing.T) {
+	test := func(content string, key string, expected string) {
+		t.Helper()
+		actual, err := UnsetFrontmatterKey(key)(content)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("# Title\n", "status", "# Title\n")
+	test("---\ntitle: Title\n---\n", "status", "---\ntitle: Title\n---\n")
+	test("---\ntitle: Title\nstatus: todo # Comment\ndate: 2021\n---\n", "status", "---\ntitle: Title\ndate: 2021\n---\n")
+	test("---\nstatus:\n  - a\n  - b\ntitle: Title\n---\n", "status", "---\ntitle: Title\n---\n")
+	test("---\nstatus: todo\n---\nBody", "status", "---\n---\nBody")
+}
+
+func TestAddFrontmatterItems(t *testing.T) {
+	test := func(content string, key string, items []string, expected string) {
+		t.Helper()
+		actual, err := AddFrontmatterItems(key, items)(content)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("# Title\n", "aliases", []string{"a"}, "---\naliases: [a]\n---\n# Title\n")
+	test("---\naliases: [a]\n---\n", "aliases", []string{"a", "b"}, "---\naliases: [a, b]\n---\n")
+	// Tags aliases.
+	test("---\nkeywords: [a]\n---\n", "tags", []string{"b"}, "---\nkeywords: [a, b]\n---\n")
+
+	_, err := AddFrontmatterItems("status", []string{"a"})("---\nstatus: todo\n---\n")
+	assert.Err(t, err, "unsupported format for the `status` frontmatter key, expected a YAML list")
+}
+
+func TestPreservingLineEndings(t *testing.T) {
+	test := func(content string, expected string) {
+		t.Helper()
+		actual, err := preservingLineEndings(content, SetFrontmatterValue("status", "done"))
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	test("---\ntitle: Title\n---\nBody\n", "---\ntitle: Title\nstatus: done\n---\nBody\n")
+	test("---\r\ntitle: Title\r\n---\r\nBody\r\n", "---\r\ntitle: Title\r\nstatus: done\r\n---\r\nBody\r\n")
+	test("\r\n---\r\nstatus: todo\r\n---\r\n", "\r\n---\r\nstatus: done\r\n---\r\n")
+}
+
+func TestValidateFrontmatterKey(t *testing.T) {
+	assert.Nil(t, ValidateFrontmatterKey("status"))
+	assert.Err(t, ValidateFrontmatterKey(""), "missing frontmatter key")
+	assert.Err(t, ValidateFrontmatterKey("a:b"), "a:b: invalid frontmatter key")
+	assert.Err(t, ValidateFrontmatterKey(" a"), " a: invalid frontmatter key")
+}
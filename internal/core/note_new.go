@@ -20,7 +20,14 @@ type newNoteTask struct {
 	bodyTemplatePath opt.String
 	templates        TemplateLoader
 	genID            IDGenerator
+	journal          *JournalContext
 	dryRun           bool
+	// Called with the rendered note before writing it, to return its final
+	// content.
+	preWrite func(path string, content string) (string, error)
+	// Called with the final content of the note to return the bytes written,
+	// when the note is encrypted.
+	encrypt func(plaintext []byte) ([]byte, error)
 }
 
 func (t *newNoteTask) execute() (string, string, error) {
@@ -44,6 +51,7 @@ func (t *newNoteTask) execute() (string, string, error) {
 		Extra:   t.extra,
 		Now:     t.date,
 		Env:     t.env,
+		Journal: t.journal,
 	}
 
 	path, context, err := t.generatePath(context, filenameTemplate)
@@ -56,8 +64,22 @@ func (t *newNoteTask) execute() (string, string, error) {
 		return "", "", err
 	}
 
+	if t.preWrite != nil {
+		content, err = t.preWrite(path, content)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
 	if !t.dryRun {
-		err = t.fs.Write(path, []byte(content))
+		data := []byte(content)
+		if t.encrypt != nil {
+			data, err = t.encrypt(data)
+			if err != nil {
+				return "", "", err
+			}
+		}
+		err = t.fs.Write(path, data)
 		if err != nil {
 			return "", "", err
 		}
@@ -107,4 +129,5 @@ type newNoteTemplateContext struct {
 	Extra        map[string]string
 	Now          time.Time
 	Env          map[string]string
+	Journal      *JournalContext
 }
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -159,6 +161,7 @@ func TestNotebookNewNoteInDirWithGroup(t *testing.T) {
 			BodyTemplatePath: opt.NewString("group-body"),
 			Extension:        "group-ext",
 			Lang:             "de",
+			WeekStart:        time.Sunday,
 			IDOptions: IDOptions{
 				Length:  29,
 				Charset: []rune("group"),
@@ -193,6 +196,7 @@ func TestNotebookNewNoteInDirWithGroup(t *testing.T) {
 	assert.Equal(t, test.fs.files["/notebook/a-dir/group-filename.group-ext"], "group template body")
 
 	assert.Equal(t, test.receivedLang, groupConfig.Note.Lang)
+	assert.Equal(t, test.receivedWeekStart, groupConfig.Note.WeekStart)
 	assert.Equal(t, test.receivedIDOpts, groupConfig.Note.IDOptions)
 
 	// Check that the templates received the proper render contexts.
@@ -379,6 +383,144 @@ func TestNotebookNewNoteErrorWhenNoFreePath(t *testing.T) {
 	assert.Equal(t, test.fs.files, files)
 }
 
+func TestNotebookNewNoteRunsHooks(t *testing.T) {
+	type call struct {
+		command string
+		dir     string
+		input   map[string]interface{}
+	}
+	calls := []call{}
+
+	test := newNoteTest{
+		rootDir: "/notebook",
+		hooks: HooksConfig{
+			PreNew:  "stamp",
+			PostNew: "git commit",
+		},
+		hookRunner: func(command string, dir string, input []byte) ([]byte, error) {
+			var note map[string]interface{}
+			err := json.Unmarshal(input, &note)
+			assert.Nil(t, err)
+			calls = append(calls, call{command, dir, note})
+			if command == "stamp" {
+				return []byte("stamped body"), nil
+			}
+			return []byte("ignored output"), nil
+		},
+	}
+	test.setup()
+
+	note, err := test.run(NewNoteOpts{Date: now})
+	assert.Nil(t, err)
+	assert.Equal(t, note.RawContent, "stamped body")
+	assert.Equal(t, test.fs.files["/notebook/filename.ext"], "stamped body")
+
+	assert.Equal(t, len(calls), 2)
+	assert.Equal(t, calls[0].command, "stamp")
+	assert.Equal(t, calls[0].dir, "/notebook")
+	assert.Equal(t, calls[0].input["path"], "filename.ext")
+	assert.Equal(t, calls[0].input["absPath"], "/notebook/filename.ext")
+	assert.Equal(t, calls[0].input["rawContent"], "body")
+	assert.Equal(t, calls[1].command, "git commit")
+	assert.Equal(t, calls[1].input["rawContent"], "stamped body")
+}
+
+func TestNotebookNewNoteVetoedByPreNewHook(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		hooks: HooksConfig{
+			PreNew: "false",
+		},
+		hookRunner: func(command string, dir string, input []byte) ([]byte, error) {
+			return nil, errors.New("exit status 1")
+		},
+	}
+	test.setup()
+
+	_, err := test.run(NewNoteOpts{Date: now})
+	assert.Err(t, err, "new note: pre-new hook: exit status 1")
+	assert.Equal(t, len(test.fs.files), 0)
+}
+
+func TestNotebookNewNoteEncrypted(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		dirs:    []string{"/notebook/secret"},
+		groups: map[string]GroupConfig{
+			"secret": {
+				Paths: []string{"secret"},
+				Note: NoteConfig{
+					FilenameTemplate: "filename",
+					Extension:        "ext",
+					BodyTemplatePath: opt.NewString("default"),
+					Encrypt:          true,
+				},
+			},
+		},
+		encryption: EncryptionConfig{
+			Encrypt: "encrypt",
+			Index:   []EncryptedNoteField{EncryptedNoteTitle, EncryptedNoteTags},
+		},
+		hookRunner: func(command string, dir string, input []byte) ([]byte, error) {
+			assert.Equal(t, command, "encrypt")
+			assert.Equal(t, dir, "/notebook")
+			return []byte("ciphertext of " + string(input)), nil
+		},
+	}
+	test.setup()
+	test.parseContentAsNote("body", &NoteContent{
+		Title: opt.NewString("Secret"),
+		Lead:  opt.NewString("Lead"),
+		Body:  opt.NewString("Lead and body"),
+		Tags:  []string{"private"},
+		Links: []Link{{Href: "other"}},
+	})
+
+	note, err := test.run(NewNoteOpts{
+		Directory: opt.NewString("secret"),
+		Date:      now,
+	})
+	assert.Nil(t, err)
+
+	// The note returned is in plaintext.
+	assert.Equal(t, note.RawContent, "body")
+	assert.Equal(t, note.Body, "Lead and body")
+	assert.Equal(t, test.fs.files["/notebook/secret/filename.ext"], "ciphertext of body")
+
+	// Only the configured properties are indexed.
+	added := test.index.AddedNote
+	assert.Equal(t, added.Path, "secret/filename.ext")
+	assert.Equal(t, added.Title, "Secret")
+	assert.Equal(t, added.Tags, []string{"private"})
+	assert.Equal(t, added.Links, []Link{})
+	assert.Equal(t, added.Lead, "")
+	assert.Equal(t, added.Body, "")
+	assert.Equal(t, added.RawContent, "")
+	assert.Equal(t, added.Checksum, "")
+}
+
+func TestNotebookNewNoteEncryptedRequiresCommand(t *testing.T) {
+	test := newNoteTest{
+		rootDir: "/notebook",
+		groups: map[string]GroupConfig{
+			"secret": {
+				Paths: []string{},
+				Note: NoteConfig{
+					FilenameTemplate: "filename",
+					Extension:        "ext",
+					BodyTemplatePath: opt.NewString("default"),
+					Encrypt:          true,
+				},
+			},
+		},
+	}
+	test.setup()
+
+	_, err := test.run(NewNoteOpts{Group: opt.NewString("secret"), Date: now})
+	assert.Err(t, err, "new note: failed to encrypt the note: no encrypt command set in the [encryption] config section")
+	assert.Equal(t, len(test.fs.files), 0)
+}
+
 var now = time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
 
 // newNoteTest builds and runs the SUT for new note test cases.
@@ -397,9 +539,13 @@ type newNoteTest struct {
 	bodyTemplate           *templateSpy
 	idGeneratorFactory     IDGeneratorFactory
 	osEnv                  map[string]string
+	hooks                  HooksConfig
+	hookRunner             HookRunner
+	encryption             EncryptionConfig
 
-	receivedLang   string
-	receivedIDOpts IDOptions
+	receivedLang      string
+	receivedWeekStart time.Weekday
+	receivedIDOpts    IDOptions
 }
 
 func (t *newNoteTest) setup() {
@@ -458,7 +604,9 @@ func (t *newNoteTest) setup() {
 				Case:    CaseUpper,
 			},
 		},
-		Groups: t.groups,
+		Groups:     t.groups,
+		Hooks:      t.hooks,
+		Encryption: t.encryption,
 		Extra: map[string]string{
 			"conf-extra": "38srnw",
 		},
@@ -471,8 +619,9 @@ func (t *newNoteTest) parseContentAsNote(content string, note *NoteContent) {
 
 func (t *newNoteTest) run(opts NewNoteOpts) (*Note, error) {
 	notebook := NewNotebook(t.rootDir, t.config, NotebookPorts{
-		TemplateLoaderFactory: func(language string) (TemplateLoader, error) {
-			t.receivedLang = language
+		TemplateLoaderFactory: func(config NoteConfig) (TemplateLoader, error) {
+			t.receivedLang = config.Lang
+			t.receivedWeekStart = config.WeekStart
 			return t.templateLoader, nil
 		},
 		IDGeneratorFactory: func(opts IDOptions) func() string {
@@ -482,6 +631,7 @@ func (t *newNoteTest) run(opts NewNoteOpts) (*Note, error) {
 		FS:                t.fs,
 		NoteIndex:         t.index,
 		NoteContentParser: t.parser,
+		HookRunner:        t.hookRunner,
 		Logger:            &util.NullLogger,
 		OSEnv:             func() map[string]string { return t.osEnv },
 	})
@@ -500,6 +650,7 @@ func incrementingID(opts IDOptions) func() string {
 
 type noteIndexAddMock struct {
 	ReturnedID NoteID
+	AddedNote  Note
 }
 
 func (m *noteIndexAddMock) Find(opts NoteFindOpts) ([]ContextualNote, error)     { return nil, nil }
@@ -513,10 +664,30 @@ func (m *noteIndexAddMock) FindLinksBetweenNotes(ids []NoteID) ([]ResolvedLink,
 func (m *noteIndexAddMock) FindCollections(kind CollectionKind, sorters []CollectionSorter) ([]Collection, error) {
 	return nil, nil
 }
-func (m *noteIndexAddMock) IndexedPaths() (<-chan paths.Metadata, error)       { return nil, nil }
-func (m *noteIndexAddMock) Add(note Note) (NoteID, error)                      { return m.ReturnedID, nil }
+func (m *noteIndexAddMock) FindTasks(opts TaskFindOpts) ([]NoteTask, error) {
+	return nil, nil
+}
+func (m *noteIndexAddMock) FindAssetLinks() ([]AssetLink, error) {
+	return nil, nil
+}
+func (m *noteIndexAddMock) Stats(opts StatsOpts) (NotebookStats, error) {
+	return NotebookStats{}, nil
+}
+func (m *noteIndexAddMock) FindCards(opts CardFindOpts) ([]NoteCard, error) {
+	return nil, nil
+}
+func (m *noteIndexAddMock) SaveCardSchedule(hash string, schedule CardSchedule) error {
+	return nil
+}
+func (m *noteIndexAddMock) IndexedPaths() (<-chan paths.Metadata, error) { return nil, nil }
+func (m *noteIndexAddMock) Add(note Note) (NoteID, error) {
+	m.AddedNote = note
+	return m.ReturnedID, nil
+}
 func (m *noteIndexAddMock) Update(note Note) error                             { return nil }
 func (m *noteIndexAddMock) Remove(path string) error                           { return nil }
 func (m *noteIndexAddMock) Commit(transaction func(idx NoteIndex) error) error { return nil }
 func (m *noteIndexAddMock) NeedsReindexing() (bool, error)                     { return false, nil }
 func (m *noteIndexAddMock) SetNeedsReindexing(needsReindexing bool) error      { return nil }
+func (m *noteIndexAddMock) Metadata(key string) (string, error)                { return "", nil }
+func (m *noteIndexAddMock) SetMetadata(key string, value string) error         { return nil }
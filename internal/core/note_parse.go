@@ -36,6 +36,10 @@ type NoteContent struct {
 	Tags []string
 	// Links is the list of outbound links found in the note.
 	Links []Link
+	// Tasks is the list of task list items found in the note.
+	Tasks []Task
+	// Cards is the list of flashcards found in the note.
+	Cards []Card
 	// Additional metadata. For example, extracted from a YAML frontmatter.
 	Metadata map[string]interface{}
 }
@@ -44,7 +48,7 @@ type NoteContent struct {
 func (n *Notebook) ParseNoteAt(absPath string) (*Note, error) {
 	wrap := errors.Wrapper(absPath)
 
-	content, err := n.fs.Read(absPath)
+	content, err := n.ReadNote(absPath)
 	if err != nil {
 		return nil, wrap(err)
 	}
@@ -75,12 +79,14 @@ func (n *Notebook) ParseNoteWithContent(absPath string, content []byte) (*Note,
 		WordCount:  len(strings.Fields(contentStr)),
 		Links:      make([]Link, 0),
 		Tags:       contentParts.Tags,
+		Tasks:      contentParts.Tasks,
+		Cards:      contentParts.Cards,
 		Metadata:   contentParts.Metadata,
 		Checksum:   fmt.Sprintf("%x", sha256.Sum256(content)),
 	}
 
 	for _, link := range contentParts.Links {
-		if !strutil.IsURL(link.Href) && link.Type == LinkTypeMarkdown {
+		if !strutil.IsURL(link.Href) && (link.Type == LinkTypeMarkdown || link.Type == LinkTypeImage) {
 			// Make the href relative to the notebook root.
 			href := filepath.Join(filepath.Dir(absPath), link.Href)
 			link.Href, err = n.RelPath(href)
@@ -88,6 +94,7 @@ func (n *Notebook) ParseNoteWithContent(absPath string, content []byte) (*Note,
 				n.logger.Err(err)
 				continue
 			}
+			link.AssetPath, _ = assetPathFromHref(n.Config, link.Href)
 		}
 		note.Links = append(note.Links, link)
 	}
@@ -102,25 +109,33 @@ func (n *Notebook) ParseNoteWithContent(absPath string, content []byte) (*Note,
 }
 
 func creationDateFrom(metadata map[string]interface{}, times times.Timespec) time.Time {
-	// Read the creation date from the YAML frontmatter `date` key.
+	if date, ok := creationDateFromMetadata(metadata); ok {
+		return date
+	}
+
+	if times.HasBirthTime() {
+		return times.BirthTime().UTC()
+	}
+
+	return time.Now().UTC()
+}
+
+// creationDateFromMetadata reads the creation date from the YAML frontmatter
+// `date` key.
+func creationDateFromMetadata(metadata map[string]interface{}) (time.Time, bool) {
 	if dateVal, ok := metadata["date"]; ok {
 		if dateStr, ok := dateVal.(string); ok {
 			if time, err := iso8601.ParseString(dateStr); err == nil {
-				return time
+				return time, true
 			}
 			// Omitting the `T` is common
 			if time, err := time.Parse("2006-01-02 15:04:05", dateStr); err == nil {
-				return time
+				return time, true
 			}
 			if time, err := time.Parse("2006-01-02 15:04", dateStr); err == nil {
-				return time
+				return time, true
 			}
 		}
 	}
-
-	if times.HasBirthTime() {
-		return times.BirthTime().UTC()
-	}
-
-	return time.Now().UTC()
+	return time.Time{}, false
 }
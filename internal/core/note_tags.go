@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// AddNoteTags adds the given tags to the YAML frontmatter of the note at the
+// given absolute path, then updates the index.
+func (n *Notebook) AddNoteTags(absPath string, tags []string) error {
+	wrap := errors.Wrapperf("%s: failed to add tags", absPath)
+
+	content, err := n.ReadNote(absPath)
+	if err != nil {
+		return wrap(err)
+	}
+	res, err := preservingLineEndings(string(content), func(content string) (string, error) {
+		return addFrontmatterTags(content, tags)
+	})
+	if err != nil {
+		return wrap(err)
+	}
+	if res == string(content) {
+		return nil
+	}
+	err = n.WriteNote(absPath, []byte(res))
+	if err != nil {
+		return wrap(err)
+	}
+	_, err = n.IndexPaths([]string{absPath})
+	return err
+}
+
+var (
+	frontmatterRegex     = regexp.MustCompile(`(?s)\A(?:[ \t]*\r?\n)*---[ \t]*\r?\n((?:.*?\r?\n)?)---[ \t]*(?:\r?\n|\z)`)
+	frontmatterTagsRegex = regexp.MustCompile(`(?im)^(tags?|keywords?):[ \t]*(.*)$`)
+	blockItemRegex       = regexp.MustCompile(`^([ \t]*)-[ \t]+(.*)$`)
+)
+
+// addFrontmatterTags adds the given tags to the tags list of the frontmatter
+// of content, creating it if needed. The existing tags and the layout of the
+// list are preserved.
+func addFrontmatterTags(content string, tags []string) (string, error) {
+	return addFrontmatterItems(content, frontmatterTagsRegex, "tags", tags)
+}
+
+// addFrontmatterItems adds the given items to the list of the first
+// frontmatter key matching keyRegex, or to a new key if none is found. The
+// frontmatter is created if needed. The existing items and the layout of the
+// list are preserved.
+func addFrontmatterItems(content string, keyRegex *regexp.Regexp, newKey string, items []string) (string, error) {
+	start, end, ok := frontmatterYAML(content)
+	if !ok {
+		return "---\n" + newKey + ": " + flowList(items) + "\n---\n" + content, nil
+	}
+	yaml := content[start:end]
+
+	key := keyRegex.FindStringSubmatchIndex(yaml)
+	if key == nil {
+		yaml += newKey + ": " + flowList(items) + "\n"
+		return content[:start] + yaml + content[end:], nil
+	}
+
+	name := yaml[key[2]:key[3]]
+	value := strings.TrimSpace(yaml[key[4]:key[5]])
+	switch {
+	case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+		existing := splitFlowList(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+		line := name + ": " + flowList(append(existing, newItems(existing, items)...))
+		yaml = yaml[:key[0]] + line + yaml[key[1]:]
+
+	case value == "":
+		// Block sequence on the following lines.
+		lines := strings.SplitAfter(yaml[key[1]:], "\n")
+		// Skip the end of the key line.
+		offset := key[1] + len(lines[0])
+		indent := "  "
+		existing := []string{}
+		for _, line := range lines[1:] {
+			item := blockItemRegex.FindStringSubmatch(strings.TrimRight(line, "\n"))
+			if item == nil {
+				break
+			}
+			indent = item[1]
+			existing = append(existing, unquote(item[2]))
+			offset += len(line)
+		}
+		lines = []string{}
+		for _, item := range newItems(existing, items) {
+			lines = append(lines, indent+"- "+quote(item)+"\n")
+		}
+		yaml = yaml[:offset] + strings.Join(lines, "") + yaml[offset:]
+
+	default:
+		return content, fmt.Errorf("unsupported format for the `%s` frontmatter key, expected a YAML list", name)
+	}
+
+	return content[:start] + yaml + content[end:], nil
+}
+
+// newItems returns the items which are not already in existing.
+func newItems(existing []string, items []string) []string {
+	res := []string{}
+	for _, item := range items {
+		found := false
+		for _, e := range append(existing, res...) {
+			if e == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+func flowList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, quote(item))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func splitFlowList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, unquote(item))
+		}
+	}
+	return items
+}
+
+// quote returns the given YAML scalar, quoted if needed. The value is quoted
+// when it would not be parsed back as the same string, e.g. `yes`, `~`, `42`
+// or `- x`.
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, ",[]{}:#&*!|>'\"%@`") || strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(s), &value); err != nil || value != s {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '\'' {
+			return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+		}
+		if res, err := strconv.Unquote(s); err == nil {
+			return res
+		}
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestAddFrontmatterTags(t *testing.T) {
+	test := func(content string, tags []string, expected string) {
+		t.Helper()
+		actual, err := addFrontmatterTags(content, tags)
+		assert.Nil(t, err)
+		assert.Equal(t, actual, expected)
+	}
+
+	// Without frontmatter.
+	test("# Title\n", []string{"a", "b c"}, "---\ntags: [a, b c]\n---\n# Title\n")
+	// Without tags.
+	test("---\ntitle: Title\n---\n# Title\n", []string{"a"}, "---\ntitle: Title\ntags: [a]\n---\n# Title\n")
+	test("---\n---\nBody", []string{"a"}, "---\ntags: [a]\n---\nBody")
+	// Flow list.
+	test("---\ntitle: Title\ntags: [a, \"b\"]\ndate: 2021\n---\n", []string{"b", "c:d"}, "---\ntitle: Title\ntags: [a, b, \"c:d\"]\ndate: 2021\n---\n")
+	test("---\nKeywords: []\n---\n", []string{"a"}, "---\nKeywords: [a]\n---\n")
+	// Block list.
+	test("---\ntags:\n    - a\n    - b\ntitle: Title\n---\n", []string{"c", "a"}, "---\ntags:\n    - a\n    - b\n    - c\ntitle: Title\n---\n")
+	test("---\ntags:\ntitle: Title\n---\n", []string{"a"}, "---\ntags:\n  - a\ntitle: Title\n---\n")
+
+	_, err := addFrontmatterTags("---\ntags: a b\n---\n", []string{"c"})
+	assert.Err(t, err, "unsupported format for the `tags` frontmatter key, expected a YAML list")
+}
+
+func TestQuote(t *testing.T) {
+	test := func(value string, expected string) {
+		t.Helper()
+		assert.Equal(t, quote(value), expected)
+	}
+
+	test("plain", "plain")
+	test("two words", "two words")
+	test("-x", "-x")
+	test("2021-01-01", "2021-01-01")
+	test("", `""`)
+	test(" padded", `" padded"`)
+	test("a: b", `"a: b"`)
+	test("a,b", `"a,b"`)
+	test("- x", `"- x"`)
+	test("? x", `"? x"`)
+	test("yes", `"yes"`)
+	test("No", `"No"`)
+	test("null", `"null"`)
+	test("~", `"~"`)
+	test("42", `"42"`)
+	test("1.5", `"1.5"`)
+}
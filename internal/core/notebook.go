@@ -22,6 +22,8 @@ type Notebook struct {
 	templateLoaderFactory TemplateLoaderFactory
 	idGeneratorFactory    IDGeneratorFactory
 	fs                    FileStorage
+	hookRunner            HookRunner
+	history               NoteHistory
 	logger                util.Logger
 	osEnv                 func() map[string]string
 }
@@ -40,6 +42,8 @@ func NewNotebook(
 		templateLoaderFactory: ports.TemplateLoaderFactory,
 		idGeneratorFactory:    ports.IDGeneratorFactory,
 		fs:                    ports.FS,
+		hookRunner:            ports.HookRunner,
+		history:               ports.NoteHistory,
 		logger:                ports.Logger,
 		osEnv:                 ports.OSEnv,
 	}
@@ -51,6 +55,8 @@ type NotebookPorts struct {
 	TemplateLoaderFactory TemplateLoaderFactory
 	IDGeneratorFactory    IDGeneratorFactory
 	FS                    FileStorage
+	HookRunner            HookRunner
+	NoteHistory           NoteHistory
 	Logger                util.Logger
 	OSEnv                 func() map[string]string
 }
@@ -65,24 +71,79 @@ func (n *Notebook) Index(opts NoteIndexOpts) (stats NoteIndexingStats, err error
 
 // Index indexes the content of the notebook to be searchable.
 func (n *Notebook) IndexWithCallback(opts NoteIndexOpts, callback func(change paths.DiffChange)) (stats NoteIndexingStats, err error) {
-	err = n.index.Commit(func(index NoteIndex) error {
-		task := indexTask{
-			path:    n.Path,
-			config:  n.Config,
-			force:   opts.Force,
-			verbose: opts.Verbose,
-			index:   index,
-			parser:  n,
-			logger:  n.logger,
+	return n.runIndexTask(indexTask{
+		force:     opts.Force,
+		verbose:   opts.Verbose,
+		newParser: n.indexParser,
+	}, callback)
+}
+
+// IndexPaths updates the index with the current state of the given files,
+// without walking the whole notebook. Paths outside the notebook or ignored
+// by the configuration are skipped.
+func (n *Notebook) IndexPaths(absPaths []string) (stats NoteIndexingStats, err error) {
+	relPaths := []string{}
+	for _, absPath := range absPaths {
+		path, err := n.RelPath(absPath)
+		if err != nil {
+			continue
+		}
+		relPaths = append(relPaths, path)
+	}
+
+	return n.runIndexTask(indexTask{
+		newParser: n.indexParser,
+		paths:     relPaths,
+	}, func(change paths.DiffChange) {})
+}
+
+// DeleteNotes removes the files of the notes at the given absolute paths,
+// then removes them from the index.
+func (n *Notebook) DeleteNotes(absPaths []string) error {
+	for _, absPath := range absPaths {
+		err := n.fs.Remove(absPath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete %s", absPath)
 		}
+	}
+	_, err := n.IndexPaths(absPaths)
+	return err
+}
+
+// runIndexTask executes the given indexing task in a transaction, then runs
+// the post-index hook.
+func (n *Notebook) runIndexTask(task indexTask, callback func(change paths.DiffChange)) (stats NoteIndexingStats, err error) {
+	task.path = n.Path
+	task.config = n.Config
+	task.fs = n.fs
+	task.logger = n.logger
+
+	err = n.index.Commit(func(index NoteIndex) error {
+		task.index = index
 		stats, err = task.execute(callback)
 		return err
 	})
 
+	if err == nil && stats.HasChanges() {
+		_, hookErr := n.runHook(HookPostIndex, stats)
+		n.logger.Err(errors.Wrapf(hookErr, "%s hook", HookPostIndex))
+	}
+
 	err = errors.Wrap(err, "indexing")
 	return
 }
 
+// isHiddenPath returns whether one of the components of the given relative
+// path is hidden, e.g. the .zk directory.
+func isHiddenPath(path string) bool {
+	for _, component := range strings.Split(filepath.ToSlash(path), "/") {
+		if strings.HasPrefix(component, ".") {
+			return true
+		}
+	}
+	return false
+}
+
 // NewNoteOpts holds the options used to create a new note in a Notebook.
 type NewNoteOpts struct {
 	// Title of the new note.
@@ -103,6 +164,11 @@ type NewNoteOpts struct {
 	DryRun bool
 	// Use a provided id over generating one
 	ID string
+	// ID or path of the parent note, used to derive the ID of the new note
+	// with the Folgezettel strategy.
+	After string
+	// Variables of a periodic journal note, exposed to the templates.
+	Journal *JournalContext
 }
 
 // ErrNoteExists is an error returned when a note already exists with the
@@ -137,18 +203,50 @@ func (n *Notebook) NewNote(opts NewNoteOpts) (*Note, error) {
 		extra[k] = v
 	}
 
-	templates, err := n.templateLoaderFactory(config.Note.Lang)
+	templates, err := n.templateLoaderFactory(config.Note)
 	if err != nil {
 		return nil, wrap(err)
 	}
 
 	var idGenerator IDGenerator
-	if opts.ID != "" {
+	var sequence *idSequence
+	idOpts := config.Note.IDOptions
+	switch {
+	case opts.ID != "":
 		idGenerator = func() string {
 			return opts.ID
 		}
-	} else {
-		idGenerator = n.idGeneratorFactory(config.Note.IDOptions)
+	case opts.After != "":
+		if idOpts.Strategy != IDStrategyFolgezettel {
+			return nil, wrap(errors.New("following a parent note requires the folgezettel ID strategy"))
+		}
+		parent := paths.FilenameStem(opts.After)
+		idGenerator, err = newFolgezettelIDGenerator(parent)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		found, err := n.hasNoteWithID(parent)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		if !found {
+			return nil, wrap(fmt.Errorf("%s: parent note not found", opts.After))
+		}
+	case idOpts.Strategy == IDStrategySequential || idOpts.Strategy == IDStrategyFolgezettel:
+		// Top-level Folgezettel IDs are not padded.
+		padding := 0
+		idRegex := folgezettelRegex
+		if idOpts.Strategy == IDStrategySequential {
+			padding = idOpts.Length
+			idRegex = sequentialIDRegex(padding)
+		}
+		sequence, err = n.newIDSequence(padding, idRegex)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		idGenerator = sequence.next
+	default:
+		idGenerator = n.idGeneratorFactory(idOpts)
 	}
 
 	task := newNoteTask{
@@ -163,8 +261,15 @@ func (n *Notebook) NewNote(opts NewNoteOpts) (*Note, error) {
 		bodyTemplatePath: opts.Template.Or(config.Note.BodyTemplatePath),
 		templates:        templates,
 		genID:            idGenerator,
+		journal:          opts.Journal,
 		dryRun:           opts.DryRun,
 	}
+	if !opts.DryRun {
+		task.preWrite = n.preNewHook
+	}
+	if config.Note.Encrypt {
+		task.encrypt = n.encrypt
+	}
 	path, content, err := task.execute()
 	if err != nil {
 		return nil, wrap(err)
@@ -176,16 +281,47 @@ func (n *Notebook) NewNote(opts NewNoteOpts) (*Note, error) {
 	}
 
 	if !opts.DryRun {
-		id, err := n.index.Add(*note)
+		id, err := n.index.Add(n.indexedNote(*note))
 		if err != nil {
 			return nil, wrap(err)
 		}
 		note.ID = id
+
+		if sequence != nil {
+			err = n.saveIDSequence(sequence)
+			if err != nil {
+				return nil, wrap(err)
+			}
+		}
+
+		err = n.RunNoteHook(HookPostNew, path)
+		if err != nil {
+			return nil, wrap(err)
+		}
 	}
 
 	return note, nil
 }
 
+// preNewHook runs the pre-new hook with a new note before it is written.
+// Returns the content to write, which might have been modified by the hook.
+func (n *Notebook) preNewHook(absPath string, content string) (string, error) {
+	if n.Config.Hooks.PreNew == "" {
+		return content, nil
+	}
+	wrap := errors.Wrapperf("%s hook", HookPreNew)
+
+	note, err := n.ParseNoteWithContent(absPath, []byte(content))
+	if note == nil || err != nil {
+		return "", wrap(err)
+	}
+	output, err := n.runNoteHook(HookPreNew, *note)
+	if output == nil || err != nil {
+		return content, wrap(err)
+	}
+	return string(output), nil
+}
+
 // FindNotes retrieves the notes matching the given filtering options.
 func (n *Notebook) FindNotes(opts NoteFindOpts) ([]ContextualNote, error) {
 	return n.index.Find(opts)
@@ -245,6 +381,16 @@ func (n *Notebook) FindCollections(kind CollectionKind, sorters []CollectionSort
 	return n.index.FindCollections(kind, sorters)
 }
 
+// FindTasks retrieves the tasks matching the given filtering options.
+func (n *Notebook) FindTasks(opts TaskFindOpts) ([]NoteTask, error) {
+	return n.index.FindTasks(opts)
+}
+
+// Stats computes the statistics of the notes matching the given options.
+func (n *Notebook) Stats(opts StatsOpts) (NotebookStats, error) {
+	return n.index.Stats(opts)
+}
+
 // RelPath returns the path relative to the notebook root to the given path.
 func (n *Notebook) RelPath(originalPath string) (string, error) {
 	wrap := errors.Wrapperf("%v: not a valid notebook path", originalPath)
@@ -329,7 +475,14 @@ func (n *Notebook) RequireDirAt(path string) (Dir, error) {
 
 // NewNoteFormatter returns a NoteFormatter used to format notes with the given template.
 func (n *Notebook) NewNoteFormatter(templateString string) (NoteFormatter, error) {
-	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
+	return n.NewNamedNoteFormatter("", templateString)
+}
+
+// NewNamedNoteFormatter returns a NoteFormatter used to format notes with the
+// given template, when listing notes from several notebooks. The note paths
+// are prefixed with the given notebook alias.
+func (n *Notebook) NewNamedNoteFormatter(notebookName string, templateString string) (NoteFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
 	if err != nil {
 		return nil, err
 	}
@@ -343,12 +496,38 @@ func (n *Notebook) NewNoteFormatter(templateString string) (NoteFormatter, error
 		return nil, err
 	}
 
-	return newNoteFormatter(n.Path, template, linkFormatter, n.osEnv(), n.fs)
+	return newNoteFormatter(n.Path, notebookName, template, linkFormatter, n.osEnv(), n.fs)
+}
+
+// NoteFormatContexts returns the template variables describing the given
+// notes, as available to the note formatting templates.
+func (n *Notebook) NoteFormatContexts(notes []ContextualNote) ([]interface{}, error) {
+	linkFormatter, err := n.NewLinkFormatter()
+	if err != nil {
+		return nil, err
+	}
+
+	env := n.osEnv()
+	contexts := make([]interface{}, 0, len(notes))
+	for _, note := range notes {
+		snippets := make([]string, 0, len(note.Snippets))
+		for _, snippet := range note.Snippets {
+			snippets = append(snippets, noteTermRegex.ReplaceAllString(snippet, "$1"))
+		}
+		note.Snippets = snippets
+
+		context, err := newNoteFormatRenderContext(note, n.Path, "", linkFormatter, env, n.fs)
+		if err != nil {
+			return nil, err
+		}
+		contexts = append(contexts, context)
+	}
+	return contexts, nil
 }
 
 // NewCollectionFormatter returns a CollectionFormatter used to format notes with the given template.
 func (n *Notebook) NewCollectionFormatter(templateString string) (CollectionFormatter, error) {
-	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
+	templates, err := n.templateLoaderFactory(n.Config.Note)
 	if err != nil {
 		return nil, err
 	}
@@ -360,12 +539,114 @@ func (n *Notebook) NewCollectionFormatter(templateString string) (CollectionForm
 	return newCollectionFormatter(template)
 }
 
+// NewTaskFormatter returns a TaskFormatter used to format tasks with the given template.
+func (n *Notebook) NewTaskFormatter(templateString string) (TaskFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTaskFormatter(n.Path, template, n.fs)
+}
+
+// NewAssetFormatter returns an AssetFormatter used to format assets with the given template.
+func (n *Notebook) NewAssetFormatter(templateString string) (AssetFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAssetFormatter(n.Path, template, n.fs)
+}
+
+// NewStatsFormatter returns a StatsFormatter used to format the notebook statistics with the given template.
+func (n *Notebook) NewStatsFormatter(templateString string) (StatsFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStatsFormatter(template)
+}
+
+// NewDuplicateGroupFormatter returns a DuplicateGroupFormatter used to format groups of duplicate notes with the given template.
+func (n *Notebook) NewDuplicateGroupFormatter(templateString string) (DuplicateGroupFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDuplicateGroupFormatter(n.Path, template, n.fs)
+}
+
+// NewNoteRevisionFormatter returns a NoteRevisionFormatter used to format note revisions with the given template.
+func (n *Notebook) NewNoteRevisionFormatter(templateString string) (NoteRevisionFormatter, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
+	if err != nil {
+		return nil, err
+	}
+	template, err := templates.LoadTemplate(templateString)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNoteRevisionFormatter(template)
+}
+
 // NewLinkFormatter returns a LinkFormatter used to generate internal links between notes.
 func (n *Notebook) NewLinkFormatter() (LinkFormatter, error) {
-	templates, err := n.templateLoaderFactory(n.Config.Note.Lang)
+	templates, err := n.templateLoaderFactory(n.Config.Note)
 	if err != nil {
 		return nil, err
 	}
 
 	return NewLinkFormatter(n.Config.Format.Markdown, templates)
 }
+
+// RenderTemplateSample renders the note template at the given path against a
+// sample note, to check it for errors before creating a note. The path may be
+// relative to the templates directories.
+func (n *Notebook) RenderTemplateSample(path string) (string, error) {
+	templates, err := n.templateLoaderFactory(n.Config.Note)
+	if err != nil {
+		return "", err
+	}
+	template, err := templates.LoadTemplateAt(path)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	return template.Render(newNoteTemplateContext{
+		ID:           "a1b2",
+		Title:        "Sample note",
+		Content:      "Sample content.",
+		Dir:          "",
+		Filename:     "a1b2." + n.Config.Note.Extension,
+		FilenameStem: "a1b2",
+		Extra:        n.Config.Extra,
+		Now:          now,
+		Env:          n.osEnv(),
+		Journal: &JournalContext{
+			Period: JournalDaily.String(),
+			Start:  JournalDaily.Start(now, n.Config.Note.WeekStart),
+			End:    JournalDaily.Add(now, 1, n.Config.Note.WeekStart),
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/zk-org/zk/internal/util/errors"
+)
+
+// NotebookNote holds a note found while searching several notebooks.
+type NotebookNote struct {
+	ContextualNote
+	// Alias of the notebook containing the note.
+	NotebookName string
+	// Notebook containing the note.
+	Notebook *Notebook
+}
+
+// FindNotesInNotebooks retrieves the notes matching the filtering options
+// built by makeOpts in each of the given notebooks, indexed by their alias.
+//
+// The results are merged and sorted using the sorters and limit of the first
+// notebook's options, in alphabetical order. Without sorters, the notes are
+// grouped by notebook.
+func FindNotesInNotebooks(notebooks map[string]*Notebook, makeOpts func(notebook *Notebook) (NoteFindOpts, error)) ([]NotebookNote, error) {
+	names := make([]string, 0, len(notebooks))
+	for name := range notebooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	notes := []NotebookNote{}
+	var sorters []NoteSorter
+	limit := 0
+
+	for i, name := range names {
+		notebook := notebooks[name]
+		opts, err := makeOpts(notebook)
+		if err != nil {
+			return nil, errors.Wrapf(err, "notebook %s", name)
+		}
+		if i == 0 {
+			sorters = opts.Sorters
+			limit = opts.Limit
+		}
+
+		found, err := notebook.FindNotes(opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "notebook %s", name)
+		}
+		for _, note := range found {
+			notes = append(notes, NotebookNote{
+				ContextualNote: note,
+				NotebookName:   name,
+				Notebook:       notebook,
+			})
+		}
+	}
+
+	sortNotebookNotes(notes, sorters)
+
+	if limit > 0 && len(notes) > limit {
+		notes = notes[:limit]
+	}
+	return notes, nil
+}
+
+// sortNotebookNotes sorts the notes found in several notebooks, following the
+// same criteria as the note index.
+func sortNotebookNotes(notes []NotebookNote, sorters []NoteSorter) {
+	if len(sorters) == 0 {
+		return
+	}
+
+	for _, sorter := range sorters {
+		if sorter.Field == NoteSortRandom {
+			rand.Shuffle(len(notes), func(i, j int) {
+				notes[i], notes[j] = notes[j], notes[i]
+			})
+			return
+		}
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		for _, sorter := range sorters {
+			cmp := compareNotes(notes[i].Note, notes[j].Note, sorter.Field)
+			if cmp == 0 {
+				continue
+			}
+			if sorter.Ascending {
+				return cmp < 0
+			}
+			return cmp > 0
+		}
+		return notes[i].Title < notes[j].Title
+	})
+}
+
+// compareNotes returns an integer comparing the given field of two notes.
+func compareNotes(a, b Note, field NoteSortField) int {
+	switch field {
+	case NoteSortCreated:
+		return a.Created.Compare(b.Created)
+	case NoteSortModified:
+		return a.Modified.Compare(b.Modified)
+	case NoteSortPath:
+		return strings.Compare(a.Path, b.Path)
+	case NoteSortTitle:
+		return strings.Compare(a.Title, b.Title)
+	case NoteSortWordCount:
+		return a.WordCount - b.WordCount
+	default:
+		return 0
+	}
+}
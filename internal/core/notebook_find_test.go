@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestSortNotebookNotes(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2021, 1, day, 0, 0, 0, 0, time.UTC)
+	}
+	note := func(notebook string, path string, title string, created time.Time, wordCount int) NotebookNote {
+		return NotebookNote{
+			ContextualNote: ContextualNote{
+				Note: Note{Path: path, Title: title, Created: created, WordCount: wordCount},
+			},
+			NotebookName: notebook,
+		}
+	}
+
+	test := func(sorters []NoteSorter, expected []string) {
+		notes := []NotebookNote{
+			note("personal", "b.md", "Bravo", date(2), 30),
+			note("personal", "d.md", "Delta", date(4), 10),
+			note("work", "a.md", "Alpha", date(3), 10),
+			note("work", "c.md", "Charlie", date(1), 20),
+		}
+		sortNotebookNotes(notes, sorters)
+
+		actual := []string{}
+		for _, note := range notes {
+			actual = append(actual, note.NotebookName+":"+note.Path)
+		}
+		assert.Equal(t, actual, expected)
+	}
+
+	// Without sorters, the notes are grouped by notebook.
+	test([]NoteSorter{}, []string{"personal:b.md", "personal:d.md", "work:a.md", "work:c.md"})
+	test([]NoteSorter{{Field: NoteSortPath, Ascending: true}}, []string{"work:a.md", "personal:b.md", "work:c.md", "personal:d.md"})
+	test([]NoteSorter{{Field: NoteSortCreated, Ascending: false}}, []string{"personal:d.md", "work:a.md", "personal:b.md", "work:c.md"})
+	// Ties are broken by the next sorters, then the title.
+	test([]NoteSorter{{Field: NoteSortWordCount, Ascending: true}}, []string{"work:a.md", "personal:d.md", "work:c.md", "personal:b.md"})
+	test([]NoteSorter{
+		{Field: NoteSortWordCount, Ascending: true},
+		{Field: NoteSortTitle, Ascending: false},
+	}, []string{"personal:d.md", "work:a.md", "work:c.md", "personal:b.md"})
+}
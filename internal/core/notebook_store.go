@@ -78,6 +78,20 @@ func (ns *NotebookStore) Open(path string) (*Notebook, error) {
 	return nb, nil
 }
 
+// OpenAll returns the notebooks declared in the `[notebooks]` section of the
+// global config, indexed by their alias.
+func (ns *NotebookStore) OpenAll() (map[string]*Notebook, error) {
+	notebooks := map[string]*Notebook{}
+	for alias, dir := range ns.config.Notebooks {
+		nb, err := ns.Open(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "notebook %s", alias)
+		}
+		notebooks[alias] = nb
+	}
+	return notebooks, nil
+}
+
 // cachedNotebookAt returns any cached notebook containing the given path.
 func (ns *NotebookStore) cachedNotebookAt(path string) *Notebook {
 	path, err := ns.fs.Abs(path)
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+)
+
+// NotebookStats summarizes the content and growth of a set of notes.
+type NotebookStats struct {
+	// Number of notes.
+	NoteCount int `json:"noteCount" handlebars:"note-count"`
+	// Total number of words in the notes.
+	WordCount int `json:"wordCount" handlebars:"word-count"`
+	// Number of distinct tags attached to the notes.
+	TagCount int `json:"tagCount" handlebars:"tag-count"`
+	// Number of links from the notes to other notes.
+	LinkCount int `json:"linkCount" handlebars:"link-count"`
+	// Number of notes without any backlink.
+	OrphanCount int `json:"orphanCount" handlebars:"orphan-count"`
+	// Ratio of notes without any backlink, between 0 and 1.
+	OrphanRatio float64 `json:"orphanRatio" handlebars:"orphan-ratio"`
+	// Average number of links from and to each note.
+	AverageDegree float64 `json:"averageDegree" handlebars:"average-degree"`
+	// Span of time used to group the created and modified notes.
+	Period StatsPeriod `json:"period"`
+	// Number of notes created during each of the most recent periods.
+	Created []StatsPeriodCount `json:"created"`
+	// Number of notes last modified during each of the most recent periods.
+	Modified []StatsPeriodCount `json:"modified"`
+	// Tags attached to the most notes.
+	Tags []StatsTag `json:"tags"`
+	// Notes with the most words.
+	LargestNotes []StatsNote `json:"largestNotes" handlebars:"largest-notes"`
+}
+
+// StatsPeriodCount is the number of notes for a period of time.
+type StatsPeriodCount struct {
+	// Period formatted as 2006-01 for months or 2006-W02 for ISO 8601 weeks.
+	Period    string `json:"period"`
+	NoteCount int    `json:"noteCount" handlebars:"note-count"`
+}
+
+// StatsTag is a tag with the number of notes it is attached to.
+type StatsTag struct {
+	Name      string `json:"name"`
+	NoteCount int    `json:"noteCount" handlebars:"note-count"`
+}
+
+// StatsNote is a note with its number of words.
+type StatsNote struct {
+	Path      string `json:"path"`
+	Title     string `json:"title"`
+	WordCount int    `json:"wordCount" handlebars:"word-count"`
+}
+
+// StatsPeriod is the span of time used to group the notes by date.
+type StatsPeriod string
+
+const (
+	StatsPeriodWeek  StatsPeriod = "week"
+	StatsPeriodMonth StatsPeriod = "month"
+)
+
+// StatsPeriodFromString returns the StatsPeriod matching the given name.
+func StatsPeriodFromString(s string) (StatsPeriod, error) {
+	switch StatsPeriod(s) {
+	case StatsPeriodWeek, StatsPeriodMonth:
+		return StatsPeriod(s), nil
+	default:
+		return "", fmt.Errorf("%s: unknown period, may be week or month", s)
+	}
+}
+
+// StatsOpts holds the options used to compute the statistics of a notebook.
+type StatsOpts struct {
+	// Restrict the statistics to the given notes, or to all of them when nil.
+	NoteIDs []NoteID
+	// Span of time used to group the created and modified notes.
+	Period StatsPeriod
+	// Maximum number of periods, tags and notes listed.
+	Limit int
+}
+
+// StatsFormatter formats the notebook statistics to be printed on the screen.
+type StatsFormatter func(stats NotebookStats) (string, error)
+
+func newStatsFormatter(template Template) (StatsFormatter, error) {
+	return func(stats NotebookStats) (string, error) {
+		return template.Render(stats)
+	}, nil
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"time"
+)
+
+// Task is a Markdown task list item found in a note, e.g. `- [ ] Buy milk`.
+type Task struct {
+	// Text of the task, without the checkbox.
+	Text string `json:"text"`
+	// Indicates whether the task is checked.
+	Done bool `json:"done"`
+	// Line number of the task in the note content, starting from 1.
+	Line int `json:"line"`
+	// Due date set with @due(2026-10-20).
+	Due *time.Time `json:"due"`
+	// Inline tags found in the task text.
+	Tags []string `json:"tags"`
+}
+
+// NoteTask is a task with the note containing it.
+type NoteTask struct {
+	Task
+	// Path of the note relative to the root of the notebook.
+	Path string `json:"path"`
+	// Title of the note.
+	Title string `json:"title"`
+}
+
+// TaskFindOpts holds a set of filtering options used to find tasks.
+type TaskFindOpts struct {
+	// Filter tasks by their completion state, or return all of them when
+	// null.
+	Done *bool
+	// Filter tasks due before the given date.
+	DueBefore *time.Time
+	// Filter tasks due after the given date.
+	DueAfter *time.Time
+	// Filter tasks tagged with all the given tags.
+	Tags []string
+	// Filter tasks found in the given notes, or in all the notes when nil.
+	NoteIDs []NoteID
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"path/filepath"
+)
+
+// TaskFormatter formats tasks to be printed on the screen.
+type TaskFormatter func(task NoteTask) (string, error)
+
+func newTaskFormatter(basePath string, template Template, fs FileStorage) (TaskFormatter, error) {
+	return func(task NoteTask) (string, error) {
+		path := NotebookPath{
+			Path:       task.Path,
+			BasePath:   basePath,
+			WorkingDir: fs.WorkingDir(),
+		}
+		relPath, err := path.PathRelToWorkingDir()
+		if err != nil {
+			return "", err
+		}
+
+		tags := task.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		context := taskFormatRenderContext{
+			Text:    task.Text,
+			Done:    task.Done,
+			Line:    task.Line,
+			Tags:    tags,
+			Path:    relPath,
+			AbsPath: filepath.Join(basePath, task.Path),
+			Title:   task.Title,
+		}
+		if task.Due != nil {
+			context.Due = *task.Due
+		}
+		return template.Render(context)
+	}, nil
+}
+
+// taskFormatRenderContext holds the variables available to the task
+// formatting templates.
+type taskFormatRenderContext struct {
+	// Text of the task, without the checkbox.
+	Text string `json:"text"`
+	// Indicates whether the task is checked.
+	Done bool `json:"done"`
+	// Line number of the task in the note.
+	Line int `json:"line"`
+	// Due date of the task, or nil.
+	Due interface{} `json:"due"`
+	// Inline tags of the task.
+	Tags []string `json:"tags"`
+	// Path of the note, relative to the working directory.
+	Path string `json:"path"`
+	// Absolute path of the note.
+	AbsPath string `json:"absPath" handlebars:"abs-path"`
+	// Title of the note.
+	Title string `json:"title"`
+}
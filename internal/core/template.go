@@ -48,8 +48,8 @@ type TemplateLoader interface {
 }
 
 // TemplateLoaderFactory creates a new instance of an implementation of the
-// TemplateLoader port.
-type TemplateLoaderFactory func(language string) (TemplateLoader, error)
+// TemplateLoader port, for the notes using the given config.
+type TemplateLoaderFactory func(config NoteConfig) (TemplateLoader, error)
 
 // NullTemplateLoader a TemplateLoader always returning a NullTemplate.
 var NullTemplateLoader = nullTemplateLoader{}
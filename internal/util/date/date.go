@@ -1,6 +1,8 @@
 package date
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	naturaldate "github.com/tj/go-naturaldate"
@@ -63,3 +65,14 @@ func TimeFromNatural(date string) (time.Time, error) {
 	}
 	return naturaldate.Parse(date, time.Now(), naturaldate.WithDirection(naturaldate.Past))
 }
+
+// WeekdayFromString returns the day of the week from its English name, e.g.
+// monday.
+func WeekdayFromString(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("%s: unknown day of the week", name)
+}
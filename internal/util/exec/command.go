@@ -0,0 +1,27 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Output runs the program name with the given arguments from the directory
+// dir, without a shell, and returns its standard output. The standard error
+// is reported in the returned error.
+func Output(dir string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("%s: %w", msg, err)
+		}
+		return out, err
+	}
+	return out, nil
+}
@@ -2,6 +2,7 @@ package rand
 
 import (
 	"math/rand"
+	"strings"
 	"time"
 	"unicode"
 
@@ -9,8 +10,27 @@ import (
 )
 
 // NewIDGenerator returns a function generating string IDs using the given options.
-// Inspired by https://www.calhoun.io/creating-random-strings-in-go/
+//
+// The sequential and Folgezettel strategies depend on the notebook content, so
+// they are handled by the core.Notebook directly.
 func NewIDGenerator(options core.IDOptions) func() string {
+	switch options.Strategy {
+	case core.IDStrategyULID:
+		// ULIDs keep their canonical uppercase form.
+		return NewULIDGenerator()
+	case core.IDStrategyUUID:
+		return withCase(options.Case, NewUUIDGenerator())
+	case core.IDStrategyTimestamp:
+		return NewTimestampGenerator(options.Format)
+	default:
+		return NewRandomIDGenerator(options)
+	}
+}
+
+// NewRandomIDGenerator returns a function generating random string IDs using
+// the given length, charset and case.
+// Inspired by https://www.calhoun.io/creating-random-strings-in-go/
+func NewRandomIDGenerator(options core.IDOptions) func() string {
 	if options.Length < 1 {
 		panic("IDOptions.Length must be at least 1")
 	}
@@ -41,3 +61,18 @@ func NewIDGenerator(options core.IDOptions) func() string {
 		return string(buf)
 	}
 }
+
+// withCase changes the letter case of the IDs returned by the given generator.
+// Mixed case keeps the IDs untouched.
+func withCase(c core.Case, generator func() string) func() string {
+	return func() string {
+		switch c {
+		case core.CaseLower:
+			return strings.ToLower(generator())
+		case core.CaseUpper:
+			return strings.ToUpper(generator())
+		default:
+			return generator()
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package rand
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/lestrrat-go/strftime"
+)
+
+// DefaultTimestampFormat is the strftime format used by the timestamp
+// strategy when none is configured.
+const DefaultTimestampFormat = "%Y%m%d%H%M"
+
+// crockfordAlphabet is the Base32 alphabet used to encode ULIDs.
+// See https://www.crockford.com/base32.html
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULIDGenerator returns a function generating lexicographically sortable
+// ULIDs, made of a 48-bit timestamp in milliseconds and 80 random bits.
+// See https://github.com/ulid/spec
+func NewULIDGenerator() func() string {
+	return func() string {
+		return ulid(time.Now())
+	}
+}
+
+func ulid(now time.Time) string {
+	var data [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+	readRandom(data[6:])
+
+	// 128 bits are encoded in 26 characters of 5 bits, the first one holding
+	// only 3 bits.
+	hi := binary.BigEndian.Uint64(data[:8])
+	lo := binary.BigEndian.Uint64(data[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+// NewUUIDGenerator returns a function generating time-ordered UUIDs
+// (version 7).
+// See https://www.rfc-editor.org/rfc/rfc9562#name-uuid-version-7
+func NewUUIDGenerator() func() string {
+	return func() string {
+		return uuidV7(time.Now())
+	}
+}
+
+func uuidV7(now time.Time) string {
+	var data [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+	readRandom(data[6:])
+	data[6] = data[6]&0x0f | 0x70 // Version 7
+	data[8] = data[8]&0x3f | 0x80 // RFC 9562 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:16])
+}
+
+// NewTimestampGenerator returns a function generating IDs from the current
+// date and time, using the given strftime format.
+func NewTimestampGenerator(format string) func() string {
+	if format == "" {
+		format = DefaultTimestampFormat
+	}
+	pattern, err := strftime.New(format, strftime.WithUnixSeconds('s'))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ID format %s: %v", format, err))
+	}
+
+	return func() string {
+		return pattern.FormatString(time.Now())
+	}
+}
+
+func readRandom(buf []byte) {
+	if _, err := cryptorand.Read(buf); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,24 @@
+package rand
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/zk-org/zk/internal/util/test/assert"
+)
+
+func TestULID(t *testing.T) {
+	date := time.UnixMilli(1469918176385)
+	id := ulid(date)
+	assert.Equal(t, len(id), 26)
+	// The timestamp part is taken from the spec example.
+	assert.Equal(t, id[:10], "01ARYZ6S41")
+	assert.NotEqual(t, ulid(date), id)
+}
+
+func TestUUIDv7(t *testing.T) {
+	date := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	id := uuidV7(date)
+	assert.True(t, regexp.MustCompile(`^017f22e2-79b0-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id))
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/alecthomas/kong"
+	"github.com/zk-org/zk/internal/adapter/plugin"
 	"github.com/zk-org/zk/internal/cli"
 	"github.com/zk-org/zk/internal/cli/cmd"
 	"github.com/zk-org/zk/internal/core"
@@ -21,14 +22,26 @@ import (
 var Version = "dev"
 
 var root struct {
-	Init  cmd.Init  `cmd group:"zk" help:"Create a new notebook in the given directory."`
-	Index cmd.Index `cmd group:"zk" help:"Index the notes to be searchable."`
-
-	New   cmd.New   `cmd group:"notes" help:"Create a new note in the given notebook directory."`
-	List  cmd.List  `cmd group:"notes" help:"List notes matching the given criteria."`
-	Graph cmd.Graph `cmd group:"notes" help:"Produce a graph of the notes matching the given criteria."`
-	Edit  cmd.Edit  `cmd group:"notes" help:"Edit notes matching the given criteria."`
-	Tag   cmd.Tag   `cmd group:"notes" help:"Manage the note tags."`
+	Init     cmd.Init     `cmd group:"zk" help:"Create a new notebook in the given directory."`
+	Index    cmd.Index    `cmd group:"zk" help:"Index the notes to be searchable."`
+	Template cmd.Template `cmd group:"zk" help:"List, show or validate the note templates."`
+
+	New        cmd.New        `cmd group:"notes" help:"Create a new note in the given notebook directory."`
+	Journal    cmd.Journal    `cmd group:"notes" help:"Create or edit the journal note of a day, week or month."`
+	List       cmd.List       `cmd group:"notes" help:"List notes matching the given criteria."`
+	Graph      cmd.Graph      `cmd group:"notes" help:"Produce a graph of the notes matching the given criteria."`
+	Edit       cmd.Edit       `cmd group:"notes" help:"Edit notes matching the given criteria."`
+	Link       cmd.Link       `cmd group:"notes" help:"Print links to notes matching the given criteria."`
+	Tag        cmd.Tag        `cmd group:"notes" help:"Manage the note tags."`
+	Meta       cmd.Meta       `cmd group:"notes" help:"Read and edit the frontmatter of the notes."`
+	Tasks      cmd.Tasks      `cmd group:"notes" help:"List the tasks found in the notes matching the given criteria."`
+	Assets     cmd.Assets     `cmd group:"notes" help:"Manage the local files referenced by the notes, e.g. images."`
+	Log        cmd.Log        `cmd group:"notes" help:"Show the git revisions of a note."`
+	Duplicates cmd.Duplicates `cmd group:"notes" help:"Find duplicate notes and merge them."`
+	Fmt        cmd.Fmt        `cmd group:"notes" help:"Normalize the links, tags and frontmatter of the notes."`
+	Lint       cmd.Lint       `cmd group:"notes" help:"Report the issues found in the notes."`
+	Stats      cmd.Stats      `cmd group:"notes" help:"Summarize the notes matching the given criteria."`
+	Review     cmd.Review     `cmd group:"notes" help:"Review the flashcards found in the notes with spaced repetition."`
 
 	NotebookDir string  `type:path placeholder:PATH help:"Turn off notebook auto-discovery and set manually the notebook where commands are run."`
 	WorkingDir  string  `short:W type:path placeholder:PATH help:"Run as if zk was started in <PATH> instead of the current working directory."`
@@ -38,9 +51,10 @@ var root struct {
 	Debug      bool   `default:"0" hidden help:"Print a debug stacktrace on SIGINT."`
 	DebugStyle bool   `default:"0" hidden help:"Force styling output as XML tags."`
 
-	ShowHelp ShowHelp         `cmd hidden default:"1"`
-	LSP      cmd.LSP          `cmd hidden`
-	Version  kong.VersionFlag `hidden help:"Print zk version."`
+	ShowHelp  ShowHelp         `cmd hidden default:"1"`
+	LSP       cmd.LSP          `cmd hidden`
+	FzfReload cmd.FzfReload    `cmd hidden name:"fzf-reload"`
+	Version   kong.VersionFlag `hidden help:"Print zk version."`
 }
 
 // NoInput is a flag preventing any user prompt when enabled.
@@ -81,9 +95,11 @@ func main() {
 	err = container.SetCurrentNotebook(searchDirs)
 	fatalIfError(err)
 
-	// Run the alias or command.
+	// Run the alias, plugin or command.
 	if isAlias, err := runAlias(container, args); isAlias {
 		fatalIfError(err)
+	} else if isPlugin, err := runPlugin(container, args); isPlugin {
+		fatalIfError(err)
 	} else {
 		parser, err := kong.New(&root, options(container)...)
 		fatalIfError(err)
@@ -100,8 +116,9 @@ func main() {
 		container.Terminal.ForceInput = root.ForceInput
 
 		// Index the current notebook except if the user is running the `index`
-		// command, otherwise it would hide the stats.
-		if ctx.Command() != "index" {
+		// command, otherwise it would hide the stats. The notebook is already
+		// indexed when fzf reloads a live search.
+		if ctx.Command() != "index" && !strings.HasPrefix(ctx.Command(), "fzf-reload") {
 			if notebook, err := container.CurrentNotebook(); err == nil {
 				index := cmd.Index{Quiet: true}
 				err = index.RunWithNotebook(container, notebook)
@@ -112,6 +129,8 @@ func main() {
 		err = ctx.Run(container)
 		ctx.FatalIfErrorf(err)
 	}
+
+	container.Close()
 }
 
 func options(container *cli.Container) []kong.Option {
@@ -199,6 +218,65 @@ func runAlias(container *cli.Container, args []string) (bool, error) {
 	return false, nil
 }
 
+// runPlugin will execute the `zk-<name>` plugin if the command is not a
+// native one.
+func runPlugin(container *cli.Container, args []string) (bool, error) {
+	if len(args) < 1 || strings.HasPrefix(args[0], "-") {
+		return false, nil
+	}
+
+	isCommand, err := isNativeCommand(container, args[0])
+	if isCommand || err != nil {
+		return false, err
+	}
+	path, ok := plugin.Lookup(args[0], container.Config.Plugins)
+	if !ok {
+		return false, nil
+	}
+
+	context := plugin.Context{
+		Version:    Version,
+		WorkingDir: container.WorkingDir,
+		Config:     container.Config,
+	}
+	if notebook, err := container.CurrentNotebook(); err == nil {
+		context.NotebookDir = notebook.Path
+	}
+
+	cmd, err := plugin.Command(path, args[1:], context)
+	if err != nil {
+		return true, err
+	}
+	err = cmd.Run()
+	if err != nil {
+		if err, ok := err.(*exec.ExitError); ok {
+			os.Exit(err.ExitCode())
+		} else {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// isNativeCommand returns whether name is one of the zk commands.
+func isNativeCommand(container *cli.Container, name string) (bool, error) {
+	parser, err := kong.New(&root, options(container)...)
+	if err != nil {
+		return false, err
+	}
+	for _, node := range parser.Model.Children {
+		if node.Name == name {
+			return true, nil
+		}
+		for _, alias := range node.Aliases {
+			if alias == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // notebookSearchDirs returns the places where zk will look for a notebook.
 // The first successful candidate will be used as the working directory from
 // which path arguments are relative from.